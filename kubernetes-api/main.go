@@ -1,36 +1,84 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"kubernetes-api/pkg/activity"
 	"kubernetes-api/pkg/handlers"
+	"kubernetes-api/pkg/index"
 	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/logging"
+	"kubernetes-api/pkg/metrics"
+	"kubernetes-api/pkg/middleware"
 	"kubernetes-api/pkg/models"
 
 	"github.com/gin-gonic/gin"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ShutdownTimeoutEnv names the environment variable holding how long in-flight
+// requests may take to finish after SIGINT/SIGTERM, as a Go duration.
+const ShutdownTimeoutEnv = "SHUTDOWN_TIMEOUT"
+
+const defaultShutdownTimeout = 30 * time.Second
+
 func main() {
+	logger := logging.NewFromEnv()
+	slog.SetDefault(logger)
+	metrics.Register()
+
 	// Initialize Kubernetes client
 	k8sClient, err := k8s.NewK8sClient()
 	if err != nil {
-		log.Fatalf("Failed to create Kubernetes client: %v", err)
+		logger.Error("Failed to create Kubernetes client", "error", err)
+		os.Exit(1)
+	}
+
+	token, err := middleware.LoadToken()
+	if err != nil {
+		logger.Error("Failed to load API token", "error", err)
+		os.Exit(1)
+	}
+	if token == "" {
+		logger.Warn("No API token configured, authentication is disabled", "env", middleware.TokenEnv)
+	}
+
+	rateLimiter := middleware.NewRateLimiterFromEnv()
+	if !rateLimiter.Enabled() {
+		logger.Warn("Rate limiting is disabled", "env", middleware.RateEnv)
 	}
 
 	// Initialize handlers
-	podHandler := handlers.NewPodHandler(k8sClient)
-	serviceHandler := handlers.NewServiceHandler(k8sClient)
+	activityLog := activity.NewLogFromEnv()
+	uidIndex := index.NewFromEnv(k8sClient.ClientSet)
+	podHandler := handlers.NewPodHandler(k8sClient, activityLog, uidIndex)
+	serviceHandler := handlers.NewServiceHandler(k8sClient, activityLog, uidIndex)
+	deploymentHandler := handlers.NewDeploymentHandler(k8sClient, activityLog)
+	configMapHandler := handlers.NewConfigMapHandler(k8sClient, activityLog)
+	secretHandler := handlers.NewSecretHandler(k8sClient, activityLog)
+	activityHandler := handlers.NewActivityHandler(activityLog)
+	exportHandler := handlers.NewExportHandler(k8sClient)
+	clusterHandler := handlers.NewClusterHandler(k8sClient)
+	nodeHandler := handlers.NewNodeHandler(k8sClient)
+	applyHandler := handlers.NewApplyHandler(k8sClient, activityLog, uidIndex)
 
 	// Setup Gin router
-	r := gin.Default()
+	r := gin.New()
+	r.Use(middleware.RequestLogger(logger), gin.Recovery(), metrics.Middleware())
 
 	// CORS middleware
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, "+middleware.RequestIDHeader)
+		c.Header("Access-Control-Expose-Headers", middleware.RequestIDHeader)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -40,6 +88,9 @@ func main() {
 		c.Next()
 	})
 
+	r.Use(middleware.BearerAuth(token, "/health", "/ready"))
+	r.Use(rateLimiter.Middleware("/health", "/ready", "/metrics"))
+
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, models.APIResponse{
@@ -48,48 +99,131 @@ func main() {
 		})
 	})
 
+	// Readiness endpoint, fails while the cluster is unreachable
+	r.GET("/ready", clusterHandler.Ready)
+
+	// Prometheus metrics endpoint
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
 	// API versioning
 	v1 := r.Group("/api/v1")
 	{
 		// Pod endpoints - Remove the group and add routes directly
 		v1.POST("/pods", podHandler.CreatePod)
 		v1.GET("/pods", podHandler.ListPods)
+		v1.POST("/pods/batch", podHandler.CreatePodsBatch)
+		v1.POST("/pods/reconcile", podHandler.ReconcilePods)
 		v1.GET("/pods/:uid", podHandler.GetPodByUID)
 		v1.DELETE("/pods/:uid", podHandler.DeletePodByUID)
 		v1.GET("/pods/:uid/logs", podHandler.GetPodLogs)
+		v1.GET("/pods/:uid/restarts", podHandler.GetPodRestarts)
+		v1.PATCH("/pods/:uid/env", podHandler.UpdatePodEnv)
+		v1.PATCH("/pods/:uid/labels", podHandler.UpdatePodLabels)
+		v1.GET("/pods/:uid/scheduling", podHandler.GetPodScheduling)
+		v1.GET("/pods/:uid/events", podHandler.GetPodEvents)
+		v1.GET("/pods/:uid/metrics", podHandler.GetPodMetrics)
+		v1.GET("/pods/:uid/export", podHandler.ExportPod)
+		v1.GET("/pods/:uid/portforward", podHandler.PortForward)
+		v1.POST("/pods/:uid/exec", podHandler.ExecInPod)
+		v1.POST("/pods/:uid/operation", podHandler.PodOperation)
 
 		// Service endpoints - Remove the group and add routes directly
 		v1.POST("/services", serviceHandler.CreateService)
 		v1.GET("/services", serviceHandler.ListServices)
+		v1.GET("/services/:uid", serviceHandler.GetServiceByUID)
+		v1.DELETE("/services/:uid", serviceHandler.DeleteServiceByUID)
+		v1.GET("/services/:uid/probe", serviceHandler.ProbeService)
 
-		// Cluster info endpoint
-		v1.GET("/cluster/info", func(c *gin.Context) {
-			nodes, err := k8sClient.ClientSet.CoreV1().Nodes().List(
-				k8sClient.Context, metav1.ListOptions{})
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, models.APIResponse{
-					Success: false,
-					Error:   err.Error(),
-				})
-				return
-			}
-
-			clusterInfo := map[string]interface{}{
-				"node_count": len(nodes.Items),
-				"nodes":      []string{},
-			}
-
-			for _, node := range nodes.Items {
-				clusterInfo["nodes"] = append(clusterInfo["nodes"].([]string), node.Name)
-			}
-
-			c.JSON(http.StatusOK, models.APIResponse{
-				Success: true,
-				Data:    clusterInfo,
-			})
-		})
+		// Deployment endpoints
+		v1.POST("/deployments", deploymentHandler.CreateDeployment)
+		v1.GET("/deployments", deploymentHandler.ListDeployments)
+		v1.GET("/deployments/:uid", deploymentHandler.GetDeploymentByUID)
+		v1.DELETE("/deployments/:uid", deploymentHandler.DeleteDeploymentByUID)
+		v1.PUT("/deployments/:uid/scale", deploymentHandler.ScaleDeployment)
+
+		// ConfigMap endpoints
+		v1.POST("/configmaps", configMapHandler.CreateConfigMap)
+		v1.GET("/configmaps/:uid", configMapHandler.GetConfigMapByUID)
+
+		// Secret endpoints
+		v1.POST("/secrets", secretHandler.CreateSecret)
+
+		// Manifest endpoint
+		v1.POST("/apply", applyHandler.ApplyManifest)
+
+		// Activity endpoint
+		v1.GET("/activity", activityHandler.GetRecentActivity)
+
+		// Export endpoint
+		v1.GET("/export", exportHandler.ExportResources)
+
+		// Cluster endpoints
+		v1.GET("/cluster/capabilities", clusterHandler.GetCapabilities)
+		v1.GET("/cluster/info", nodeHandler.GetClusterInfo)
+
+		// Node endpoints
+		v1.GET("/nodes", nodeHandler.ListNodes)
+		v1.GET("/nodes/:name", nodeHandler.GetNode)
 	}
 
-	log.Println("Starting Kubernetes API server on :8080")
-	log.Fatal(r.Run(":8080"))
+	server := &http.Server{
+		Addr:    ":8080",
+		Handler: r,
+	}
+
+	ln, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		logger.Error("Failed to listen", "addr", server.Addr, "error", err)
+		os.Exit(1)
+	}
+	if err := serve(logger, server, ln, shutdownTimeout(), syscall.SIGINT, syscall.SIGTERM); err != nil {
+		logger.Error("Server stopped", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("Server stopped")
+}
+
+// serve runs server on ln until one of signals arrives, then gives in-flight
+// requests gracePeriod to finish before closing the remaining connections.
+func serve(logger *slog.Logger, server *http.Server, ln net.Listener, gracePeriod time.Duration, signals ...os.Signal) error {
+	ctx, stop := signal.NotifyContext(context.Background(), signals...)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("Starting Kubernetes API server", "addr", ln.Addr().String())
+		serverErr <- server.Serve(ln)
+	}()
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	logger.Info("Shutting down, draining in-flight requests", "grace_period", gracePeriod.String())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		// Requests still running after the grace period (e.g. followed
+		// log streams) are cut off
+		logger.Warn("Grace period expired, closing remaining connections", "error", err)
+		server.Close()
+	}
+	if err := <-serverErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// shutdownTimeout reads ShutdownTimeoutEnv, falling back to
+// defaultShutdownTimeout when it is unset or not a positive duration.
+func shutdownTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv(ShutdownTimeoutEnv))
+	if err != nil || timeout <= 0 {
+		return defaultShutdownTimeout
+	}
+	return timeout
 }
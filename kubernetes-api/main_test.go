@@ -0,0 +1,111 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// startServer runs handler through serve on a local port with the given grace
+// period, returning the server URL and serve's result.
+func startServer(t *testing.T, handler http.Handler, gracePeriod time.Duration) (string, <-chan error) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(slog.New(slog.NewTextHandler(io.Discard, nil)), &http.Server{Handler: handler}, ln, gracePeriod, syscall.SIGTERM)
+	}()
+	return "http://" + ln.Addr().String(), done
+}
+
+// slowHandler signals started once a request arrives, then answers after delay.
+func slowHandler(started chan<- struct{}, delay time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(delay)
+		io.WriteString(w, "done")
+	})
+}
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	url, done := startServer(t, slowHandler(started, 300*time.Millisecond), 5*time.Second)
+
+	type result struct {
+		body string
+		err  error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		response <- result{string(body), err}
+	}()
+
+	<-started
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-response
+	if got.err != nil || got.body != "done" {
+		t.Errorf("in-flight request returned %q, %v; want it to complete", got.body, got.err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serve() = %v, want nil after a graceful shutdown", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve() did not return after the signal")
+	}
+
+	if _, err := http.Get(url); err == nil {
+		t.Error("server still accepts requests after shutting down")
+	}
+}
+
+func TestServeCutsOffRequestsAfterGracePeriod(t *testing.T) {
+	started := make(chan struct{})
+	url, done := startServer(t, slowHandler(started, 5*time.Second), 100*time.Millisecond)
+
+	response := make(chan error, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err == nil {
+			_, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+		response <- err
+	}()
+
+	<-started
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serve() = %v, want nil once the grace period expires", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("serve() waited past the grace period")
+	}
+	if err := <-response; err == nil {
+		t.Error("request outliving the grace period completed, want it cut off")
+	}
+}
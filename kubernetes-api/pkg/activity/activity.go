@@ -0,0 +1,85 @@
+package activity
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultSize is the number of operations kept when no size is configured.
+const DefaultSize = 100
+
+// SizeEnv names the environment variable overriding the buffer size.
+const SizeEnv = "ACTIVITY_LOG_SIZE"
+
+type Entry struct {
+	Action    string    `json:"action"` // create, delete
+	Kind      string    `json:"kind"`
+	UID       string    `json:"uid"`
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Log is a fixed-size ring buffer of the create/delete operations performed
+// by the API. Once full, the oldest entries are overwritten.
+type Log struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+func NewLog(size int) *Log {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Log{entries: make([]Entry, size)}
+}
+
+// NewLogFromEnv creates a log sized by SizeEnv, falling back to DefaultSize
+// when it is unset or not a positive integer.
+func NewLogFromEnv() *Log {
+	size, _ := strconv.Atoi(os.Getenv(SizeEnv))
+	return NewLog(size)
+}
+
+func (l *Log) Record(action, kind, uid, name, namespace string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[l.next] = Entry{
+		Action:    action,
+		Kind:      kind,
+		UID:       uid,
+		Name:      name,
+		Namespace: namespace,
+		Timestamp: time.Now(),
+	}
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Recent returns up to limit entries, newest first. A limit of zero or less
+// returns everything in the buffer.
+func (l *Log) Recent(limit int) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.entries)
+	}
+	if limit > 0 && limit < count {
+		count = limit
+	}
+
+	recent := make([]Entry, 0, count)
+	for i := 1; i <= count; i++ {
+		recent = append(recent, l.entries[(l.next-i+len(l.entries))%len(l.entries)])
+	}
+	return recent
+}
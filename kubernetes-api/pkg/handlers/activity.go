@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ActivityHandler struct {
+	activity *activity.Log
+}
+
+func NewActivityHandler(activityLog *activity.Log) *ActivityHandler {
+	return &ActivityHandler{activity: activityLog}
+}
+
+// GetRecentActivity lists the most recent create/delete operations, newest first.
+func (h *ActivityHandler) GetRecentActivity(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil || limit < 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid limit %q", c.Query("limit")),
+		})
+		return
+	}
+
+	entries := h.activity.Recent(limit)
+	items := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, entry)
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: models.ListResponse{
+			Items: items,
+			Count: len(items),
+		},
+	})
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/index"
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// maxManifestSize bounds the request body ApplyManifest reads.
+const maxManifestSize = 1 << 20
+
+// ApplyHandler creates objects from raw Kubernetes manifests. It reuses the
+// per-kind handlers to generate UIDs, so applied objects are managed exactly
+// like ones created through the typed endpoints.
+type ApplyHandler struct {
+	k8sClient   *k8s.K8sClient
+	activity    *activity.Log
+	uidIndex    *index.Index
+	pods        *PodHandler
+	services    *ServiceHandler
+	deployments *DeploymentHandler
+	configMaps  *ConfigMapHandler
+}
+
+func NewApplyHandler(client *k8s.K8sClient, activityLog *activity.Log, uidIndex *index.Index) *ApplyHandler {
+	return &ApplyHandler{
+		k8sClient:   client,
+		activity:    activityLog,
+		uidIndex:    uidIndex,
+		pods:        NewPodHandler(client, activityLog, uidIndex),
+		services:    NewServiceHandler(client, activityLog, uidIndex),
+		deployments: NewDeploymentHandler(client, activityLog),
+		configMaps:  NewConfigMapHandler(client, activityLog),
+	}
+}
+
+// manifest is one decoded document of an applied manifest.
+type manifest struct {
+	obj  runtime.Object
+	kind string
+	meta *metav1.ObjectMeta
+}
+
+// ApplyManifest creates the Pods, Services, Deployments and ConfigMaps
+// described by a YAML or JSON manifest, each labelled with a generated uid.
+// Multi-document YAML, such as an ExportResources bundle, is applied in
+// order; every document is validated before anything is created. An object
+// is created in its manifest namespace or, failing that, in ?namespace=, and
+// in "default" when neither is set. When both are set they must match, or the
+// request is rejected with a 400.
+func (h *ApplyHandler) ApplyManifest(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxManifestSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to read manifest: %v", err),
+		})
+		return
+	}
+
+	docs, err := splitManifest(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid manifest: %v", err),
+		})
+		return
+	}
+	if len(docs) == 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Manifest is empty",
+		})
+		return
+	}
+
+	// documentError prefixes err with its document number in a bundle
+	documentError := func(i int, err error) string {
+		if len(docs) == 1 {
+			return err.Error()
+		}
+		return fmt.Sprintf("document %d: %v", i+1, err)
+	}
+
+	manifests := make([]manifest, 0, len(docs))
+	for i, doc := range docs {
+		m, err := decodeManifest(doc, c.Query("namespace"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   documentError(i, err),
+			})
+			return
+		}
+		manifests = append(manifests, m)
+	}
+
+	applied := make([]models.ApplyResponse, 0, len(manifests))
+	for i, m := range manifests {
+		uid, created, err := h.create(m.obj, m.meta)
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case apierrors.IsAlreadyExists(err):
+				status = http.StatusConflict
+			case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+				status = http.StatusBadRequest
+			}
+			response := models.APIResponse{
+				Success: false,
+				Error:   documentError(i, err),
+			}
+			// Report what earlier documents already created
+			if len(applied) > 0 {
+				response.Data = models.ApplyBundleResponse{Items: applied}
+			}
+			c.JSON(status, response)
+			return
+		}
+
+		h.activity.Record("create", m.kind, uid, created.Name, created.Namespace)
+		if m.kind == "Pod" || m.kind == "Service" {
+			recordUID(h.k8sClient, h.uidIndex, m.kind, uid, created.Namespace, created.Name)
+		}
+		applied = append(applied, models.ApplyResponse{
+			Kind:      m.kind,
+			UID:       uid,
+			Name:      created.Name,
+			Namespace: created.Namespace,
+		})
+	}
+
+	if len(applied) == 1 {
+		c.JSON(http.StatusCreated, models.APIResponse{
+			Success: true,
+			Message: fmt.Sprintf("%s created successfully", applied[0].Kind),
+			Data:    applied[0],
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("%d objects created successfully", len(applied)),
+		Data:    models.ApplyBundleResponse{Items: applied},
+	})
+}
+
+// splitManifest splits a YAML stream into its non-empty documents. JSON is
+// returned as a single document.
+func splitManifest(body []byte) ([][]byte, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(body)))
+	var docs [][]byte
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) > 0 {
+			docs = append(docs, doc)
+		}
+	}
+}
+
+// decodeManifest decodes one document and resolves its namespace against the
+// ?namespace= value query.
+func decodeManifest(doc []byte, query string) (manifest, error) {
+	obj, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(doc, nil, nil)
+	if err != nil {
+		return manifest{}, fmt.Errorf("Invalid manifest: %v", err)
+	}
+
+	var meta *metav1.ObjectMeta
+	switch typed := obj.(type) {
+	case *corev1.Pod:
+		meta = &typed.ObjectMeta
+	case *corev1.Service:
+		meta = &typed.ObjectMeta
+	case *appsv1.Deployment:
+		meta = &typed.ObjectMeta
+	case *corev1.ConfigMap:
+		meta = &typed.ObjectMeta
+	default:
+		return manifest{}, fmt.Errorf("Unsupported kind %s: must be a v1 Pod, Service or ConfigMap, or an apps/v1 Deployment", gvk.Kind)
+	}
+
+	if meta.Name == "" && meta.GenerateName == "" {
+		return manifest{}, fmt.Errorf("Manifest needs metadata.name or metadata.generateName")
+	}
+
+	namespace := meta.Namespace
+	if namespace == "" {
+		namespace = query
+	} else if query != "" && query != namespace {
+		return manifest{}, fmt.Errorf("Manifest namespace %q does not match ?namespace=%s", namespace, query)
+	}
+	namespace, err = resolveNamespace(namespace)
+	if err != nil {
+		return manifest{}, err
+	}
+	meta.Namespace = namespace
+
+	return manifest{obj: obj, kind: gvk.Kind, meta: meta}, nil
+}
+
+// create labels obj, whose metadata is meta, with a fresh UID and creates it,
+// returning the UID and the created object's metadata.
+func (h *ApplyHandler) create(obj runtime.Object, meta *metav1.ObjectMeta) (string, *metav1.ObjectMeta, error) {
+	ctx := h.k8sClient.Context
+	client := h.k8sClient.ClientSet
+
+	var uid string
+	var err error
+	switch obj.(type) {
+	case *corev1.Pod:
+		uid, err = h.pods.generatePodUID()
+	case *corev1.Service:
+		uid, err = h.services.generateServiceUID()
+	case *appsv1.Deployment:
+		uid, err = h.deployments.generateDeploymentUID()
+	case *corev1.ConfigMap:
+		uid, err = h.configMaps.generateConfigMapUID()
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	// A uid label in the manifest is replaced, so UIDs stay unique
+	if meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	meta.Labels["uid"] = uid
+
+	switch typed := obj.(type) {
+	case *corev1.Pod:
+		if _, ok := typed.Labels["app"]; !ok && typed.Name != "" {
+			typed.Labels["app"] = typed.Name
+		}
+		created, err := client.CoreV1().Pods(meta.Namespace).Create(ctx, typed, metav1.CreateOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return uid, &created.ObjectMeta, nil
+	case *corev1.Service:
+		created, err := client.CoreV1().Services(meta.Namespace).Create(ctx, typed, metav1.CreateOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return uid, &created.ObjectMeta, nil
+	case *appsv1.Deployment:
+		created, err := client.AppsV1().Deployments(meta.Namespace).Create(ctx, typed, metav1.CreateOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return uid, &created.ObjectMeta, nil
+	case *corev1.ConfigMap:
+		created, err := client.CoreV1().ConfigMaps(meta.Namespace).Create(ctx, typed, metav1.CreateOptions{})
+		if err != nil {
+			return "", nil, err
+		}
+		return uid, &created.ObjectMeta, nil
+	}
+	return "", nil, fmt.Errorf("unsupported object %T", obj)
+}
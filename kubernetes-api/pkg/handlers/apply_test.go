@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/index"
+	"kubernetes-api/pkg/models"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTestApplyHandler creates an apply handler backed by an empty fake clientset.
+func newTestApplyHandler() *ApplyHandler {
+	client := newTestClient()
+	return NewApplyHandler(client, activity.NewLog(10), index.New(client.ClientSet, ""))
+}
+
+func TestApplyManifestPod(t *testing.T) {
+	h := newTestApplyHandler()
+
+	manifest := `apiVersion: v1
+kind: Pod
+metadata:
+  name: debug-shell
+  labels:
+    uid: chosen-by-client
+spec:
+  containers:
+  - name: shell
+    image: busybox
+    command: ["sleep", "infinity"]
+`
+	rec := serve(h.ApplyManifest, http.MethodPost, "/apply", "/apply?namespace=team-a", manifest)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("ApplyManifest returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var data models.ApplyResponse
+	resp := decodeResponse(t, rec, &data)
+	if resp.Message != "Pod created successfully" || data.Kind != "Pod" || data.Name != "debug-shell" || data.Namespace != "team-a" {
+		t.Errorf("ApplyManifest = %q, %+v, want Pod debug-shell in team-a", resp.Message, data)
+	}
+
+	pod, err := h.k8sClient.ClientSet.CoreV1().Pods("team-a").Get(h.k8sClient.Context, "debug-shell", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.UID == "" || data.UID == "chosen-by-client" || pod.Labels["uid"] != data.UID {
+		t.Errorf("uid label = %q, response UID = %q, want a generated UID replacing the manifest's", pod.Labels["uid"], data.UID)
+	}
+	if pod.Labels["app"] != "debug-shell" {
+		t.Errorf("app label = %q, want the pod name", pod.Labels["app"])
+	}
+	if got := pod.Spec.Containers[0].Command; strings.Join(got, " ") != "sleep infinity" {
+		t.Errorf("command = %v, want the manifest's", got)
+	}
+}
+
+func TestApplyManifestDefaultNamespace(t *testing.T) {
+	h := newTestApplyHandler()
+
+	manifest := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings"},"data":{"mode":"fast"}}`
+	rec := serve(h.ApplyManifest, http.MethodPost, "/apply", "/apply", manifest)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("ApplyManifest returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, err := h.k8sClient.ClientSet.CoreV1().ConfigMaps("default").Get(h.k8sClient.Context, "settings", metav1.GetOptions{}); err != nil {
+		t.Errorf("config map not created in default: %v", err)
+	}
+}
+
+func TestApplyManifestRejected(t *testing.T) {
+	pod := func(metadata string) string {
+		return "apiVersion: v1\nkind: Pod\nmetadata:\n" + metadata + "spec:\n  containers:\n  - name: app\n    image: nginx\n"
+	}
+
+	tests := []struct {
+		name     string
+		target   string
+		manifest string
+		want     string
+	}{
+		{"empty", "/apply", "  \n", "Manifest is empty"},
+		{"malformed", "/apply", "apiVersion: v1\nkind: Pod\nmetadata: [", "Invalid manifest"},
+		{"no kind", "/apply", `{"metadata":{"name":"web"}}`, "Invalid manifest"},
+		{"unsupported kind", "/apply", "apiVersion: v1\nkind: Secret\nmetadata:\n  name: creds\n", "Unsupported kind Secret"},
+		{"no name", "/apply", pod("  labels:\n    app: web\n"), "metadata.name"},
+		{"namespace mismatch", "/apply?namespace=team-b", pod("  name: web\n  namespace: team-a\n"), "does not match"},
+		{"invalid namespace", "/apply?namespace=Team_A", pod("  name: web\n"), "invalid namespace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestApplyHandler()
+			rec := serve(h.ApplyManifest, http.MethodPost, "/apply", tt.target, tt.manifest)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("ApplyManifest returned %d, want 400: %s", rec.Code, rec.Body.String())
+			}
+			if resp := decodeResponse(t, rec, nil); !strings.Contains(resp.Error, tt.want) {
+				t.Errorf("error = %q, want it to mention %q", resp.Error, tt.want)
+			}
+
+			pods, err := h.k8sClient.ClientSet.CoreV1().Pods(metav1.NamespaceAll).List(h.k8sClient.Context, metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pods.Items) != 0 {
+				t.Errorf("%d pods created from a rejected manifest", len(pods.Items))
+			}
+		})
+	}
+}
+
+func TestApplyManifestBundleValidatesFirst(t *testing.T) {
+	h := newTestApplyHandler()
+	client := h.k8sClient
+
+	bundle := `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+`
+	rec := serve(h.ApplyManifest, http.MethodPost, "/apply", "/apply", bundle)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body.String())
+	}
+	if resp := decodeResponse(t, rec, nil); !strings.HasPrefix(resp.Error, "document 2: ") {
+		t.Errorf("error = %q, want it to name document 2", resp.Error)
+	}
+
+	configMaps, err := client.ClientSet.CoreV1().ConfigMaps("default").List(client.Context, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configMaps.Items) != 0 {
+		t.Errorf("created %d config maps from a rejected bundle", len(configMaps.Items))
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+)
+
+// capabilitiesTTL is how long discovered cluster capabilities are reused.
+const capabilitiesTTL = 5 * time.Minute
+
+// optionalFeatures maps feature names to the API resource that signals them,
+// as "<group/version>:<resource>".
+var optionalFeatures = map[string]string{
+	"metrics":              "metrics.k8s.io/v1beta1:pods",
+	"ephemeral_containers": "v1:pods/ephemeralcontainers",
+	"pod_eviction":         "v1:pods/eviction",
+	"deployments":          "apps/v1:deployments",
+	"deployment_scale":     "apps/v1:deployments/scale",
+}
+
+// readyTimeout bounds the cluster call made by Ready, so an unresponsive API
+// server fails the probe instead of hanging it.
+const readyTimeout = 5 * time.Second
+
+type ClusterHandler struct {
+	k8sClient *k8s.K8sClient
+
+	mu           sync.Mutex
+	capabilities *models.ClusterCapabilitiesResponse
+}
+
+func NewClusterHandler(client *k8s.K8sClient) *ClusterHandler {
+	return &ClusterHandler{k8sClient: client}
+}
+
+func (h *ClusterHandler) GetCapabilities(c *gin.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	refresh := c.Query("refresh") == "true"
+	if !refresh && h.capabilities != nil && time.Since(h.capabilities.CachedAt) < capabilitiesTTL {
+		c.JSON(http.StatusOK, models.APIResponse{
+			Success: true,
+			Data:    h.capabilities,
+		})
+		return
+	}
+
+	capabilities, err := h.discoverCapabilities()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	h.capabilities = capabilities
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    capabilities,
+	})
+}
+
+// Ready reports whether the Kubernetes API server can be reached, by fetching
+// its version. Unlike /health it returns 503 when the cluster is unavailable.
+func (h *ClusterHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+	defer cancel()
+
+	body, err := h.k8sClient.ClientSet.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Kubernetes API unreachable: %v", err),
+		})
+		return
+	}
+
+	var info version.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid Kubernetes version response: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "API is ready",
+		Data: models.ReadyResponse{
+			ServerVersion: info.GitVersion,
+		},
+	})
+}
+
+func (h *ClusterHandler) discoverCapabilities() (*models.ClusterCapabilitiesResponse, error) {
+	client := h.k8sClient.ClientSet.Discovery()
+
+	version, err := client.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	// An unavailable aggregated API (e.g. a broken metrics-server) only fails
+	// its own group, so keep whatever else was discovered
+	_, resourceLists, err := client.ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, err
+	}
+
+	resources := make(map[string]bool)
+	var apiVersions []string
+	for _, list := range resourceLists {
+		apiVersions = append(apiVersions, list.GroupVersion)
+		for _, resource := range list.APIResources {
+			resources[list.GroupVersion+":"+resource.Name] = true
+		}
+	}
+	slices.Sort(apiVersions)
+
+	features := make(map[string]bool)
+	for feature, resource := range optionalFeatures {
+		features[feature] = resources[resource]
+	}
+
+	return &models.ClusterCapabilitiesResponse{
+		ServerVersion: version.GitVersion,
+		APIVersions:   apiVersions,
+		Features:      features,
+		CachedAt:      time.Now(),
+	}, nil
+}
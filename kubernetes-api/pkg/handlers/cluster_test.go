@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// newClusterHandlerFor returns a cluster handler whose client talks to an API
+// server answering every request with handler.
+func newClusterHandlerFor(t *testing.T, handler http.HandlerFunc) *ClusterHandler {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	clientSet, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClusterHandler(&k8s.K8sClient{ClientSet: clientSet, Context: context.Background()})
+}
+
+func TestReady(t *testing.T) {
+	h := newClusterHandlerFor(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"major":"1","minor":"33","gitVersion":"v1.33.3"}`))
+	})
+
+	rec := serve(h.Ready, http.MethodGet, "/ready", "/ready", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Ready returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var ready models.ReadyResponse
+	decodeResponse(t, rec, &ready)
+	if ready.ServerVersion != "v1.33.3" {
+		t.Errorf("Ready() server version = %q, want v1.33.3", ready.ServerVersion)
+	}
+}
+
+func TestReadyClusterUnavailable(t *testing.T) {
+	h := newClusterHandlerFor(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "etcd unavailable", http.StatusInternalServerError)
+	})
+
+	rec := serve(h.Ready, http.MethodGet, "/ready", "/ready", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Ready with a failing cluster returned %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if resp := decodeResponse(t, rec, nil); resp.Success || !strings.Contains(resp.Error, "unreachable") {
+		t.Errorf("Ready() = %+v, want an unreachable error", resp)
+	}
+}
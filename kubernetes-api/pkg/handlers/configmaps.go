@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+	"kubernetes-api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+type ConfigMapHandler struct {
+	k8sClient *k8s.K8sClient
+	activity  *activity.Log
+}
+
+func NewConfigMapHandler(client *k8s.K8sClient, activityLog *activity.Log) *ConfigMapHandler {
+	return &ConfigMapHandler{k8sClient: client, activity: activityLog}
+}
+
+func (h *ConfigMapHandler) CreateConfigMap(c *gin.Context) {
+	var req models.CreateConfigMapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	namespace, err := resolveNamespace(req.Namespace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Name is required",
+		})
+		return
+	}
+	for key := range req.Data {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Invalid key %q: %s", key, strings.Join(errs, ", ")),
+			})
+			return
+		}
+	}
+
+	uid, err := h.generateConfigMapUID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: utils.GeneratePodName(utils.SanitizeName(req.Name)),
+			Labels: map[string]string{
+				"app": req.Name,
+				"uid": uid,
+			},
+		},
+		Data: req.Data,
+	}
+
+	createdConfigMap, err := h.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).Create(
+		h.k8sClient.Context, configMap, metav1.CreateOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	h.activity.Record("create", "ConfigMap", uid, createdConfigMap.Name, createdConfigMap.Namespace)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "ConfigMap created successfully",
+		Data:    configMapResponse(createdConfigMap),
+	})
+}
+
+func (h *ConfigMapHandler) GetConfigMapByUID(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	configMaps, err := h.k8sClient.ClientSet.CoreV1().ConfigMaps(namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			LabelSelector: "uid=" + uid,
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(configMaps.Items) == 0 {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "ConfigMap not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    configMapResponse(&configMaps.Items[0]),
+	})
+}
+
+func configMapResponse(configMap *corev1.ConfigMap) models.ConfigMapResponse {
+	return models.ConfigMapResponse{
+		UID:       configMap.Labels["uid"],
+		Name:      configMap.Name,
+		Namespace: configMap.Namespace,
+		Data:      configMap.Data,
+		CreatedAt: configMap.CreationTimestamp.Time,
+	}
+}
+
+func (h *ConfigMapHandler) generateConfigMapUID() (string, error) {
+	return generateFreeUID(func(opts metav1.ListOptions) (bool, error) {
+		configMaps, err := h.k8sClient.ClientSet.CoreV1().ConfigMaps(metav1.NamespaceAll).List(h.k8sClient.Context, opts)
+		if err != nil {
+			return false, err
+		}
+		return len(configMaps.Items) > 0, nil
+	})
+}
+
+// validateConfigMapRefs checks that each reference names a distinct, well
+// formed UID and a way to use the ConfigMap, without looking anything up in
+// the cluster. The UID goes into a label selector and a volume name, so it
+// must be valid in both.
+func validateConfigMapRefs(refs []models.ConfigMapRef) error {
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if ref.UID == "" {
+			return fmt.Errorf("config_map_refs: uid is required")
+		}
+		if errs := validation.IsValidLabelValue(ref.UID); len(errs) > 0 {
+			return fmt.Errorf("config_map_refs: invalid uid %q: %s", ref.UID, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsDNS1123Label(configMapVolumeName(ref.UID)); len(errs) > 0 {
+			return fmt.Errorf("config_map_refs: invalid uid %q: %s", ref.UID, strings.Join(errs, ", "))
+		}
+		if seen[ref.UID] {
+			return fmt.Errorf("config map %s is referenced more than once; set mount_path and env_from on one reference", ref.UID)
+		}
+		seen[ref.UID] = true
+		if ref.MountPath == "" && !ref.EnvFrom {
+			return fmt.Errorf("config map %s: set mount_path, env_from or both", ref.UID)
+		}
+		if ref.MountPath != "" && !path.IsAbs(ref.MountPath) {
+			return fmt.Errorf("config map %s: mount_path %q must be absolute", ref.UID, ref.MountPath)
+		}
+	}
+	return nil
+}
+
+// configMapVolumeName returns the name of the volume a referenced ConfigMap
+// is mounted from.
+func configMapVolumeName(uid string) string {
+	return "configmap-" + uid
+}
+
+var errConfigMapNotFound = errors.New("config map not found")
+
+// resolveConfigMaps maps each referenced ConfigMap UID to its name in
+// namespace, failing if one does not exist.
+func resolveConfigMaps(client *k8s.K8sClient, namespace string, refs []models.ConfigMapRef) (map[string]string, error) {
+	names := make(map[string]string)
+	for _, ref := range refs {
+		if _, ok := names[ref.UID]; ok {
+			continue
+		}
+
+		configMaps, err := client.ClientSet.CoreV1().ConfigMaps(namespace).List(
+			client.Context, metav1.ListOptions{
+				LabelSelector: "uid=" + ref.UID,
+			})
+		if err != nil {
+			return nil, err
+		}
+		if len(configMaps.Items) == 0 {
+			return nil, fmt.Errorf("%w: %s in namespace %s", errConfigMapNotFound, ref.UID, namespace)
+		}
+		names[ref.UID] = configMaps.Items[0].Name
+	}
+	return names, nil
+}
+
+// attachConfigMaps mounts and/or injects the referenced ConfigMaps into every
+// container of pod. names maps ConfigMap UIDs to names, as returned by
+// resolveConfigMaps.
+func attachConfigMaps(pod *corev1.Pod, refs []models.ConfigMapRef, names map[string]string) {
+	for _, ref := range refs {
+		name := names[ref.UID]
+
+		if ref.MountPath != "" {
+			volume := configMapVolumeName(ref.UID)
+			pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+				Name: volume,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: name},
+					},
+				},
+			})
+			for i := range pod.Spec.Containers {
+				pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+					Name:      volume,
+					MountPath: ref.MountPath,
+					ReadOnly:  true,
+				})
+			}
+		}
+
+		if ref.EnvFrom {
+			for i := range pod.Spec.Containers {
+				pod.Spec.Containers[i].EnvFrom = append(pod.Spec.Containers[i].EnvFrom, corev1.EnvFromSource{
+					ConfigMapRef: &corev1.ConfigMapEnvSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: name},
+					},
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/index"
+	"kubernetes-api/pkg/models"
+)
+
+// createConfigMap creates a ConfigMap from body through CreateConfigMap and returns the response.
+func createConfigMap(t *testing.T, h *ConfigMapHandler, body string) models.ConfigMapResponse {
+	t.Helper()
+	rec := serve(h.CreateConfigMap, http.MethodPost, "/configmaps", "/configmaps", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("CreateConfigMap returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var created models.ConfigMapResponse
+	decodeResponse(t, rec, &created)
+	return created
+}
+
+func TestCreateAndGetConfigMap(t *testing.T) {
+	h := NewConfigMapHandler(newTestClient(), activity.NewLog(10))
+
+	created := createConfigMap(t, h, `{"name":"app-config","data":{"LOG_LEVEL":"debug","app.yaml":"port: 80"}}`)
+	if created.UID == "" || created.Namespace != "default" || created.Data["LOG_LEVEL"] != "debug" {
+		t.Errorf("CreateConfigMap() = %+v, want a UID, the default namespace and the data", created)
+	}
+
+	rec := serve(h.GetConfigMapByUID, http.MethodGet, "/configmaps/:uid", "/configmaps/"+created.UID, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GetConfigMapByUID returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var got models.ConfigMapResponse
+	decodeResponse(t, rec, &got)
+	if got.Name != created.Name || got.Data["app.yaml"] != "port: 80" {
+		t.Errorf("GetConfigMapByUID() = %+v, want the created ConfigMap", got)
+	}
+
+	if rec := serve(h.GetConfigMapByUID, http.MethodGet, "/configmaps/:uid", "/configmaps/missing", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("GetConfigMapByUID(missing) returned %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestCreateConfigMapInvalid(t *testing.T) {
+	h := NewConfigMapHandler(newTestClient(), activity.NewLog(10))
+
+	for _, body := range []string{
+		`{"data":{"key":"value"}}`,
+		`{"name":"app-config","data":{"bad key!":"value"}}`,
+		`{"name":"app-config","namespace":"Bad_NS"}`,
+	} {
+		if rec := serve(h.CreateConfigMap, http.MethodPost, "/configmaps", "/configmaps", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("CreateConfigMap(%s) returned %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestCreatePodWithConfigMapRefs(t *testing.T) {
+	client := newTestClient()
+	configMaps := NewConfigMapHandler(client, activity.NewLog(10))
+	pods := NewPodHandler(client, activity.NewLog(10), index.New(client.ClientSet, ""))
+
+	cm := createConfigMap(t, configMaps, `{"name":"app-config","data":{"LOG_LEVEL":"debug"}}`)
+	pod := createPod(t, pods, `{"name":"web","image":"nginx","container_name":"nginx",
+		"config_map_refs":[{"uid":"`+cm.UID+`","mount_path":"/etc/app","env_from":true}]}`)
+
+	if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].ConfigMap == nil || pod.Spec.Volumes[0].ConfigMap.Name != cm.Name {
+		t.Fatalf("pod volumes = %+v, want the ConfigMap %s", pod.Spec.Volumes, cm.Name)
+	}
+	container := pod.Spec.Containers[0]
+	if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != "/etc/app" ||
+		container.VolumeMounts[0].Name != pod.Spec.Volumes[0].Name || !container.VolumeMounts[0].ReadOnly {
+		t.Errorf("container mounts = %+v, want the ConfigMap read-only at /etc/app", container.VolumeMounts)
+	}
+	if len(container.EnvFrom) != 1 || container.EnvFrom[0].ConfigMapRef == nil || container.EnvFrom[0].ConfigMapRef.Name != cm.Name {
+		t.Errorf("container envFrom = %+v, want the ConfigMap %s", container.EnvFrom, cm.Name)
+	}
+}
+
+func TestCreatePodWithInvalidConfigMapRefs(t *testing.T) {
+	pods := newTestPodHandler()
+
+	for _, refs := range []string{
+		`[{"uid":"missing","env_from":true}]`,
+		`[{"uid":""}]`,
+		`[{"uid":"abc","mount_path":"relative/path"}]`,
+		// UIDs end up in label selectors and volume names
+		`[{"uid":"abc,uid!=def","env_from":true}]`,
+		`[{"uid":"ABC","mount_path":"/etc/app"}]`,
+		`[{"uid":"-abc","env_from":true}]`,
+		`[{"uid":"` + strings.Repeat("a", 54) + `","env_from":true}]`,
+	} {
+		body := `{"name":"web","image":"nginx","container_name":"nginx","config_map_refs":` + refs + `}`
+		if code := createPodStatus(pods, body); code != http.StatusBadRequest {
+			t.Errorf("CreatePod with config_map_refs %s returned %d, want %d", refs, code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestCreatePodWithDuplicateConfigMapRefs(t *testing.T) {
+	client := newTestClient()
+	configMaps := NewConfigMapHandler(client, activity.NewLog(10))
+	pods := NewPodHandler(client, activity.NewLog(10), index.New(client.ClientSet, ""))
+
+	cm := createConfigMap(t, configMaps, `{"name":"app-config","data":{"LOG_LEVEL":"debug"}}`)
+	body := `{"name":"web","image":"nginx","config_map_refs":[
+		{"uid":"` + cm.UID + `","mount_path":"/etc/app"},
+		{"uid":"` + cm.UID + `","env_from":true}]}`
+	rec := serve(pods.CreatePod, http.MethodPost, "/pods", "/pods", body)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "more than once") {
+		t.Errorf("CreatePod with a ConfigMap referenced twice returned %d, want 400: %s", rec.Code, rec.Body.String())
+	}
+}
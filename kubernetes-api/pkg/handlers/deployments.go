@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+	"kubernetes-api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type DeploymentHandler struct {
+	k8sClient *k8s.K8sClient
+	activity  *activity.Log
+}
+
+func NewDeploymentHandler(client *k8s.K8sClient, activityLog *activity.Log) *DeploymentHandler {
+	return &DeploymentHandler{k8sClient: client, activity: activityLog}
+}
+
+func (h *DeploymentHandler) CreateDeployment(c *gin.Context) {
+	var req models.CreateDeploymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	namespace, err := resolveNamespace(req.Namespace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if req.Replicas < 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Replicas must not be negative",
+		})
+		return
+	}
+	replicas := req.Replicas
+	if replicas == 0 {
+		replicas = 1
+	}
+
+	uid, err := h.generateDeploymentUID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	deploymentName := utils.GeneratePodName(utils.SanitizeName(req.Name))
+
+	labels := map[string]string{
+		"app": req.Name,
+		"uid": uid,
+	}
+	for k, v := range req.Labels {
+		labels[k] = v
+	}
+
+	// The pods get their own label rather than "uid", so they are not
+	// mistaken for standalone managed pods
+	podLabels := map[string]string{
+		"app":            req.Name,
+		"deployment-uid": uid,
+	}
+
+	container := corev1.Container{
+		Name:  req.ContainerName,
+		Image: req.Image,
+		Env: []corev1.EnvVar{
+			{Name: "DEPLOYMENT_UID", Value: uid},
+		},
+	}
+	if req.Port > 0 {
+		container.Ports = []corev1.ContainerPort{
+			{ContainerPort: req.Port},
+		}
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   deploymentName,
+			Labels: labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"deployment-uid": uid,
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{container},
+				},
+			},
+		},
+	}
+
+	createdDeployment, err := h.k8sClient.ClientSet.AppsV1().Deployments(namespace).Create(
+		h.k8sClient.Context, deployment, metav1.CreateOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	h.activity.Record("create", "Deployment", uid, createdDeployment.Name, createdDeployment.Namespace)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Deployment created successfully",
+		Data:    deploymentResponse(createdDeployment),
+	})
+}
+
+func (h *DeploymentHandler) ListDeployments(c *gin.Context) {
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	deployments, err := h.k8sClient.ClientSet.AppsV1().Deployments(namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			LabelSelector: "uid",
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	items := []interface{}{}
+	for i := range deployments.Items {
+		items = append(items, deploymentResponse(&deployments.Items[i]))
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: models.ListResponse{
+			Items: items,
+			Count: len(items),
+		},
+	})
+}
+
+func (h *DeploymentHandler) GetDeploymentByUID(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	deployments, err := h.k8sClient.ClientSet.AppsV1().Deployments(namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			LabelSelector: "uid=" + uid,
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(deployments.Items) == 0 {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Deployment not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    deploymentResponse(&deployments.Items[0]),
+	})
+}
+
+func (h *DeploymentHandler) DeleteDeploymentByUID(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	deployments, err := h.k8sClient.ClientSet.AppsV1().Deployments(namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			LabelSelector: "uid=" + uid,
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(deployments.Items) == 0 {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Deployment not found",
+		})
+		return
+	}
+
+	deployment := deployments.Items[0]
+	// Background propagation lets the garbage collector remove the
+	// deployment's replica sets and pods
+	propagation := metav1.DeletePropagationBackground
+	err = h.k8sClient.ClientSet.AppsV1().Deployments(deployment.Namespace).Delete(
+		h.k8sClient.Context, deployment.Name, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	h.activity.Record("delete", "Deployment", uid, deployment.Name, deployment.Namespace)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Deployment deleted successfully",
+	})
+}
+
+// ScaleDeployment sets the desired replica count through the scale subresource.
+func (h *DeploymentHandler) ScaleDeployment(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	var req models.ScaleDeploymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if req.Replicas == nil || *req.Replicas < 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Replicas must be given and must not be negative",
+		})
+		return
+	}
+
+	deployments, err := h.k8sClient.ClientSet.AppsV1().Deployments(namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			LabelSelector: "uid=" + uid,
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(deployments.Items) == 0 {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Deployment not found",
+		})
+		return
+	}
+
+	deployment := deployments.Items[0]
+	client := h.k8sClient.ClientSet.AppsV1().Deployments(deployment.Namespace)
+
+	scale, err := client.GetScale(h.k8sClient.Context, deployment.Name, metav1.GetOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	scale.Spec.Replicas = *req.Replicas
+	if _, err := client.UpdateScale(h.k8sClient.Context, deployment.Name, scale, metav1.UpdateOptions{}); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Re-read the deployment for its current availability; the scale itself
+	// only carries the desired count
+	updated, err := client.Get(h.k8sClient.Context, deployment.Name, metav1.GetOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Deployment scaled to %d replicas", *req.Replicas),
+		Data:    deploymentResponse(updated),
+	})
+}
+
+func deploymentResponse(deployment *appsv1.Deployment) models.DeploymentResponse {
+	response := models.DeploymentResponse{
+		UID:               deployment.Labels["uid"],
+		Name:              deployment.Name,
+		Namespace:         deployment.Namespace,
+		Labels:            deployment.Labels,
+		ReadyReplicas:     deployment.Status.ReadyReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+		CreatedAt:         deployment.CreationTimestamp.Time,
+	}
+	if deployment.Spec.Replicas != nil {
+		response.Replicas = *deployment.Spec.Replicas
+	}
+	if len(deployment.Spec.Template.Spec.Containers) > 0 {
+		response.Image = deployment.Spec.Template.Spec.Containers[0].Image
+	}
+	return response
+}
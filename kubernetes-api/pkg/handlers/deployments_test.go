@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// fakeScale serves the deployment scale subresource, which the fake clientset
+// does not implement, from the deployments' replica counts.
+func fakeScale(client *k8s.K8sClient) {
+	clientset := client.ClientSet.(*fake.Clientset)
+	deployments := appsv1.SchemeGroupVersion.WithResource("deployments")
+	clientset.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		obj, err := clientset.Tracker().Get(deployments, action.GetNamespace(), action.(k8stesting.GetAction).GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		deployment := obj.(*appsv1.Deployment)
+		return true, &autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Name: deployment.Name, Namespace: deployment.Namespace},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: replicasOf(deployment)},
+		}, nil
+	})
+	clientset.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		scale := action.(k8stesting.UpdateAction).GetObject().(*autoscalingv1.Scale)
+		obj, err := clientset.Tracker().Get(deployments, action.GetNamespace(), scale.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		deployment := obj.(*appsv1.Deployment)
+		deployment.Spec.Replicas = &scale.Spec.Replicas
+		return true, scale, clientset.Tracker().Update(deployments, deployment, action.GetNamespace())
+	})
+}
+
+// replicasOf returns the desired replica count of deployment, which
+// defaults to one.
+func replicasOf(deployment *appsv1.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+	return *deployment.Spec.Replicas
+}
+
+// deploymentReplicas returns the desired replica count of the named deployment.
+func deploymentReplicas(t *testing.T, client *k8s.K8sClient, name string) int32 {
+	t.Helper()
+	deployment, err := client.ClientSet.AppsV1().Deployments("default").Get(client.Context, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return replicasOf(deployment)
+}
+
+func TestDeploymentLifecycle(t *testing.T) {
+	h := newTestDeploymentHandler()
+
+	rec := serve(h.CreateDeployment, http.MethodPost, "/deployments", "/deployments",
+		`{"name":"web","image":"nginx","container_name":"nginx","replicas":3}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("CreateDeployment returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var created models.DeploymentResponse
+	decodeResponse(t, rec, &created)
+	if created.UID == "" || created.Replicas != 3 || created.Image != "nginx" {
+		t.Fatalf("CreateDeployment = %+v, want a UID, 3 replicas and image nginx", created)
+	}
+
+	rec = serve(h.ListDeployments, http.MethodGet, "/deployments", "/deployments", "")
+	var list models.ListResponse
+	decodeResponse(t, rec, &list)
+	if rec.Code != http.StatusOK || list.Count != 1 {
+		t.Errorf("ListDeployments returned %d with %d items, want 1 item", rec.Code, list.Count)
+	}
+
+	rec = serve(h.GetDeploymentByUID, http.MethodGet, "/deployments/:uid", "/deployments/"+created.UID, "")
+	var got models.DeploymentResponse
+	decodeResponse(t, rec, &got)
+	if rec.Code != http.StatusOK || got.Name != created.Name {
+		t.Errorf("GetDeploymentByUID returned %d with %+v, want %s", rec.Code, got, created.Name)
+	}
+
+	rec = serve(h.DeleteDeploymentByUID, http.MethodDelete, "/deployments/:uid", "/deployments/"+created.UID, "")
+	if rec.Code != http.StatusOK {
+		t.Errorf("DeleteDeploymentByUID returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(h.GetDeploymentByUID, http.MethodGet, "/deployments/:uid", "/deployments/"+created.UID, "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GetDeploymentByUID of a deleted deployment returned %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	rec = serve(h.DeleteDeploymentByUID, http.MethodDelete, "/deployments/:uid", "/deployments/"+created.UID, "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("DeleteDeploymentByUID of a deleted deployment returned %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestCreateDeploymentNegativeReplicas(t *testing.T) {
+	h := newTestDeploymentHandler()
+	rec := serve(h.CreateDeployment, http.MethodPost, "/deployments", "/deployments",
+		`{"name":"web","image":"nginx","container_name":"nginx","replicas":-1}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("CreateDeployment with -1 replicas returned %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestScaleDeployment(t *testing.T) {
+	h := newTestDeploymentHandler()
+	fakeScale(h.k8sClient)
+
+	rec := serve(h.CreateDeployment, http.MethodPost, "/deployments", "/deployments",
+		`{"name":"web","image":"nginx","container_name":"nginx","replicas":1}`)
+	var created models.DeploymentResponse
+	decodeResponse(t, rec, &created)
+
+	rec = serve(h.ScaleDeployment, http.MethodPut, "/deployments/:uid/scale", "/deployments/"+created.UID+"/scale", `{"replicas":4}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ScaleDeployment returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var scaled models.DeploymentResponse
+	decodeResponse(t, rec, &scaled)
+	if scaled.Replicas != 4 {
+		t.Errorf("ScaleDeployment response has %d replicas, want 4", scaled.Replicas)
+	}
+	if replicas := deploymentReplicas(t, h.k8sClient, created.Name); replicas != 4 {
+		t.Errorf("deployment has %d replicas after scaling, want 4", replicas)
+	}
+}
+
+func TestScaleDeploymentErrors(t *testing.T) {
+	h := newTestDeploymentHandler()
+	fakeScale(h.k8sClient)
+
+	for _, tt := range []struct {
+		uid, body string
+		want      int
+	}{
+		{"missing", `{"replicas":2}`, http.StatusNotFound},
+		{"missing", `{"replicas":-1}`, http.StatusBadRequest},
+		{"missing", `{}`, http.StatusBadRequest},
+	} {
+		rec := serve(h.ScaleDeployment, http.MethodPut, "/deployments/:uid/scale", "/deployments/"+tt.uid+"/scale", tt.body)
+		if rec.Code != tt.want {
+			t.Errorf("ScaleDeployment(%s) returned %d, want %d", tt.body, rec.Code, tt.want)
+		}
+	}
+}
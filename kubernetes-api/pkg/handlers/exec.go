@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+const (
+	defaultExecTimeout = 30 * time.Second
+	maxExecTimeout     = 5 * time.Minute
+
+	// maxExecOutput caps how much of stdout and of stderr is returned
+	maxExecOutput = 1 << 20
+)
+
+// cappedBuffer keeps the first maxExecOutput bytes written to it and drops
+// the rest, so a chatty command cannot exhaust memory.
+type cappedBuffer struct {
+	data      []byte
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	kept := p
+	if room := maxExecOutput - len(b.data); len(kept) > room {
+		b.truncated = true
+		kept = kept[:room]
+	}
+	b.data = append(b.data, kept...)
+	// Report everything as written so the stream keeps draining
+	return len(p), nil
+}
+
+// ExecInPod runs a command in a container of the pod through the exec
+// subresource and returns its output and exit code. The command is not run in
+// a shell, and gets no stdin or TTY.
+func (h *PodHandler) ExecInPod(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	var req models.ExecRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if len(req.Command) == 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "command is required",
+		})
+		return
+	}
+
+	timeout, err := time.ParseDuration(c.DefaultQuery("timeout", defaultExecTimeout.String()))
+	if err != nil || timeout <= 0 || timeout > maxExecTimeout {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid timeout %q: must be a duration up to %s", c.Query("timeout"), maxExecTimeout),
+		})
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if pod == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+
+	if pod.Status.Phase != corev1.PodRunning {
+		c.JSON(http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Pod is not running (status: %s)", pod.Status.Phase),
+		})
+		return
+	}
+
+	container, err := execContainer(pod, req.Container)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	var stdout, stderr cappedBuffer
+	err = h.execCommand(ctx, pod, container, req.Command, &stdout, &stderr)
+
+	response := models.ExecResponse{
+		Container: container,
+		Command:   req.Command,
+		Stdout:    string(stdout.data),
+		Stderr:    string(stderr.data),
+		Truncated: stdout.truncated || stderr.truncated,
+	}
+
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr) && exitErr.Exited():
+		// A non-zero exit is a result, not a failure of the request
+		response.ExitCode = exitErr.ExitStatus()
+	case ctx.Err() == context.DeadlineExceeded:
+		c.JSON(http.StatusGatewayTimeout, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Command did not finish within %s", timeout),
+			Data:    response,
+		})
+		return
+	default:
+		c.JSON(http.StatusBadGateway, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to exec in pod: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Command exited with code %d", response.ExitCode),
+		Data:    response,
+	})
+}
+
+// execContainer picks the container to exec in: the requested one, or the
+// only container of a single-container pod.
+func execContainer(pod *corev1.Pod, requested string) (string, error) {
+	var names []string
+	for _, container := range pod.Spec.Containers {
+		if container.Name == requested {
+			return requested, nil
+		}
+		names = append(names, container.Name)
+	}
+
+	if requested != "" {
+		return "", fmt.Errorf("container %q not found in pod, available: %v", requested, names)
+	}
+	if len(names) != 1 {
+		return "", fmt.Errorf("pod has %d containers, specify one of %v", len(names), names)
+	}
+	return names[0], nil
+}
+
+// executorFactory creates the executor running an exec request in a pod.
+type executorFactory func(client *k8s.K8sClient, pod *corev1.Pod, options *corev1.PodExecOptions) (remotecommand.Executor, error)
+
+// newSPDYExecutor execs through the API server's exec subresource over SPDY.
+func newSPDYExecutor(client *k8s.K8sClient, pod *corev1.Pod, options *corev1.PodExecOptions) (remotecommand.Executor, error) {
+	req := client.ClientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(options, scheme.ParameterCodec)
+
+	return remotecommand.NewSPDYExecutor(client.Config, http.MethodPost, req.URL())
+}
+
+// execCommand streams command's output in container of pod into stdout and
+// stderr until it exits or ctx is done.
+func (h *PodHandler) execCommand(ctx context.Context, pod *corev1.Pod, container string, command []string, stdout, stderr *cappedBuffer) error {
+	executor, err := h.newExecutor(h.k8sClient, pod, &corev1.PodExecOptions{
+		Container: container,
+		Command:   command,
+		Stdout:    true,
+		Stderr:    true,
+	})
+	if err != nil {
+		return err
+	}
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+}
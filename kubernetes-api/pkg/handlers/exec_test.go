@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+const execRoute = "/pods/:uid/exec"
+
+// fakeExecutor writes fixed output and returns err, or blocks until the
+// context is done when block is set.
+type fakeExecutor struct {
+	stdout, stderr string
+	err            error
+	block          bool
+}
+
+func (e *fakeExecutor) Stream(options remotecommand.StreamOptions) error {
+	return e.StreamWithContext(context.Background(), options)
+}
+
+func (e *fakeExecutor) StreamWithContext(ctx context.Context, options remotecommand.StreamOptions) error {
+	io.WriteString(options.Stdout, e.stdout)
+	io.WriteString(options.Stderr, e.stderr)
+	if e.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return e.err
+}
+
+// newExecTestHandler returns a pod handler holding a running pod with UID
+// "abc" whose execs run through executor, recording the options they get.
+func newExecTestHandler(executor *fakeExecutor, got *corev1.PodExecOptions) *PodHandler {
+	h := newTestPodHandler(testPod("web", map[string]string{"uid": "abc"}))
+	h.newExecutor = func(client *k8s.K8sClient, pod *corev1.Pod, options *corev1.PodExecOptions) (remotecommand.Executor, error) {
+		*got = *options
+		return executor, nil
+	}
+	return h
+}
+
+func TestExecInPodCapturesOutput(t *testing.T) {
+	var options corev1.PodExecOptions
+	h := newExecTestHandler(&fakeExecutor{stdout: "hello\n", stderr: "warning\n"}, &options)
+
+	rec := serve(h.ExecInPod, http.MethodPost, execRoute, "/pods/abc/exec", `{"command":["echo","hello"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ExecInPod returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var data models.ExecResponse
+	decodeResponse(t, rec, &data)
+
+	if data.Stdout != "hello\n" || data.Stderr != "warning\n" || data.ExitCode != 0 {
+		t.Errorf("exec = %+v, want stdout and stderr captured with exit code 0", data)
+	}
+	if options.Container != "app" || !slices.Equal(options.Command, []string{"echo", "hello"}) || !options.Stdout || !options.Stderr || options.Stdin {
+		t.Errorf("exec options = %+v, want echo hello in app with stdout and stderr only", options)
+	}
+}
+
+func TestExecInPodNonZeroExit(t *testing.T) {
+	var options corev1.PodExecOptions
+	executor := &fakeExecutor{stderr: "no such file\n", err: utilexec.CodeExitError{Err: errors.New("command terminated with exit code 2"), Code: 2}}
+	h := newExecTestHandler(executor, &options)
+
+	rec := serve(h.ExecInPod, http.MethodPost, execRoute, "/pods/abc/exec", `{"command":["ls","/missing"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ExecInPod returned %d, want 200 for a command that ran: %s", rec.Code, rec.Body.String())
+	}
+	var data models.ExecResponse
+	resp := decodeResponse(t, rec, &data)
+	if data.ExitCode != 2 || data.Stderr != "no such file\n" {
+		t.Errorf("exec = %+v, want exit code 2 with stderr", data)
+	}
+	if resp.Message != "Command exited with code 2" {
+		t.Errorf("message = %q", resp.Message)
+	}
+}
+
+func TestExecInPodTimeout(t *testing.T) {
+	var options corev1.PodExecOptions
+	h := newExecTestHandler(&fakeExecutor{stdout: "partial", block: true}, &options)
+
+	rec := serve(h.ExecInPod, http.MethodPost, execRoute, "/pods/abc/exec?timeout=50ms", `{"command":["sleep","60"]}`)
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("ExecInPod returned %d, want 504: %s", rec.Code, rec.Body.String())
+	}
+	var data models.ExecResponse
+	resp := decodeResponse(t, rec, &data)
+	if !strings.Contains(resp.Error, "50ms") || data.Stdout != "partial" {
+		t.Errorf("timeout response = %q with %+v, want the timeout and the output so far", resp.Error, data)
+	}
+}
+
+func TestExecInPodStreamFailure(t *testing.T) {
+	var options corev1.PodExecOptions
+	h := newExecTestHandler(&fakeExecutor{err: errors.New("upgrade failed")}, &options)
+
+	rec := serve(h.ExecInPod, http.MethodPost, execRoute, "/pods/abc/exec", `{"command":["true"]}`)
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("ExecInPod returned %d, want 502: %s", rec.Code, rec.Body.String())
+	}
+}
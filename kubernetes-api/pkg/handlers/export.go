@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+// exportPageSize is the number of objects fetched per list call while exporting.
+const exportPageSize = 100
+
+type ExportHandler struct {
+	k8sClient *k8s.K8sClient
+}
+
+func NewExportHandler(client *k8s.K8sClient) *ExportHandler {
+	return &ExportHandler{k8sClient: client}
+}
+
+// exportWriter streams objects as a multi-document YAML bundle.
+type exportWriter struct {
+	c       *gin.Context
+	written int
+}
+
+func (w *exportWriter) write(obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	if w.written == 0 {
+		w.c.Header("Content-Type", "application/yaml")
+		w.c.Status(http.StatusOK)
+	}
+	w.written++
+
+	if _, err := w.c.Writer.Write(append([]byte("---\n"), data...)); err != nil {
+		return err
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+// ExportResources dumps every pod, service and deployment in ?namespace=
+// matching the label selector (by default everything carrying a uid label) as
+// YAML, cleaned of status and server-managed fields so the bundle can be
+// applied elsewhere. ?allNamespaces=true exports every namespace instead; each
+// object keeps its namespace in the bundle either way.
+func (h *ExportHandler) ExportResources(c *gin.Context) {
+	namespace := metav1.NamespaceAll
+	if c.Query("allNamespaces") != "true" {
+		var ok bool
+		if namespace, ok = queryNamespace(c); !ok {
+			return
+		}
+	}
+
+	selector := c.DefaultQuery("labelSelector", "uid")
+	if _, err := labels.Parse(selector); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid label selector: %v", err),
+		})
+		return
+	}
+
+	w := &exportWriter{c: c}
+	exporters := []func(*exportWriter, string, string) error{
+		h.exportPods,
+		h.exportServices,
+		h.exportDeployments,
+	}
+
+	for _, export := range exporters {
+		if err := export(w, namespace, selector); err != nil {
+			if w.written == 0 {
+				c.JSON(http.StatusInternalServerError, models.APIResponse{
+					Success: false,
+					Error:   err.Error(),
+				})
+				return
+			}
+			// Headers are already sent, so flag the truncated bundle inline
+			fmt.Fprintf(c.Writer, "# export aborted: %v\n", err)
+			return
+		}
+	}
+
+	if w.written == 0 {
+		c.Header("Content-Type", "application/yaml")
+		c.Status(http.StatusOK)
+	}
+}
+
+func (h *ExportHandler) exportPods(w *exportWriter, namespace, selector string) error {
+	opts := metav1.ListOptions{LabelSelector: selector, Limit: exportPageSize}
+	for {
+		pods, err := h.k8sClient.ClientSet.CoreV1().Pods(namespace).List(h.k8sClient.Context, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+
+		for _, pod := range pods.Items {
+			exported := corev1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: exportedMeta(pod.ObjectMeta),
+				Spec:       cleanPodSpec(pod.Spec),
+			}
+			if err := w.write(exported); err != nil {
+				return err
+			}
+		}
+
+		if pods.Continue == "" {
+			return nil
+		}
+		opts.Continue = pods.Continue
+	}
+}
+
+func (h *ExportHandler) exportServices(w *exportWriter, namespace, selector string) error {
+	opts := metav1.ListOptions{LabelSelector: selector, Limit: exportPageSize}
+	for {
+		services, err := h.k8sClient.ClientSet.CoreV1().Services(namespace).List(h.k8sClient.Context, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+
+		for _, service := range services.Items {
+			spec := *service.Spec.DeepCopy()
+			// Cluster IPs are allocated by the target cluster
+			spec.ClusterIP = ""
+			spec.ClusterIPs = nil
+
+			exported := corev1.Service{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+				ObjectMeta: exportedMeta(service.ObjectMeta),
+				Spec:       spec,
+			}
+			if err := w.write(exported); err != nil {
+				return err
+			}
+		}
+
+		if services.Continue == "" {
+			return nil
+		}
+		opts.Continue = services.Continue
+	}
+}
+
+func (h *ExportHandler) exportDeployments(w *exportWriter, namespace, selector string) error {
+	opts := metav1.ListOptions{LabelSelector: selector, Limit: exportPageSize}
+	for {
+		deployments, err := h.k8sClient.ClientSet.AppsV1().Deployments(namespace).List(h.k8sClient.Context, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list deployments: %w", err)
+		}
+
+		for _, deployment := range deployments.Items {
+			exported := appsv1.Deployment{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta: exportedMeta(deployment.ObjectMeta),
+				Spec:       *deployment.Spec.DeepCopy(),
+			}
+			if err := w.write(exported); err != nil {
+				return err
+			}
+		}
+
+		if deployments.Continue == "" {
+			return nil
+		}
+		opts.Continue = deployments.Continue
+	}
+}
+
+// ExportPod returns the manifest of a single pod as YAML (the default) or
+// JSON, including the defaults Kubernetes filled in but cleaned of status and
+// server-managed fields like ExportResources, so it can be created elsewhere.
+func (h *PodHandler) ExportPod(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "yaml")
+	if format != "yaml" && format != "json" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid format %q: must be yaml or json", format),
+		})
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if pod == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+
+	exported := corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: exportedMeta(pod.ObjectMeta),
+		Spec:       cleanPodSpec(pod.Spec),
+	}
+
+	if format == "json" {
+		c.IndentedJSON(http.StatusOK, exported)
+		return
+	}
+
+	data, err := yaml.Marshal(exported)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	c.Data(http.StatusOK, "application/yaml", data)
+}
+
+// exportedMeta keeps only the user-facing parts of an object's metadata.
+func exportedMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   meta.Namespace,
+		Labels:      meta.Labels,
+		Annotations: meta.Annotations,
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"kubernetes-api/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// exportedNames returns "namespace/name" for every document of a bundle.
+func exportedNames(t *testing.T, bundle string) []string {
+	t.Helper()
+	var names []string
+	for _, doc := range strings.Split(bundle, "---\n") {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		var obj metav1.PartialObjectMetadata
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			t.Fatalf("invalid document %q: %v", doc, err)
+		}
+		names = append(names, obj.Namespace+"/"+obj.Name)
+	}
+	return names
+}
+
+func TestExportResourcesNamespace(t *testing.T) {
+	other := testPod("other", map[string]string{"uid": "b2"})
+	other.Namespace = "team-a"
+	h := NewExportHandler(newTestClient(
+		testPod("web", map[string]string{"uid": "a1"}),
+		other,
+	))
+
+	tests := []struct {
+		name   string
+		target string
+		want   []string
+	}{
+		{"default namespace", "/export", []string{"default/web"}},
+		{"named namespace", "/export?namespace=team-a", []string{"team-a/other"}},
+		{"all namespaces", "/export?allNamespaces=true", []string{"default/web", "team-a/other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(h.ExportResources, http.MethodGet, "/export", tt.target, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+			}
+			got := exportedNames(t, rec.Body.String())
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("exported %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	rec := serve(h.ExportResources, http.MethodGet, "/export", "/export?namespace=Team_A", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid namespace: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestExportBundleApplies(t *testing.T) {
+	source := []runtime.Object{
+		testPod("web", map[string]string{"uid": "a1", "app": "web"}),
+		testService("web", map[string]string{"uid": "c3"}),
+	}
+	rec := serve(NewExportHandler(newTestClient(source...)).ExportResources,
+		http.MethodGet, "/export", "/export", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("export status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	h := newTestApplyHandler()
+	target := h.k8sClient
+	rec = serve(h.ApplyManifest, http.MethodPost, "/apply", "/apply", rec.Body.String())
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("apply status = %d, want 201: %s", rec.Code, rec.Body.String())
+	}
+
+	var bundle models.ApplyBundleResponse
+	decodeResponse(t, rec, &bundle)
+	var kinds []string
+	for _, item := range bundle.Items {
+		kinds = append(kinds, item.Kind)
+	}
+	if !slices.Equal(kinds, []string{"Pod", "Service"}) {
+		t.Errorf("applied kinds = %v, want [Pod Service]", kinds)
+	}
+
+	if _, err := target.ClientSet.CoreV1().Pods("default").Get(target.Context, "web", metav1.GetOptions{}); err != nil {
+		t.Errorf("pod not created: %v", err)
+	}
+	if _, err := target.ClientSet.CoreV1().Services("default").Get(target.Context, "web", metav1.GetOptions{}); err != nil {
+		t.Errorf("service not created: %v", err)
+	}
+}
+
+func TestExportPodRoundTrip(t *testing.T) {
+	spec := corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:  "app",
+			Image: "nginx:1.27",
+			Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 80, Protocol: corev1.ProtocolTCP}},
+			Env:   []corev1.EnvVar{{Name: "MODE", Value: "fast"}},
+		}},
+		RestartPolicy: corev1.RestartPolicyAlways,
+		DNSPolicy:     corev1.DNSClusterFirst,
+	}
+
+	// The stored pod carries everything the cluster adds to the spec above
+	stored := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-abc",
+			Namespace:       "default",
+			Labels:          map[string]string{"uid": "abc", "app": "web"},
+			Annotations:     map[string]string{"team": "platform"},
+			UID:             "2f8e7c1a-0000-4000-8000-000000000000",
+			ResourceVersion: "12345",
+			ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "kubernetes-api", Operation: metav1.ManagedFieldsOperationUpdate}},
+		},
+		Spec:   *spec.DeepCopy(),
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.7"},
+	}
+	stored.Spec.NodeName = "node-1"
+	stored.Spec.Volumes = []corev1.Volume{{Name: "kube-api-access-x7k2p"}}
+	stored.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "kube-api-access-x7k2p", MountPath: "/var/run/secrets/kubernetes.io/serviceaccount"}}
+	h := newTestPodHandler(stored)
+
+	for _, format := range []string{"yaml", "json"} {
+		t.Run(format, func(t *testing.T) {
+			rec := serve(h.ExportPod, http.MethodGet, "/pods/:uid/export", "/pods/abc/export?format="+format, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("ExportPod returned %d: %s", rec.Code, rec.Body.String())
+			}
+			// An empty status object is fine; its contents are not
+			for _, stripped := range []string{"Running", "10.0.0.7", "managedFields", "resourceVersion", "node-1", "kube-api-access"} {
+				if strings.Contains(rec.Body.String(), stripped) {
+					t.Errorf("export contains %s:\n%s", stripped, rec.Body.String())
+				}
+			}
+
+			// sigs.k8s.io/yaml reads JSON as well
+			var exported corev1.Pod
+			if err := yaml.Unmarshal(rec.Body.Bytes(), &exported); err != nil {
+				t.Fatalf("export does not decode: %v", err)
+			}
+			if exported.Kind != "Pod" || exported.APIVersion != "v1" {
+				t.Errorf("exported type = %s/%s, want v1/Pod", exported.APIVersion, exported.Kind)
+			}
+			if !reflect.DeepEqual(exported.Spec, spec) {
+				t.Errorf("exported spec = %+v, want %+v", exported.Spec, spec)
+			}
+			if !reflect.DeepEqual(exported.Status, corev1.PodStatus{}) {
+				t.Errorf("exported status = %+v, want it stripped", exported.Status)
+			}
+			meta := exported.ObjectMeta
+			if meta.UID != "" || meta.ResourceVersion != "" || meta.ManagedFields != nil {
+				t.Errorf("exported metadata = %+v, want server-managed fields stripped", meta)
+			}
+			if meta.Name != "web-abc" || meta.Namespace != "default" || meta.Labels["uid"] != "abc" || meta.Annotations["team"] != "platform" {
+				t.Errorf("exported metadata = %+v, want name, namespace, labels and annotations kept", meta)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/index"
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestPodHandler creates a pod handler backed by a fake clientset holding objects.
+func newTestPodHandler(objects ...runtime.Object) *PodHandler {
+	client := newTestClient(objects...)
+	return NewPodHandler(client, activity.NewLog(10), index.New(client.ClientSet, ""))
+}
+
+// newTestClient returns a client backed by a fake clientset holding objects.
+func newTestClient(objects ...runtime.Object) *k8s.K8sClient {
+	return &k8s.K8sClient{
+		ClientSet: fake.NewClientset(objects...),
+		Context:   context.Background(),
+	}
+}
+
+// newTestDeploymentHandler creates a deployment handler backed by a fake clientset holding objects.
+func newTestDeploymentHandler(objects ...runtime.Object) *DeploymentHandler {
+	return NewDeploymentHandler(newTestClient(objects...), activity.NewLog(10))
+}
+
+// newTestServiceHandler creates a service handler backed by a fake clientset holding objects.
+func newTestServiceHandler(objects ...runtime.Object) *ServiceHandler {
+	client := newTestClient(objects...)
+	return NewServiceHandler(client, activity.NewLog(10), index.New(client.ClientSet, ""))
+}
+
+// testService returns a ClusterIP service in the default namespace with the given labels.
+func testService(name string, labels map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{{Port: 80}},
+		},
+	}
+}
+
+// serve runs handler for a single request to target, routed through route so
+// path parameters are filled in, and returns the recorded response.
+func serve(handler gin.HandlerFunc, method, route, target, body string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Handle(method, route, handler)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+// decodeResponse decodes an APIResponse, with its data decoded into data when non-nil.
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder, data any) models.APIResponse {
+	t.Helper()
+	var resp models.APIResponse
+	if data != nil {
+		resp.Data = data
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response body %q: %v", rec.Body.String(), err)
+	}
+	return resp
+}
+
+// testPod returns a running pod in the default namespace with the given labels.
+func testPod(name string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const metricsGroupVersion = "metrics.k8s.io/v1beta1"
+
+// GetPodMetrics returns the current CPU and memory usage of each container in
+// a pod, as reported by metrics-server
+func (h *PodHandler) GetPodMetrics(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if pod == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+
+	podMetrics, err := h.k8sClient.MetricsClient.MetricsV1beta1().PodMetricses(pod.Namespace).Get(
+		h.k8sClient.Context, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		// A missing metrics API and a pod that has not been scraped yet both
+		// come back as NotFound, so ask discovery which one it is
+		if apierrors.IsServiceUnavailable(err) || !h.metricsAvailable() {
+			c.JSON(http.StatusServiceUnavailable, models.APIResponse{
+				Success: false,
+				Error:   "Metrics API (" + metricsGroupVersion + ") is not available; install metrics-server to see pod usage",
+			})
+			return
+		}
+		if apierrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   "No metrics for this pod yet; metrics-server reports usage about a minute after a container starts",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response := models.PodMetricsResponse{
+		UID:        uid,
+		Name:       pod.Name,
+		Timestamp:  podMetrics.Timestamp.Time,
+		Window:     podMetrics.Window.Duration.String(),
+		Containers: []models.ContainerMetrics{},
+	}
+
+	var totalCPU, totalMemory int64
+	for _, container := range podMetrics.Containers {
+		cpu := container.Usage.Cpu().MilliValue()
+		memory := container.Usage.Memory().Value()
+		totalCPU += cpu
+		totalMemory += memory
+
+		response.Containers = append(response.Containers, models.ContainerMetrics{
+			Name:   container.Name,
+			CPU:    formatMillicores(cpu),
+			Memory: formatMebibytes(memory),
+		})
+	}
+	response.TotalCPU = formatMillicores(totalCPU)
+	response.TotalMemory = formatMebibytes(totalMemory)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+func (h *PodHandler) metricsAvailable() bool {
+	_, err := h.k8sClient.ClientSet.Discovery().ServerResourcesForGroupVersion(metricsGroupVersion)
+	return err == nil
+}
+
+func formatMillicores(millicores int64) string {
+	return fmt.Sprintf("%dm", millicores)
+}
+
+func formatMebibytes(bytes int64) string {
+	return fmt.Sprintf("%.1fMi", float64(bytes)/(1024*1024))
+}
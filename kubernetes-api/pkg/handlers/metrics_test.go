@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"kubernetes-api/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+// withMetricsAPI makes h's fake cluster serve the metrics API with the given pod metrics.
+func withMetricsAPI(h *PodHandler, podMetrics ...*metricsv1beta1.PodMetrics) {
+	h.k8sClient.ClientSet.(*fake.Clientset).Resources = []*metav1.APIResourceList{
+		{GroupVersion: metricsGroupVersion, APIResources: []metav1.APIResource{{Name: "pods", Namespaced: true, Kind: "PodMetrics"}}},
+	}
+	client := metricsfake.NewSimpleClientset()
+	for _, m := range podMetrics {
+		client.Tracker().Create(metricsv1beta1.SchemeGroupVersion.WithResource("pods"), m, m.Namespace)
+	}
+	h.k8sClient.MetricsClient = client
+}
+
+func TestGetPodMetrics(t *testing.T) {
+	h := newTestPodHandler(testPod("web", map[string]string{"uid": "abc"}))
+	usage := func(cpu, memory string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)}
+	}
+	withMetricsAPI(h, &metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Window:     metav1.Duration{Duration: 30 * time.Second},
+		Containers: []metricsv1beta1.ContainerMetrics{
+			{Name: "app", Usage: usage("12m", "32Mi")},
+			{Name: "sidecar", Usage: usage("3m", "8Mi")},
+		},
+	})
+
+	rec := serve(h.GetPodMetrics, http.MethodGet, "/pods/:uid/metrics", "/pods/abc/metrics", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GetPodMetrics returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var got models.PodMetricsResponse
+	decodeResponse(t, rec, &got)
+	if len(got.Containers) != 2 || got.Containers[0] != (models.ContainerMetrics{Name: "app", CPU: "12m", Memory: "32.0Mi"}) {
+		t.Errorf("Containers = %+v, want app at 12m and 32.0Mi first", got.Containers)
+	}
+	if got.TotalCPU != "15m" || got.TotalMemory != "40.0Mi" || got.Window != "30s" {
+		t.Errorf("totals = %s, %s over %s; want 15m, 40.0Mi over 30s", got.TotalCPU, got.TotalMemory, got.Window)
+	}
+}
+
+func TestGetPodMetricsUnavailable(t *testing.T) {
+	// Without metrics-server the metrics API is not served at all
+	h := newTestPodHandler(testPod("web", map[string]string{"uid": "abc"}))
+	h.k8sClient.MetricsClient = metricsfake.NewSimpleClientset()
+
+	rec := serve(h.GetPodMetrics, http.MethodGet, "/pods/:uid/metrics", "/pods/abc/metrics", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("GetPodMetrics without metrics-server returned %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	// With metrics-server, a pod that has not been scraped yet is a 404
+	withMetricsAPI(h)
+	rec = serve(h.GetPodMetrics, http.MethodGet, "/pods/:uid/metrics", "/pods/abc/metrics", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GetPodMetrics before the first scrape returned %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// defaultNamespace is used when a request does not name a namespace.
+const defaultNamespace = "default"
+
+// resolveNamespace defaults an empty namespace and checks that it is a valid
+// DNS-1123 label.
+func resolveNamespace(namespace string) (string, error) {
+	if namespace == "" {
+		return defaultNamespace, nil
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return "", fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+	}
+	return namespace, nil
+}
+
+// queryNamespace resolves the ?namespace= query parameter. On an invalid
+// value it writes a 400 response and returns false.
+func queryNamespace(c *gin.Context) (string, bool) {
+	namespace, err := resolveNamespace(c.Query("namespace"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return "", false
+	}
+	return namespace, true
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NodeHandler struct {
+	k8sClient *k8s.K8sClient
+}
+
+func NewNodeHandler(client *k8s.K8sClient) *NodeHandler {
+	return &NodeHandler{k8sClient: client}
+}
+
+// GetClusterInfo returns a short summary of the cluster's nodes.
+func (h *NodeHandler) GetClusterInfo(c *gin.Context) {
+	nodes, err := h.k8sClient.ClientSet.CoreV1().Nodes().List(
+		h.k8sClient.Context, metav1.ListOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	clusterInfo := map[string]interface{}{
+		"node_count": len(nodes.Items),
+		"nodes":      []string{},
+	}
+
+	for _, node := range nodes.Items {
+		clusterInfo["nodes"] = append(clusterInfo["nodes"].([]string), node.Name)
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    clusterInfo,
+	})
+}
+
+func (h *NodeHandler) ListNodes(c *gin.Context) {
+	nodes, err := h.k8sClient.ClientSet.CoreV1().Nodes().List(
+		h.k8sClient.Context, metav1.ListOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	items := []interface{}{}
+	for i := range nodes.Items {
+		items = append(items, nodeResponse(&nodes.Items[i]))
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: models.ListResponse{
+			Items: items,
+			Count: len(items),
+		},
+	})
+}
+
+func (h *NodeHandler) GetNode(c *gin.Context) {
+	node, err := h.k8sClient.ClientSet.CoreV1().Nodes().Get(
+		h.k8sClient.Context, c.Param("name"), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Node not found",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    nodeResponse(node),
+	})
+}
+
+func nodeResponse(node *corev1.Node) models.NodeResponse {
+	response := models.NodeResponse{
+		Name:              node.Name,
+		Schedulable:       !node.Spec.Unschedulable,
+		KubeletVersion:    node.Status.NodeInfo.KubeletVersion,
+		AllocatableCPU:    node.Status.Allocatable.Cpu().String(),
+		AllocatableMemory: node.Status.Allocatable.Memory().String(),
+		AllocatablePods:   node.Status.Allocatable.Pods().Value(),
+		Conditions:        []models.NodeCondition{},
+		CreatedAt:         node.CreationTimestamp.Time,
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			response.Ready = condition.Status == corev1.ConditionTrue
+		}
+		response.Conditions = append(response.Conditions, models.NodeCondition{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			response.InternalIP = address.Address
+			break
+		}
+	}
+
+	return response
+}
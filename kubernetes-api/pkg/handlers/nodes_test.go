@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"kubernetes-api/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testNodes returns a ready worker and a cordoned worker under memory pressure.
+func testNodes() (*corev1.Node, *corev1.Node) {
+	healthy := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("3800m"),
+				corev1.ResourceMemory: resource.MustParse("7901Mi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+			},
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "10.0.0.12"}},
+			NodeInfo:  corev1.NodeSystemInfo{KubeletVersion: "v1.33.1"},
+		},
+	}
+	pressured := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-2"},
+		Spec:       corev1.NodeSpec{Unschedulable: true},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Reason: "KubeletNotReady"},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue, Reason: "KubeletHasInsufficientMemory"},
+			},
+		},
+	}
+	return healthy, pressured
+}
+
+func TestGetNode(t *testing.T) {
+	h := NewNodeHandler(newTestClient(testNodes()))
+
+	rec := serve(h.GetNode, http.MethodGet, "/nodes/:name", "/nodes/worker-1", "")
+	var healthy models.NodeResponse
+	decodeResponse(t, rec, &healthy)
+	if rec.Code != http.StatusOK || !healthy.Ready || !healthy.Schedulable {
+		t.Errorf("GetNode(worker-1) returned %d with %+v, want a ready, schedulable node", rec.Code, healthy)
+	}
+	if healthy.AllocatableCPU != "3800m" || healthy.AllocatableMemory != "7901Mi" || healthy.AllocatablePods != 110 ||
+		healthy.KubeletVersion != "v1.33.1" || healthy.InternalIP != "10.0.0.12" {
+		t.Errorf("GetNode(worker-1) = %+v, want its allocatable resources, kubelet version and IP", healthy)
+	}
+
+	rec = serve(h.GetNode, http.MethodGet, "/nodes/:name", "/nodes/worker-2", "")
+	var pressured models.NodeResponse
+	decodeResponse(t, rec, &pressured)
+	if pressured.Ready || pressured.Schedulable {
+		t.Errorf("GetNode(worker-2) ready = %v, schedulable = %v; want neither", pressured.Ready, pressured.Schedulable)
+	}
+	want := models.NodeCondition{Type: "MemoryPressure", Status: "True", Reason: "KubeletHasInsufficientMemory"}
+	if len(pressured.Conditions) != 2 || pressured.Conditions[1] != want {
+		t.Errorf("GetNode(worker-2) conditions = %+v, want %+v", pressured.Conditions, want)
+	}
+
+	rec = serve(h.GetNode, http.MethodGet, "/nodes/:name", "/nodes/missing", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GetNode(missing) returned %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestListNodes(t *testing.T) {
+	h := NewNodeHandler(newTestClient(testNodes()))
+
+	rec := serve(h.ListNodes, http.MethodGet, "/nodes", "/nodes", "")
+	var list models.ListResponse
+	decodeResponse(t, rec, &list)
+	if rec.Code != http.StatusOK || list.Count != 2 {
+		t.Errorf("ListNodes returned %d with %d nodes, want 2", rec.Code, list.Count)
+	}
+
+	// The cluster summary keeps its old shape
+	rec = serve(h.GetClusterInfo, http.MethodGet, "/cluster/info", "/cluster/info", "")
+	var info struct {
+		NodeCount int      `json:"node_count"`
+		Nodes     []string `json:"nodes"`
+	}
+	decodeResponse(t, rec, &info)
+	if info.NodeCount != 2 || len(info.Nodes) != 2 {
+		t.Errorf("GetClusterInfo = %+v, want 2 nodes", info)
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pageOptions builds list options from the ?limit= and ?continue= query
+// parameters. On an invalid limit it writes a 400 response and returns false.
+func pageOptions(c *gin.Context) (metav1.ListOptions, bool) {
+	opts := metav1.ListOptions{Continue: c.Query("continue")}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Invalid limit %q: must be a positive integer", limit),
+			})
+			return opts, false
+		}
+		opts.Limit = n
+	}
+
+	return opts, true
+}
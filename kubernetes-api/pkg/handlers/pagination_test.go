@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"kubernetes-api/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// listOptionsOf returns the list options a list action was called with.
+func listOptionsOf(action k8stesting.Action) metav1.ListOptions {
+	return action.(k8stesting.ListActionImpl).ListOptions
+}
+
+func TestListPodsPagination(t *testing.T) {
+	h := newTestPodHandler()
+	var gotLimit int64
+	var gotContinue string
+	h.k8sClient.ClientSet.(*fake.Clientset).PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		opts := listOptionsOf(action)
+		gotLimit, gotContinue = opts.Limit, opts.Continue
+		if opts.Continue == "" {
+			list := &corev1.PodList{Items: []corev1.Pod{*testPod("web-1", map[string]string{"uid": "a"}), *testPod("web-2", map[string]string{"uid": "b"})}}
+			list.Continue = "page-2"
+			return true, list, nil
+		}
+		return true, &corev1.PodList{Items: []corev1.Pod{*testPod("web-3", map[string]string{"uid": "c"})}}, nil
+	})
+
+	rec := serve(h.ListPods, http.MethodGet, "/pods", "/pods?limit=2", "")
+	var page models.ListResponse
+	decodeResponse(t, rec, &page)
+	if rec.Code != http.StatusOK || page.Count != 2 || page.Continue != "page-2" {
+		t.Errorf("first page returned %d with %d items and token %q, want 2 items and page-2", rec.Code, page.Count, page.Continue)
+	}
+	if gotLimit != 2 {
+		t.Errorf("list limit = %d, want 2", gotLimit)
+	}
+
+	rec = serve(h.ListPods, http.MethodGet, "/pods", "/pods?limit=2&continue=page-2", "")
+	page = models.ListResponse{}
+	decodeResponse(t, rec, &page)
+	if gotContinue != "page-2" {
+		t.Errorf("list continue = %q, want page-2", gotContinue)
+	}
+	if page.Count != 1 || page.Continue != "" {
+		t.Errorf("last page has %d items and token %q, want 1 item and no token", page.Count, page.Continue)
+	}
+
+	for _, target := range []string{"/pods?limit=0", "/pods?limit=abc"} {
+		if rec := serve(h.ListPods, http.MethodGet, "/pods", target, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("ListPods(%s) returned %d, want %d", target, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestListServicesPagination(t *testing.T) {
+	h := newTestServiceHandler()
+	var gotLimit int64
+	h.k8sClient.ClientSet.(*fake.Clientset).PrependReactor("list", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gotLimit = listOptionsOf(action).Limit
+		list := &corev1.ServiceList{Items: []corev1.Service{*testService("web", map[string]string{"uid": "a"})}}
+		list.Continue = "page-2"
+		return true, list, nil
+	})
+
+	rec := serve(h.ListServices, http.MethodGet, "/services", "/services?limit=1", "")
+	var page models.ListResponse
+	decodeResponse(t, rec, &page)
+	if rec.Code != http.StatusOK || page.Count != 1 || page.Continue != "page-2" {
+		t.Errorf("ListServices returned %d with %d items and token %q, want 1 item and page-2", rec.Code, page.Count, page.Continue)
+	}
+	if gotLimit != 1 {
+		t.Errorf("list limit = %d, want 1", gotLimit)
+	}
+}
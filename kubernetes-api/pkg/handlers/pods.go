@@ -1,26 +1,62 @@
 package handlers
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/index"
 	"kubernetes-api/pkg/k8s"
 	"kubernetes-api/pkg/models"
 	"kubernetes-api/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 type PodHandler struct {
 	k8sClient *k8s.K8sClient
+	activity  *activity.Log
+	uidIndex  *index.Index
+
+	// batchWorkers is how many pods CreatePodsBatch creates at once
+	batchWorkers int
+	// newExecutor creates the executor ExecInPod runs commands through
+	newExecutor executorFactory
+	// newDialer creates the dialer PortForward connects to pods through
+	newDialer dialerFactory
 }
 
-func NewPodHandler(client *k8s.K8sClient) *PodHandler {
-	return &PodHandler{k8sClient: client}
+func NewPodHandler(client *k8s.K8sClient, activityLog *activity.Log, uidIndex *index.Index) *PodHandler {
+	return &PodHandler{
+		k8sClient:    client,
+		activity:     activityLog,
+		uidIndex:     uidIndex,
+		batchWorkers: batchWorkersFromEnv(),
+		newExecutor:  newSPDYExecutor,
+		newDialer:    newSPDYDialer,
+	}
 }
 
 func (h *PodHandler) CreatePod(c *gin.Context) {
@@ -33,86 +69,246 @@ func (h *PodHandler) CreatePod(c *gin.Context) {
 		return
 	}
 
-	// Generate unique identifiers
-	uid := utils.GenerateUID()
-	podName := utils.GeneratePodName(utils.SanitizeName(req.Name))
+	waitReady := c.Query("wait") == "true"
+	timeout, err := time.ParseDuration(c.DefaultQuery("timeout", "30s"))
+	if err != nil || timeout <= 0 || timeout > maxReadyTimeout {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid timeout %q: must be a duration up to %s", c.Query("timeout"), maxReadyTimeout),
+		})
+		return
+	}
 
-	// Prepare labels
-	labels := map[string]string{
-		"app": req.Name,
-		"uid": uid,
+	createdPod, uid, status, err := h.createPod(req)
+	if err != nil {
+		c.JSON(status, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
 	}
-	for k, v := range req.Labels {
-		labels[k] = v
+
+	message := "Pod created successfully"
+	if waitReady {
+		var ready bool
+		createdPod, ready = h.waitForReady(c.Request.Context(), createdPod, timeout)
+		switch {
+		case ready:
+			message = "Pod created and ready"
+		case createdPod.Status.Phase == corev1.PodFailed || createdPod.Status.Phase == corev1.PodSucceeded:
+			message = fmt.Sprintf("Pod created but exited (status: %s)", createdPod.Status.Phase)
+		default:
+			message = fmt.Sprintf("Pod created but not ready after %s (status: %s)", timeout, createdPod.Status.Phase)
+		}
 	}
 
-	// Prepare environment variables
-	envVars := []corev1.EnvVar{
-		{Name: "POD_UID", Value: uid},
+	response := models.PodResponse{
+		UID:         uid,
+		Name:        createdPod.Name,
+		Namespace:   createdPod.Namespace,
+		Status:      string(createdPod.Status.Phase),
+		Image:       createdPod.Spec.Containers[0].Image,
+		Labels:      createdPod.Labels,
+		Annotations: createdPod.Annotations,
+		CreatedAt:   createdPod.CreationTimestamp.Time,
+		HostIP:      createdPod.Status.HostIP,
+		PodIP:       createdPod.Status.PodIP,
 	}
-	for k, v := range req.Env {
-		envVars = append(envVars, corev1.EnvVar{Name: k, Value: v})
+	for _, status := range createdPod.Status.ContainerStatuses {
+		response.Containers = append(response.Containers, containerStatus(status))
 	}
 
-	// Create pod specification
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:   podName,
-			Labels: labels,
-		},
-		Spec: corev1.PodSpec{
-			Containers: []corev1.Container{
-				{
-					Name:  req.ContainerName,
-					Image: req.Image,
-					Env:   envVars,
-				},
-			},
-		},
-	}
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    response,
+	})
+}
 
-	// Add port if specified
-	if req.Port > 0 {
-		pod.Spec.Containers[0].Ports = []corev1.ContainerPort{
-			{ContainerPort: req.Port},
-		}
+// maxBatchSize bounds the number of pods CreatePodsBatch accepts at once.
+const maxBatchSize = 50
+
+// BatchWorkersEnv names the environment variable setting how many pods
+// CreatePodsBatch creates in parallel, from 1 to maxBatchSize.
+const BatchWorkersEnv = "BATCH_CREATE_WORKERS"
+
+const defaultBatchWorkers = 4
+
+// batchWorkersFromEnv reads BatchWorkersEnv, falling back to
+// defaultBatchWorkers when it is unset or out of range.
+func batchWorkersFromEnv() int {
+	workers, err := strconv.Atoi(os.Getenv(BatchWorkersEnv))
+	if err != nil || workers < 1 || workers > maxBatchSize {
+		return defaultBatchWorkers
 	}
+	return workers
+}
 
-	// Create pod in cluster
-	createdPod, err := h.k8sClient.ClientSet.CoreV1().Pods("default").Create(
-		h.k8sClient.Context, pod, metav1.CreateOptions{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
+// CreatePodsBatch creates each pod in the request independently, up to
+// batchWorkers at a time. A failing item is reported in its result and does
+// not stop the others. Results are in request order.
+func (h *PodHandler) CreatePodsBatch(c *gin.Context) {
+	var req models.BatchCreatePodsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Success: false,
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	response := models.PodResponse{
-		UID:       uid,
-		Name:      createdPod.Name,
-		Namespace: createdPod.Namespace,
-		Status:    string(createdPod.Status.Phase),
-		Image:     req.Image,
-		Labels:    createdPod.Labels,
-		CreatedAt: createdPod.CreationTimestamp.Time,
+	if len(req.Pods) == 0 || len(req.Pods) > maxBatchSize {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("pods must contain between 1 and %d items", maxBatchSize),
+		})
+		return
 	}
 
-	c.JSON(http.StatusCreated, models.APIResponse{
+	// Each worker writes only the results of the indexes it takes
+	results := make([]models.BatchResult, len(req.Pods))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range max(1, min(h.batchWorkers, len(req.Pods))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = h.createBatchItem(i, req.Pods[i])
+			}
+		}()
+	}
+	for i := range req.Pods {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	response := models.BatchResponse{Results: results}
+	for _, result := range results {
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Pod created successfully",
+		Message: fmt.Sprintf("%d created, %d failed", response.Succeeded, response.Failed),
 		Data:    response,
 	})
 }
 
+// createBatchItem creates the pod at index i of a batch and reports the outcome.
+func (h *PodHandler) createBatchItem(i int, req models.CreatePodRequest) models.BatchResult {
+	result := models.BatchResult{Index: i, Name: req.Name}
+
+	createdPod, uid, _, err := h.createPod(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Success = true
+	result.UID = uid
+	result.PodName = createdPod.Name
+	result.Namespace = createdPod.Namespace
+	return result
+}
+
+// createPod validates req and creates the pod it describes, returning the
+// created pod and its UID, or the HTTP status to report alongside the error.
+func (h *PodHandler) createPod(req models.CreatePodRequest) (*corev1.Pod, string, int, error) {
+	namespace, err := resolveNamespace(req.Namespace)
+	if err != nil {
+		return nil, "", http.StatusBadRequest, err
+	}
+
+	if err := validatePodRequest(req); err != nil {
+		return nil, "", http.StatusBadRequest, err
+	}
+
+	uid, err := h.generatePodUID()
+	if err != nil {
+		return nil, "", http.StatusInternalServerError, err
+	}
+
+	pod, err := newManagedPod(req, uid)
+	if err != nil {
+		return nil, "", http.StatusBadRequest, err
+	}
+
+	if err := attachReferences(h.k8sClient, namespace, pod, req); err != nil {
+		if errors.Is(err, errConfigMapNotFound) || errors.Is(err, errSecretNotFound) {
+			return nil, "", http.StatusBadRequest, err
+		}
+		return nil, "", http.StatusInternalServerError, err
+	}
+
+	// Create pod in cluster
+	createdPod, err := h.k8sClient.ClientSet.CoreV1().Pods(namespace).Create(
+		h.k8sClient.Context, pod, metav1.CreateOptions{})
+	if err != nil {
+		return nil, "", http.StatusInternalServerError, err
+	}
+	h.activity.Record("create", "Pod", uid, createdPod.Name, createdPod.Namespace)
+	recordUID(h.k8sClient, h.uidIndex, "Pod", uid, createdPod.Namespace, createdPod.Name)
+
+	return createdPod, uid, http.StatusCreated, nil
+}
+
+// maxReadyTimeout bounds how long CreatePod and GetServiceByUID may block
+// with wait=true, and how long ProbeService waits for a response.
+const maxReadyTimeout = 5 * time.Minute
+
+// readyPollInterval is how often waitForReady re-reads the pod, and
+// waitForIngress the service.
+const readyPollInterval = time.Second
+
+// waitForReady polls pod until it is Ready, has exited, or timeout elapses.
+// It returns the last observed pod, so a timeout still reports the latest
+// phase, and whether the pod became ready.
+func (h *PodHandler) waitForReady(ctx context.Context, pod *corev1.Pod, timeout time.Duration) (*corev1.Pod, bool) {
+	ready := false
+	// A timeout is reported through the returned pod's phase, not an error
+	_ = wait.PollUntilContextTimeout(ctx, readyPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		current, err := h.k8sClient.ClientSet.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			// Transient API errors are retried until the timeout
+			return false, nil
+		}
+		pod = current
+
+		switch pod.Status.Phase {
+		case corev1.PodFailed, corev1.PodSucceeded:
+			return true, nil
+		case corev1.PodRunning:
+			ready = podReady(pod)
+			return ready, nil
+		}
+		return false, nil
+	})
+	return pod, ready
+}
+
+// podReady reports whether the pod's Ready condition is true.
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func (h *PodHandler) GetPodByUID(c *gin.Context) {
 	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
 
-	pods, err := h.k8sClient.ClientSet.CoreV1().Pods("default").List(
-		h.k8sClient.Context, metav1.ListOptions{
-			LabelSelector: "uid=" + uid,
-		})
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
@@ -120,8 +316,7 @@ func (h *PodHandler) GetPodByUID(c *gin.Context) {
 		})
 		return
 	}
-
-	if len(pods.Items) == 0 {
+	if pod == nil {
 		c.JSON(http.StatusNotFound, models.APIResponse{
 			Success: false,
 			Error:   "Pod not found",
@@ -129,16 +324,16 @@ func (h *PodHandler) GetPodByUID(c *gin.Context) {
 		return
 	}
 
-	pod := pods.Items[0]
 	response := models.PodResponse{
-		UID:       uid,
-		Name:      pod.Name,
-		Namespace: pod.Namespace,
-		Status:    string(pod.Status.Phase),
-		Labels:    pod.Labels,
-		CreatedAt: pod.CreationTimestamp.Time,
-		HostIP:    pod.Status.HostIP,
-		PodIP:     pod.Status.PodIP,
+		UID:         uid,
+		Name:        pod.Name,
+		Namespace:   pod.Namespace,
+		Status:      string(pod.Status.Phase),
+		Labels:      pod.Labels,
+		Annotations: pod.Annotations,
+		CreatedAt:   pod.CreationTimestamp.Time,
+		HostIP:      pod.Status.HostIP,
+		PodIP:       pod.Status.PodIP,
 	}
 
 	// Add safety check for container statuses
@@ -148,15 +343,74 @@ func (h *PodHandler) GetPodByUID(c *gin.Context) {
 		response.RestartCount = 0
 	}
 
+	for _, condition := range pod.Status.Conditions {
+		response.Conditions = append(response.Conditions, models.PodCondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Time,
+		})
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		response.Containers = append(response.Containers, containerStatus(status))
+	}
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Data:    response,
 	})
 }
 
+// containerStatus summarizes a container's current state and why it is in it.
+func containerStatus(status corev1.ContainerStatus) models.ContainerStatus {
+	info := models.ContainerStatus{
+		Name:         status.Name,
+		Ready:        status.Ready,
+		RestartCount: status.RestartCount,
+	}
+
+	switch {
+	case status.State.Waiting != nil:
+		info.State = "waiting"
+		info.Reason = status.State.Waiting.Reason
+		info.Message = status.State.Waiting.Message
+	case status.State.Running != nil:
+		info.State = "running"
+	case status.State.Terminated != nil:
+		info.State = "terminated"
+		info.Reason = status.State.Terminated.Reason
+		info.Message = status.State.Terminated.Message
+	}
+
+	if terminated := status.LastTerminationState.Terminated; terminated != nil {
+		info.LastTerminationReason = terminated.Reason
+	}
+	return info
+}
+
 func (h *PodHandler) ListPods(c *gin.Context) {
-	pods, err := h.k8sClient.ClientSet.CoreV1().Pods("default").List(
-		h.k8sClient.Context, metav1.ListOptions{})
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	opts, ok := pageOptions(c)
+	if !ok {
+		return
+	}
+
+	opts.LabelSelector = c.Query("labelSelector")
+	if _, err := labels.Parse(opts.LabelSelector); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid label selector: %v", err),
+		})
+		return
+	}
+
+	pods, err := h.k8sClient.ClientSet.CoreV1().Pods(namespace).List(
+		h.k8sClient.Context, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
@@ -168,14 +422,15 @@ func (h *PodHandler) ListPods(c *gin.Context) {
 	var podResponses []models.PodResponse
 	for _, pod := range pods.Items {
 		podResponse := models.PodResponse{
-			UID:       pod.Labels["uid"],
-			Name:      pod.Name,
-			Namespace: pod.Namespace,
-			Status:    string(pod.Status.Phase),
-			Labels:    pod.Labels,
-			CreatedAt: pod.CreationTimestamp.Time,
-			HostIP:    pod.Status.HostIP,
-			PodIP:     pod.Status.PodIP,
+			UID:         pod.Labels["uid"],
+			Name:        pod.Name,
+			Namespace:   pod.Namespace,
+			Status:      string(pod.Status.Phase),
+			Labels:      pod.Labels,
+			Annotations: pod.Annotations,
+			CreatedAt:   pod.CreationTimestamp.Time,
+			HostIP:      pod.Status.HostIP,
+			PodIP:       pod.Status.PodIP,
 		}
 		if len(pod.Status.ContainerStatuses) > 0 {
 			podResponse.RestartCount = pod.Status.ContainerStatuses[0].RestartCount
@@ -192,19 +447,30 @@ func (h *PodHandler) ListPods(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Data: models.ListResponse{
-			Items: items,
-			Count: len(podResponses),
+			Items:    items,
+			Count:    len(podResponses),
+			Continue: pods.Continue,
 		},
 	})
 }
 
 func (h *PodHandler) DeletePodByUID(c *gin.Context) {
 	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
 
-	pods, err := h.k8sClient.ClientSet.CoreV1().Pods("default").List(
-		h.k8sClient.Context, metav1.ListOptions{
-			LabelSelector: "uid=" + uid,
+	deleteOptions, err := podDeleteOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
 		})
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
@@ -212,8 +478,7 @@ func (h *PodHandler) DeletePodByUID(c *gin.Context) {
 		})
 		return
 	}
-
-	if len(pods.Items) == 0 {
+	if pod == nil {
 		c.JSON(http.StatusNotFound, models.APIResponse{
 			Success: false,
 			Error:   "Pod not found",
@@ -221,9 +486,8 @@ func (h *PodHandler) DeletePodByUID(c *gin.Context) {
 		return
 	}
 
-	pod := pods.Items[0]
-	err = h.k8sClient.ClientSet.CoreV1().Pods("default").Delete(
-		h.k8sClient.Context, pod.Name, metav1.DeleteOptions{})
+	err = h.k8sClient.ClientSet.CoreV1().Pods(namespace).Delete(
+		h.k8sClient.Context, pod.Name, deleteOptions)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
@@ -231,76 +495,1529 @@ func (h *PodHandler) DeletePodByUID(c *gin.Context) {
 		})
 		return
 	}
+	h.activity.Record("delete", "Pod", uid, pod.Name, pod.Namespace)
+	forgetUID(h.k8sClient, h.uidIndex, "Pod", uid)
+
+	if c.Query("cascade") != "true" {
+		c.JSON(http.StatusOK, models.APIResponse{
+			Success: true,
+			Message: "Pod deleted successfully",
+		})
+		return
+	}
 
+	response := h.deleteDependentServices(pod.Namespace, uid)
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Pod deleted successfully",
+		Message: fmt.Sprintf("Pod deleted successfully along with %d dependent services", len(response.CascadedServices)),
+		Data:    response,
 	})
 }
 
-func (h *PodHandler) GetPodLogs(c *gin.Context) {
-	uid := c.Param("uid")
-	lines := c.DefaultQuery("lines", "100")
-
-	lineCount, _ := strconv.ParseInt(lines, 10, 64)
+// podDeleteOptions reads the termination grace period for a pod deletion from
+// ?gracePeriodSeconds=, or ?force=true for an immediate delete. Without either
+// the pod's own grace period applies.
+func podDeleteOptions(c *gin.Context) (metav1.DeleteOptions, error) {
+	var options metav1.DeleteOptions
+	force := c.Query("force") == "true"
 
-	pods, err := h.k8sClient.ClientSet.CoreV1().Pods("default").List(
-		h.k8sClient.Context, metav1.ListOptions{
-			LabelSelector: "uid=" + uid,
-		})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
-		return
+	if value, ok := c.GetQuery("gracePeriodSeconds"); ok {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds < 0 {
+			return options, fmt.Errorf("invalid gracePeriodSeconds %q: must be a non-negative integer", value)
+		}
+		if force && seconds != 0 {
+			return options, fmt.Errorf("force=true deletes immediately and cannot be combined with gracePeriodSeconds=%d", seconds)
+		}
+		options.GracePeriodSeconds = &seconds
 	}
+	if force {
+		immediate := int64(0)
+		options.GracePeriodSeconds = &immediate
+	}
+	return options, nil
+}
 
-	if len(pods.Items) == 0 {
-		c.JSON(http.StatusNotFound, models.APIResponse{
-			Success: false,
-			Error:   "Pod not found",
-		})
+// ReconcilePods makes the managed pods match a desired set, keyed by their
+// "app" label: missing pods are created, and pods whose name is not in the set
+// (or duplicates of one that is) are deleted. Existing pods are never updated
+// in place. With dry_run set, the planned changes are reported but not applied.
+func (h *PodHandler) ReconcilePods(c *gin.Context) {
+	namespace, ok := queryNamespace(c)
+	if !ok {
 		return
 	}
 
-	pod := pods.Items[0]
-
-	// Check if pod is running
-	if pod.Status.Phase != corev1.PodRunning {
+	var req models.ReconcilePodsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Success: false,
-			Error:   fmt.Sprintf("Pod is not running (status: %s)", pod.Status.Phase),
+			Error:   err.Error(),
 		})
 		return
 	}
 
-	podLogOpts := corev1.PodLogOptions{
-		TailLines: &lineCount,
+	desired := make(map[string]models.CreatePodRequest)
+	for _, pod := range req.Pods {
+		if pod.Name == "" || (pod.Image == "" && len(pod.Containers) == 0) {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Every desired pod needs a name and an image or containers",
+			})
+			return
+		}
+		if err := validatePodRequest(pod); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Pod %q: %v", pod.Name, err),
+			})
+			return
+		}
+		if pod.Namespace != "" && pod.Namespace != namespace {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Pod %q is in namespace %q, but the reconcile is scoped to %q", pod.Name, pod.Namespace, namespace),
+			})
+			return
+		}
+		if _, exists := desired[pod.Name]; exists {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Pod name %q is listed more than once", pod.Name),
+			})
+			return
+		}
+		desired[pod.Name] = pod
 	}
 
-	req := h.k8sClient.ClientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &podLogOpts)
-	logs, err := req.Stream(h.k8sClient.Context)
+	pods, err := h.k8sClient.ClientSet.CoreV1().Pods(namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			LabelSelector: "uid",
+		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
-			Error:   fmt.Sprintf("Failed to get logs: %v", err),
+			Error:   err.Error(),
 		})
 		return
 	}
-	defer logs.Close()
 
-	// Read logs into buffer first to check if empty
-	logBytes, err := io.ReadAll(logs)
+	response := models.ReconcilePodsResponse{
+		DryRun:    req.DryRun,
+		Created:   []models.ReconcileAction{},
+		Deleted:   []models.ReconcileAction{},
+		Unchanged: []models.ReconcileAction{},
+	}
+
+	// Keep the first live pod for each desired name; everything else is extra
+	kept := make(map[string]bool)
+	for _, pod := range pods.Items {
+		action := models.ReconcileAction{
+			Name:    pod.Labels["app"],
+			UID:     pod.Labels["uid"],
+			PodName: pod.Name,
+		}
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if _, wanted := desired[action.Name]; wanted && !kept[action.Name] {
+			kept[action.Name] = true
+			response.Unchanged = append(response.Unchanged, action)
+			continue
+		}
+
+		if !req.DryRun {
+			err := h.k8sClient.ClientSet.CoreV1().Pods(pod.Namespace).Delete(
+				h.k8sClient.Context, pod.Name, metav1.DeleteOptions{})
+			if err != nil {
+				response.Errors = append(response.Errors, fmt.Sprintf("failed to delete pod %s: %v", pod.Name, err))
+				continue
+			}
+			h.activity.Record("delete", "Pod", action.UID, pod.Name, pod.Namespace)
+			forgetUID(h.k8sClient, h.uidIndex, "Pod", action.UID)
+		}
+		response.Deleted = append(response.Deleted, action)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(desired)) {
+		if kept[name] {
+			continue
+		}
+
+		uid, err := h.generatePodUID()
+		if err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("failed to build pod %s: %v", name, err))
+			continue
+		}
+		pod, err := newManagedPod(desired[name], uid)
+		if err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("failed to build pod %s: %v", name, err))
+			continue
+		}
+		if err := attachReferences(h.k8sClient, namespace, pod, desired[name]); err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("failed to build pod %s: %v", name, err))
+			continue
+		}
+		action := models.ReconcileAction{Name: name, UID: uid, PodName: pod.Name}
+		if !req.DryRun {
+			createdPod, err := h.k8sClient.ClientSet.CoreV1().Pods(namespace).Create(
+				h.k8sClient.Context, pod, metav1.CreateOptions{})
+			if err != nil {
+				response.Errors = append(response.Errors, fmt.Sprintf("failed to create pod %s: %v", name, err))
+				continue
+			}
+			action.PodName = createdPod.Name
+			h.activity.Record("create", "Pod", uid, createdPod.Name, createdPod.Namespace)
+			recordUID(h.k8sClient, h.uidIndex, "Pod", uid, createdPod.Namespace, createdPod.Name)
+		}
+		response.Created = append(response.Created, action)
+	}
+
+	// Individual failures are reported in the response rather than failing
+	// the whole request, since the other changes have already been applied
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("%d created, %d deleted, %d unchanged, %d failed",
+			len(response.Created), len(response.Deleted), len(response.Unchanged), len(response.Errors)),
+		Data: response,
+	})
+}
+
+// newManagedPod builds the pod described by req, labelled with uid.
+func newManagedPod(req models.CreatePodRequest, uid string) (*corev1.Pod, error) {
+	podName := utils.GeneratePodName(utils.SanitizeName(req.Name))
+
+	// Prepare labels
+	labels := map[string]string{
+		"app": req.Name,
+		"uid": uid,
+	}
+	for k, v := range req.Labels {
+		labels[k] = v
+	}
+
+	// The single-container fields are shorthand for a one-entry containers list
+	specs := req.Containers
+	if len(specs) == 0 {
+		specs = []models.ContainerSpec{{
+			Name:            req.ContainerName,
+			Image:           req.Image,
+			Port:            req.Port,
+			Ports:           req.Ports,
+			Command:         req.Command,
+			Args:            req.Args,
+			Resources:       req.Resources,
+			ImagePullPolicy: req.ImagePullPolicy,
+			VolumeMounts:    req.VolumeMounts,
+		}}
+	}
+
+	var containers []corev1.Container
+	for _, spec := range specs {
+		// Prepare environment variables; pod-wide env applies to every container
+		envVars := []corev1.EnvVar{
+			{Name: "POD_UID", Value: uid},
+		}
+		for k, v := range req.Env {
+			envVars = append(envVars, corev1.EnvVar{Name: k, Value: v})
+		}
+		envVars = mergeEnv(envVars, spec.Env)
+
+		resources, err := resourceRequirements(spec.Resources)
+		if err != nil {
+			return nil, fmt.Errorf("container %q: %w", spec.Name, err)
+		}
+
+		container := corev1.Container{
+			Name:            spec.Name,
+			Image:           spec.Image,
+			Command:         spec.Command,
+			Args:            spec.Args,
+			Env:             envVars,
+			Resources:       resources,
+			ImagePullPolicy: corev1.PullPolicy(spec.ImagePullPolicy),
+			VolumeMounts:    volumeMounts(spec.VolumeMounts),
+		}
+
+		if container.Ports, err = containerPorts(spec); err != nil {
+			return nil, fmt.Errorf("container %q: %w", spec.Name, err)
+		}
+
+		containers = append(containers, container)
+	}
+
+	volumes, err := podVolumes(req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Error:   fmt.Sprintf("Failed to read logs: %v", err),
-		})
-		return
+		return nil, err
 	}
 
-	c.Header("Content-Type", "text/plain")
-	c.Status(http.StatusOK)
-	c.Writer.Write(logBytes)
+	// Create pod specification
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        podName,
+			Labels:      labels,
+			Annotations: req.Annotations,
+		},
+		Spec: corev1.PodSpec{
+			Containers: containers,
+			Volumes:    volumes,
+			// Empty leaves the Kubernetes default of Always
+			RestartPolicy: corev1.RestartPolicy(req.RestartPolicy),
+		},
+	}
+	for _, name := range req.ImagePullSecrets {
+		pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: name})
+	}
+
+	pod.Spec.NodeSelector = req.NodeSelector
+	if len(req.NodeAffinity) > 0 {
+		// A single term, so every requirement must hold on the chosen node
+		var term corev1.NodeSelectorTerm
+		for _, requirement := range req.NodeAffinity {
+			term.MatchExpressions = append(term.MatchExpressions, corev1.NodeSelectorRequirement{
+				Key:      requirement.Key,
+				Operator: corev1.NodeSelectorOperator(requirement.Operator),
+				Values:   requirement.Values,
+			})
+		}
+		pod.Spec.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{term},
+				},
+			},
+		}
+	}
+
+	return pod, nil
+}
+
+// attachReferences resolves the ConfigMaps and Secrets req refers to in
+// namespace and wires them into pod. Image pull secrets are already set on pod
+// and are only checked to exist.
+func attachReferences(client *k8s.K8sClient, namespace string, pod *corev1.Pod, req models.CreatePodRequest) error {
+	configMapNames, err := resolveConfigMaps(client, namespace, req.ConfigMapRefs)
+	if err != nil {
+		return err
+	}
+	secretNames, err := resolveSecrets(client, namespace, req.SecretEnv)
+	if err != nil {
+		return err
+	}
+	for _, name := range req.ImagePullSecrets {
+		_, err := client.ClientSet.CoreV1().Secrets(namespace).Get(client.Context, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: image pull secret %s in namespace %s", errSecretNotFound, name, namespace)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	attachConfigMaps(pod, req.ConfigMapRefs, configMapNames)
+	attachSecretEnv(pod, req.SecretEnv, secretNames)
+	return nil
+}
+
+// validatePodRequest checks the containers list of a create request, which
+// replaces the single-container fields, every container's resources, and the
+// shape of its ConfigMap and Secret references and volumes. Container names
+// must be unique DNS-1123 labels.
+func validatePodRequest(req models.CreatePodRequest) error {
+	if err := validateConfigMapRefs(req.ConfigMapRefs); err != nil {
+		return err
+	}
+	if err := validateSecretEnv(req.SecretEnv); err != nil {
+		return err
+	}
+
+	for _, name := range req.ImagePullSecrets {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("invalid image pull secret %q: %s", name, strings.Join(errs, ", "))
+		}
+	}
+	if err := validateNodeScheduling(req); err != nil {
+		return err
+	}
+	if err := validateAnnotations(req.Annotations); err != nil {
+		return err
+	}
+	if err := validateVolumes(req); err != nil {
+		return err
+	}
+	switch corev1.RestartPolicy(req.RestartPolicy) {
+	case "", corev1.RestartPolicyAlways, corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever:
+	default:
+		return fmt.Errorf("invalid restart_policy %q: must be Always, OnFailure or Never", req.RestartPolicy)
+	}
+
+	if len(req.Containers) == 0 {
+		if err := validatePullPolicy(req.ImagePullPolicy); err != nil {
+			return err
+		}
+		if _, err := containerPorts(models.ContainerSpec{Port: req.Port, Ports: req.Ports}); err != nil {
+			return err
+		}
+		_, err := resourceRequirements(req.Resources)
+		return err
+	}
+	if req.Image != "" || req.ContainerName != "" || req.Port != 0 || len(req.Ports) > 0 || req.Resources != nil || req.ImagePullPolicy != "" ||
+		len(req.Command) > 0 || len(req.Args) > 0 || len(req.VolumeMounts) > 0 {
+		return fmt.Errorf("specify either image/container_name/port/ports/command/args/resources/image_pull_policy/volume_mounts or containers, not both")
+	}
+
+	names := make(map[string]bool)
+	for _, container := range req.Containers {
+		if errs := validation.IsDNS1123Label(container.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid container name %q: %s", container.Name, strings.Join(errs, ", "))
+		}
+		if names[container.Name] {
+			return fmt.Errorf("duplicate container name %q", container.Name)
+		}
+		names[container.Name] = true
+
+		if container.Image == "" {
+			return fmt.Errorf("container %q: image is required", container.Name)
+		}
+		if _, err := containerPorts(container); err != nil {
+			return fmt.Errorf("container %q: %w", container.Name, err)
+		}
+		if _, err := resourceRequirements(container.Resources); err != nil {
+			return fmt.Errorf("container %q: %w", container.Name, err)
+		}
+		if err := validatePullPolicy(container.ImagePullPolicy); err != nil {
+			return fmt.Errorf("container %q: %w", container.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateAnnotations checks annotation keys and the total size the way the
+// API server does, so bad annotations fail with a 400 before anything is
+// created.
+func validateAnnotations(annotations map[string]string) error {
+	return apivalidation.ValidateAnnotations(annotations, field.NewPath("annotations")).ToAggregate()
+}
+
+// validateNodeScheduling checks the node selector and required node affinity
+// of a create request. Keys must be qualified label names and values valid
+// label values; the number of values must suit the operator.
+func validateNodeScheduling(req models.CreatePodRequest) error {
+	for key, value := range req.NodeSelector {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("node_selector: invalid key %q: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("node_selector: invalid value %q for %s: %s", value, key, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, requirement := range req.NodeAffinity {
+		if errs := validation.IsQualifiedName(requirement.Key); len(errs) > 0 {
+			return fmt.Errorf("required_node_affinity: invalid key %q: %s", requirement.Key, strings.Join(errs, ", "))
+		}
+		for _, value := range requirement.Values {
+			if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+				return fmt.Errorf("required_node_affinity: invalid value %q for %s: %s", value, requirement.Key, strings.Join(errs, ", "))
+			}
+		}
+
+		switch corev1.NodeSelectorOperator(requirement.Operator) {
+		case corev1.NodeSelectorOpIn, corev1.NodeSelectorOpNotIn:
+			if len(requirement.Values) == 0 {
+				return fmt.Errorf("required_node_affinity: %s %s needs at least one value", requirement.Key, requirement.Operator)
+			}
+		case corev1.NodeSelectorOpExists, corev1.NodeSelectorOpDoesNotExist:
+			if len(requirement.Values) > 0 {
+				return fmt.Errorf("required_node_affinity: %s %s takes no values", requirement.Key, requirement.Operator)
+			}
+		case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+			if len(requirement.Values) != 1 {
+				return fmt.Errorf("required_node_affinity: %s %s needs exactly one integer value", requirement.Key, requirement.Operator)
+			}
+			if _, err := strconv.ParseInt(requirement.Values[0], 10, 64); err != nil {
+				return fmt.Errorf("required_node_affinity: %s %s needs an integer value, got %q", requirement.Key, requirement.Operator, requirement.Values[0])
+			}
+		default:
+			return fmt.Errorf("required_node_affinity: invalid operator %q for %s: must be In, NotIn, Exists, DoesNotExist, Gt or Lt", requirement.Operator, requirement.Key)
+		}
+	}
+	return nil
+}
+
+// containerPorts builds the ports of the container described by spec, either
+// the single port shorthand or the ports list. Names, when given, must be
+// valid and unique so services can target them.
+func containerPorts(spec models.ContainerSpec) ([]corev1.ContainerPort, error) {
+	specs := spec.Ports
+	if spec.Port != 0 {
+		if len(specs) > 0 {
+			return nil, fmt.Errorf("specify either port or ports, not both")
+		}
+		specs = []models.ContainerPortSpec{{ContainerPort: spec.Port}}
+	}
+
+	names := make(map[string]bool)
+	seen := make(map[string]bool)
+	var ports []corev1.ContainerPort
+	for _, port := range specs {
+		if errs := validation.IsValidPortNum(int(port.ContainerPort)); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid port %d: %s", port.ContainerPort, strings.Join(errs, ", "))
+		}
+
+		protocol := corev1.ProtocolTCP
+		if port.Protocol != "" {
+			protocol = corev1.Protocol(strings.ToUpper(port.Protocol))
+		}
+		switch protocol {
+		case corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP:
+		default:
+			return nil, fmt.Errorf("invalid protocol %q: must be TCP, UDP or SCTP", port.Protocol)
+		}
+
+		if port.Name != "" {
+			if errs := validation.IsValidPortName(port.Name); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid port name %q: %s", port.Name, strings.Join(errs, ", "))
+			}
+			if names[port.Name] {
+				return nil, fmt.Errorf("duplicate port name %q", port.Name)
+			}
+			names[port.Name] = true
+		}
+
+		key := fmt.Sprintf("%d/%s", port.ContainerPort, protocol)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate port %s", key)
+		}
+		seen[key] = true
+
+		ports = append(ports, corev1.ContainerPort{
+			Name:          port.Name,
+			ContainerPort: port.ContainerPort,
+			Protocol:      protocol,
+		})
+	}
+	return ports, nil
+}
+
+// validatePullPolicy accepts an empty policy, which leaves the Kubernetes
+// default in place.
+func validatePullPolicy(policy string) error {
+	switch corev1.PullPolicy(policy) {
+	case "", corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+		return nil
+	}
+	return fmt.Errorf("invalid image_pull_policy %q: must be Always, IfNotPresent or Never", policy)
+}
+
+// resourceRequirements parses the cpu and memory quantities of spec. A nil
+// spec leaves the container without requests or limits.
+func resourceRequirements(spec *models.ResourceSpec) (corev1.ResourceRequirements, error) {
+	var requirements corev1.ResourceRequirements
+	if spec == nil {
+		return requirements, nil
+	}
+
+	var err error
+	if requirements.Requests, err = resourceList("requests", spec.Requests); err != nil {
+		return requirements, err
+	}
+	if requirements.Limits, err = resourceList("limits", spec.Limits); err != nil {
+		return requirements, err
+	}
+
+	for name, limit := range requirements.Limits {
+		if request, ok := requirements.Requests[name]; ok && request.Cmp(limit) > 0 {
+			return requirements, fmt.Errorf("%s request %s exceeds its limit %s", name, request.String(), limit.String())
+		}
+	}
+	return requirements, nil
+}
+
+func resourceList(field string, quantities map[string]string) (corev1.ResourceList, error) {
+	if len(quantities) == 0 {
+		return nil, nil
+	}
+
+	list := corev1.ResourceList{}
+	for name, value := range quantities {
+		switch corev1.ResourceName(name) {
+		case corev1.ResourceCPU, corev1.ResourceMemory:
+		default:
+			return nil, fmt.Errorf("unsupported resource %q in %s: must be cpu or memory", name, field)
+		}
+
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %s quantity %q: %v", name, field, value, err)
+		}
+		list[corev1.ResourceName(name)] = quantity
+	}
+	return list, nil
+}
+
+// deleteDependentServices removes the managed services that select only the
+// pod with the given UID. Services with any other selector terms are left
+// alone, since they may still match other pods.
+func (h *PodHandler) deleteDependentServices(namespace, uid string) models.DeletePodResponse {
+	response := models.DeletePodResponse{CascadedServices: []string{}}
+
+	services, err := h.k8sClient.ClientSet.CoreV1().Services(namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			LabelSelector: "uid",
+		})
+	if err != nil {
+		response.CascadeErrors = append(response.CascadeErrors, fmt.Sprintf("failed to list services: %v", err))
+		return response
+	}
+
+	for _, service := range services.Items {
+		if len(service.Spec.Selector) != 1 || service.Spec.Selector["uid"] != uid {
+			continue
+		}
+
+		err := h.k8sClient.ClientSet.CoreV1().Services(namespace).Delete(
+			h.k8sClient.Context, service.Name, metav1.DeleteOptions{})
+		if err != nil {
+			response.CascadeErrors = append(response.CascadeErrors, fmt.Sprintf("failed to delete service %s: %v", service.Name, err))
+			continue
+		}
+		h.activity.Record("delete", "Service", service.Labels["uid"], service.Name, service.Namespace)
+		forgetUID(h.k8sClient, h.uidIndex, "Service", service.Labels["uid"])
+		response.CascadedServices = append(response.CascadedServices, service.Name)
+	}
+
+	return response
+}
+
+func (h *PodHandler) GetPodLogs(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+	lines := c.DefaultQuery("lines", "100")
+	previous := c.Query("previous") == "true"
+
+	lineCount, _ := strconv.ParseInt(lines, 10, 64)
+
+	if previous && c.Query("follow") == "true" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "previous and follow cannot be combined: the previous container has already exited",
+		})
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if pod == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+
+	// A pending pod has no container output yet, unless an earlier
+	// instance ran before it was restarted
+	if pod.Status.Phase == corev1.PodPending && !previous {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Pod has not started yet (status: %s)", pod.Status.Phase),
+		})
+		return
+	}
+
+	container, err := logContainer(pod, c.Query("container"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	podLogOpts := corev1.PodLogOptions{
+		Container: container,
+		TailLines: &lineCount,
+		Previous:  previous,
+	}
+
+	if c.Query("follow") == "true" {
+		h.followPodLogs(c, pod, &podLogOpts)
+		return
+	}
+
+	req := h.k8sClient.ClientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &podLogOpts)
+	logs, err := req.Stream(h.k8sClient.Context)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to get logs: %v", err),
+		})
+		return
+	}
+	defer logs.Close()
+
+	// Read logs into buffer first to check if empty
+	logBytes, err := io.ReadAll(logs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to read logs: %v", err),
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/plain")
+	c.Status(http.StatusOK)
+	c.Writer.Write(logBytes)
+}
+
+// logContainer picks the container to read logs from. With no name given, the
+// only container is used; a pod with several needs an explicit choice.
+func logContainer(pod *corev1.Pod, name string) (string, error) {
+	names := make([]string, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+
+	switch {
+	case name != "" && slices.Contains(names, name):
+		return name, nil
+	case name != "":
+		return "", fmt.Errorf("container %q not found in pod, available containers: %s", name, strings.Join(names, ", "))
+	case len(names) == 1:
+		return names[0], nil
+	default:
+		return "", fmt.Errorf("pod has multiple containers, specify one with ?container=: %s", strings.Join(names, ", "))
+	}
+}
+
+// maxLogLineLength bounds a single streamed log line; longer lines end the stream.
+const maxLogLineLength = 1024 * 1024
+
+// followPodLogs streams a pod's logs as server-sent events, one "log" event per
+// line, until the container exits or the client disconnects.
+func (h *PodHandler) followPodLogs(c *gin.Context, pod *corev1.Pod, opts *corev1.PodLogOptions) {
+	opts.Follow = true
+
+	// The request context is cancelled when the client goes away, which
+	// closes the log stream and ends the loop below
+	req := h.k8sClient.ClientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts)
+	logs, err := req.Stream(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to get logs: %v", err),
+		})
+		return
+	}
+	defer logs.Close()
+
+	streamLogEvents(c, logs)
+}
+
+// streamLogEvents writes each line read from logs as a "log" event, flushing
+// after every line, and ends with an "end" or "error" event unless the client
+// has gone away.
+func streamLogEvents(c *gin.Context, logs io.Reader) {
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineLength)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Send the headers now; a quiet container may not log for a while
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	c.Stream(func(w io.Writer) bool {
+		if scanner.Scan() {
+			c.SSEvent("log", scanner.Text())
+			return true
+		}
+
+		switch {
+		case c.Request.Context().Err() != nil:
+			// Client disconnected, nobody left to tell
+		case scanner.Err() != nil:
+			c.SSEvent("error", scanner.Err().Error())
+		default:
+			c.SSEvent("end", "log stream closed")
+		}
+		return false
+	})
+}
+
+func (h *PodHandler) GetPodRestarts(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if pod == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+
+	response := models.PodRestartsResponse{
+		UID:        uid,
+		Name:       pod.Name,
+		Containers: []models.ContainerRestartInfo{},
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		info := models.ContainerRestartInfo{
+			Name:         status.Name,
+			RestartCount: status.RestartCount,
+		}
+
+		// LastTerminationState is only populated once the container has restarted
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			exitCode := terminated.ExitCode
+			finishedAt := terminated.FinishedAt.Time
+			info.LastTerminationReason = terminated.Reason
+			info.LastTerminationMessage = terminated.Message
+			info.LastExitCode = &exitCode
+			info.LastTerminatedAt = &finishedAt
+		}
+
+		response.Containers = append(response.Containers, info)
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// UpdatePodEnv merges env vars into a pod's containers. Env is immutable on a
+// running pod, so the pod is recreated from its existing spec, keeping its
+// labels (and therefore its UID) intact. Protected pods are rejected.
+func (h *PodHandler) UpdatePodEnv(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePodEnvRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(req.Env) == 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "env must contain at least one variable",
+		})
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if pod == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+
+	if reason := protectedPodReason(pod); reason != "" {
+		c.JSON(http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   reason,
+		})
+		return
+	}
+
+	replacement := recreatablePod(pod)
+
+	matched := false
+	for i := range replacement.Spec.Containers {
+		container := &replacement.Spec.Containers[i]
+		if req.Container != "" && container.Name != req.Container {
+			continue
+		}
+		matched = true
+		container.Env = mergeEnv(container.Env, req.Env)
+	}
+
+	if !matched {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Container %q not found in pod", req.Container),
+		})
+		return
+	}
+
+	createdPod, err := h.replacePod(pod, replacement)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response := models.PodResponse{
+		UID:         uid,
+		Name:        createdPod.Name,
+		Namespace:   createdPod.Namespace,
+		Status:      string(createdPod.Status.Phase),
+		Image:       createdPod.Spec.Containers[0].Image,
+		Labels:      createdPod.Labels,
+		Annotations: createdPod.Annotations,
+		CreatedAt:   createdPod.CreationTimestamp.Time,
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Pod recreated with updated environment",
+		Data:    response,
+	})
+}
+
+// UpdatePodLabels sets and removes labels on a pod in place with a strategic
+// merge patch. The uid label identifies the pod and cannot be changed.
+func (h *PodHandler) UpdatePodLabels(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePodLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := validateLabelUpdate(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if pod == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+
+	// A null value removes the label
+	changes := make(map[string]interface{})
+	for key, value := range req.Set {
+		changes[key] = value
+	}
+	for _, key := range req.Remove {
+		changes[key] = nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": changes},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	pod, err = h.k8sClient.ClientSet.CoreV1().Pods(namespace).Patch(
+		h.k8sClient.Context, pod.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Pod labels updated successfully",
+		Data: models.PodLabelsResponse{
+			UID:    uid,
+			Name:   pod.Name,
+			Labels: pod.Labels,
+		},
+	})
+}
+
+// validateLabelUpdate checks the keys and values of a label update and that
+// it leaves the uid label alone.
+func validateLabelUpdate(req models.UpdatePodLabelsRequest) error {
+	if len(req.Set) == 0 && len(req.Remove) == 0 {
+		return fmt.Errorf("set or remove must contain at least one label")
+	}
+
+	for key, value := range req.Set {
+		if key == "uid" {
+			return fmt.Errorf("the uid label identifies the pod and cannot be changed")
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid value %q for label %s: %s", value, key, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, key := range req.Remove {
+		if key == "uid" {
+			return fmt.Errorf("the uid label identifies the pod and cannot be removed")
+		}
+		if _, ok := req.Set[key]; ok {
+			return fmt.Errorf("label %s is both set and removed", key)
+		}
+	}
+	return nil
+}
+
+// PodOperation runs a lifecycle operation on a pod. restart and delete work on
+// any pod; a bare pod cannot be paused, so stop and start scale the pod's
+// owning deployment instead and are rejected for pods without one. Pods owned
+// by a controller are restarted by deleting them and letting the controller
+// create the replacement.
+func (h *PodHandler) PodOperation(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	var req models.PodOperationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if req.UID != "" && req.UID != uid {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Body uid %q does not match path uid %q", req.UID, uid),
+		})
+		return
+	}
+
+	switch req.Operation {
+	case "restart", "delete", "stop", "start":
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid operation %q: must be start, stop, restart or delete", req.Operation),
+		})
+		return
+	}
+
+	if req.Operation == "stop" || req.Operation == "start" {
+		h.scaleForOperation(c, namespace, uid, req.Operation)
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if pod == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+
+	response := models.PodOperationResponse{
+		UID:       uid,
+		Operation: req.Operation,
+		PodName:   pod.Name,
+	}
+
+	switch req.Operation {
+	case "restart":
+		// Recreating an owned pod ourselves would leave an extra, unmanaged
+		// pod next to the one its controller creates
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			err := h.k8sClient.ClientSet.CoreV1().Pods(pod.Namespace).Delete(
+				h.k8sClient.Context, pod.Name, metav1.DeleteOptions{})
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, models.APIResponse{
+					Success: false,
+					Error:   err.Error(),
+				})
+				return
+			}
+			h.activity.Record("delete", "Pod", uid, pod.Name, pod.Namespace)
+
+			c.JSON(http.StatusOK, models.APIResponse{
+				Success: true,
+				Message: fmt.Sprintf("Pod deleted; its %s %s will recreate it", owner.Kind, owner.Name),
+				Data:    response,
+			})
+			return
+		}
+
+		createdPod, err := h.replacePod(pod, recreatablePod(pod))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		response.PodName = createdPod.Name
+
+		c.JSON(http.StatusOK, models.APIResponse{
+			Success: true,
+			Message: "Pod restarted",
+			Data:    response,
+		})
+
+	case "delete":
+		err := h.k8sClient.ClientSet.CoreV1().Pods(pod.Namespace).Delete(
+			h.k8sClient.Context, pod.Name, metav1.DeleteOptions{})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		h.activity.Record("delete", "Pod", uid, pod.Name, pod.Namespace)
+		forgetUID(h.k8sClient, h.uidIndex, "Pod", uid)
+
+		c.JSON(http.StatusOK, models.APIResponse{
+			Success: true,
+			Message: "Pod deleted successfully",
+			Data:    response,
+		})
+
+	}
+}
+
+// scaleForOperation stops or starts the deployment behind uid by scaling it to
+// zero or back to at least one replica.
+func (h *PodHandler) scaleForOperation(c *gin.Context, namespace, uid, operation string) {
+	deployment, found, err := h.deploymentForUID(namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+	if deployment == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Pod has no owning deployment; bare pods cannot be stopped or started, use restart or delete instead",
+		})
+		return
+	}
+
+	client := h.k8sClient.ClientSet.AppsV1().Deployments(namespace)
+	scale, err := client.GetScale(h.k8sClient.Context, deployment, metav1.GetOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// start only brings a stopped deployment back; a running one keeps its count
+	replicas := int32(0)
+	if operation == "start" {
+		replicas = max(scale.Spec.Replicas, 1)
+	}
+	if scale.Spec.Replicas != replicas {
+		scale.Spec.Replicas = replicas
+		if _, err := client.UpdateScale(h.k8sClient.Context, deployment, scale, metav1.UpdateOptions{}); err != nil {
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Deployment %s scaled to %d replicas", deployment, replicas),
+		Data: models.PodOperationResponse{
+			UID:        uid,
+			Operation:  operation,
+			Deployment: deployment,
+			Replicas:   &replicas,
+		},
+	})
+}
+
+// deploymentForUID finds the deployment to stop or start for uid. Deployments
+// created through the API carry uid as their uid label, and their pods carry
+// it as deployment-uid; that lookup also works while the deployment is
+// stopped and has no pods. Otherwise uid names a managed pod, and its owning
+// deployment is used. found is false when uid matches neither; deployment is
+// "" when the pod has no owning deployment.
+func (h *PodHandler) deploymentForUID(namespace, uid string) (deployment string, found bool, err error) {
+	deployments, err := h.k8sClient.ClientSet.AppsV1().Deployments(namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			LabelSelector: "uid=" + uid,
+		})
+	if err != nil {
+		return "", false, err
+	}
+	if len(deployments.Items) > 0 {
+		return deployments.Items[0].Name, true, nil
+	}
+
+	pods, err := h.k8sClient.ClientSet.CoreV1().Pods(namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			LabelSelector: "deployment-uid=" + uid,
+		})
+	if err != nil {
+		return "", false, err
+	}
+
+	var pod *corev1.Pod
+	if len(pods.Items) > 0 {
+		pod = &pods.Items[0]
+	} else if pod, err = podByUID(h.k8sClient, h.uidIndex, namespace, uid); err != nil || pod == nil {
+		return "", false, err
+	}
+
+	deployment, err = h.owningDeployment(pod)
+	return deployment, true, err
+}
+
+// owningDeployment returns the name of the deployment that manages pod through
+// a replica set, or "" when the pod has no such owner.
+func (h *PodHandler) owningDeployment(pod *corev1.Pod) (string, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "ReplicaSet" {
+		return "", nil
+	}
+
+	replicaSet, err := h.k8sClient.ClientSet.AppsV1().ReplicaSets(pod.Namespace).Get(
+		h.k8sClient.Context, owner.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get replica set %s: %w", owner.Name, err)
+	}
+
+	owner = metav1.GetControllerOf(replicaSet)
+	if owner == nil || owner.Kind != "Deployment" {
+		return "", nil
+	}
+	return owner.Name, nil
+}
+
+// replacePod creates replacement and then deletes original. The replacement
+// is created first, so a rejected spec leaves the original pod running.
+func (h *PodHandler) replacePod(original, replacement *corev1.Pod) (*corev1.Pod, error) {
+	uid := original.Labels["uid"]
+	pods := h.k8sClient.ClientSet.CoreV1().Pods(original.Namespace)
+
+	createdPod, err := pods.Create(h.k8sClient.Context, replacement, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Replacement pod could not be created, the original is unchanged: %v", err)
+	}
+
+	err = pods.Delete(h.k8sClient.Context, original.Name, metav1.DeleteOptions{})
+	if err != nil {
+		// Keep a single pod per uid; the original is still running
+		pods.Delete(h.k8sClient.Context, createdPod.Name, metav1.DeleteOptions{})
+		return nil, fmt.Errorf("Original pod could not be deleted, the replacement was discarded: %v", err)
+	}
+
+	h.activity.Record("create", "Pod", uid, createdPod.Name, createdPod.Namespace)
+	h.activity.Record("delete", "Pod", uid, original.Name, original.Namespace)
+	recordUID(h.k8sClient, h.uidIndex, "Pod", uid, createdPod.Namespace, createdPod.Name)
+
+	return createdPod, nil
+}
+
+// systemNamespaces hold cluster components, which are never recreated through the API.
+var systemNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// protectedPodReason returns why pod must not be deleted and recreated by the
+// API, or "" if it may be. Pods of system namespaces are protected, and so are
+// pods owned by a controller, which would recreate them from its own template.
+func protectedPodReason(pod *corev1.Pod) string {
+	if slices.Contains(systemNamespaces, pod.Namespace) {
+		return fmt.Sprintf("Pod %s is in the system namespace %s and cannot be recreated", pod.Name, pod.Namespace)
+	}
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		return fmt.Sprintf("Pod %s is managed by %s %s; change its template instead", pod.Name, owner.Kind, owner.Name)
+	}
+	return ""
+}
+
+// recreatablePod returns a copy of pod that can be submitted to Create again.
+// The copy gets a fresh name so it does not collide with the terminating original.
+func recreatablePod(pod *corev1.Pod) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        utils.GeneratePodName(utils.SanitizeName(pod.Labels["app"])),
+			Namespace:   pod.Namespace,
+			Labels:      pod.Labels,
+			Annotations: pod.Annotations,
+		},
+		Spec: cleanPodSpec(pod.Spec),
+	}
+}
+
+// cleanPodSpec returns a copy of spec without the fields the cluster fills in
+// on admission and scheduling, so it can be used to create a new pod.
+func cleanPodSpec(spec corev1.PodSpec) corev1.PodSpec {
+	cleaned := *spec.DeepCopy()
+	cleaned.NodeName = ""
+
+	// The service account token volume is injected again on admission
+	cleaned.Volumes = slices.DeleteFunc(cleaned.Volumes, func(v corev1.Volume) bool {
+		return strings.HasPrefix(v.Name, "kube-api-access-")
+	})
+	for i := range cleaned.Containers {
+		cleaned.Containers[i].VolumeMounts = slices.DeleteFunc(cleaned.Containers[i].VolumeMounts, func(m corev1.VolumeMount) bool {
+			return strings.HasPrefix(m.Name, "kube-api-access-")
+		})
+	}
+
+	return cleaned
+}
+
+// mergeEnv sets the given variables on env, replacing existing ones by name.
+func mergeEnv(env []corev1.EnvVar, vars map[string]string) []corev1.EnvVar {
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		value := vars[name]
+		i := slices.IndexFunc(env, func(e corev1.EnvVar) bool { return e.Name == name })
+		if i == -1 {
+			env = append(env, corev1.EnvVar{Name: name, Value: value})
+			continue
+		}
+		env[i] = corev1.EnvVar{Name: name, Value: value}
+	}
+	return env
+}
+
+// schedulingCauses maps fragments of scheduler messages to a short summary.
+var schedulingCauses = []struct {
+	fragment string
+	cause    string
+}{
+	{"Insufficient cpu", "insufficient cpu"},
+	{"Insufficient memory", "insufficient memory"},
+	{"Insufficient ephemeral-storage", "insufficient ephemeral storage"},
+	{"didn't match Pod's node affinity/selector", "node selector/affinity mismatch"},
+	{"untolerated taint", "untolerated node taints"},
+	{"didn't have free ports", "host port conflict"},
+	{"persistentvolumeclaim", "unbound persistent volume claim"},
+	{"node(s) were unschedulable", "nodes cordoned"},
+}
+
+func (h *PodHandler) GetPodScheduling(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if pod == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+
+	response := models.PodSchedulingResponse{
+		UID:      uid,
+		Name:     pod.Name,
+		Status:   string(pod.Status.Phase),
+		NodeName: pod.Spec.NodeName,
+		Causes:   []string{},
+		Events:   []models.SchedulingEvent{},
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled {
+			response.Scheduled = condition.Status == corev1.ConditionTrue
+			response.Reason = condition.Reason
+			response.Message = condition.Message
+		}
+	}
+
+	events, err := h.k8sClient.ClientSet.CoreV1().Events(pod.Namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			FieldSelector: "involvedObject.name=" + pod.Name + ",reason=FailedScheduling",
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to list events: %v", err),
+		})
+		return
+	}
+
+	messages := []string{response.Message}
+	for _, event := range events.Items {
+		response.Events = append(response.Events, models.SchedulingEvent{
+			Reason:        event.Reason,
+			Message:       event.Message,
+			Count:         event.Count,
+			LastTimestamp: event.LastTimestamp.Time,
+		})
+		messages = append(messages, event.Message)
+	}
+
+	for _, known := range schedulingCauses {
+		if slices.ContainsFunc(messages, func(m string) bool { return strings.Contains(m, known.fragment) }) {
+			response.Causes = append(response.Causes, known.cause)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// GetPodEvents lists the events involving a pod, oldest first
+func (h *PodHandler) GetPodEvents(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if pod == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+
+	events, err := h.k8sClient.ClientSet.CoreV1().Events(pod.Namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			FieldSelector: "involvedObject.name=" + pod.Name,
+		})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to list events: %v", err),
+		})
+		return
+	}
+
+	response := models.PodEventsResponse{
+		UID:    uid,
+		Name:   pod.Name,
+		Events: []models.PodEvent{},
+	}
+
+	for _, event := range events.Items {
+		response.Events = append(response.Events, models.PodEvent{
+			Type:          event.Type,
+			Reason:        event.Reason,
+			Message:       event.Message,
+			Count:         event.Count,
+			LastTimestamp: eventTime(event),
+		})
+	}
+
+	slices.SortStableFunc(response.Events, func(a, b models.PodEvent) int {
+		return a.LastTimestamp.Compare(b.LastTimestamp)
+	})
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// eventTime returns when an event last occurred. Events recorded through the
+// events.k8s.io API leave LastTimestamp unset and only carry EventTime.
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.FirstTimestamp.Time
 }
@@ -0,0 +1,987 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const operationRoute = "/pods/:uid/operation"
+
+func TestPodOperationRestartBarePod(t *testing.T) {
+	h := newTestPodHandler(testPod("web-old", map[string]string{"uid": "abc", "app": "web"}))
+
+	rec := serve(h.PodOperation, http.MethodPost, operationRoute, "/pods/abc/operation", `{"operation":"restart"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("restart returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var data models.PodOperationResponse
+	decodeResponse(t, rec, &data)
+
+	pods, err := h.k8sClient.ClientSet.CoreV1().Pods("default").List(h.k8sClient.Context, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 1 {
+		t.Fatalf("%d pods after restart, want 1", len(pods.Items))
+	}
+	pod := pods.Items[0]
+	if pod.Name == "web-old" || pod.Name != data.PodName {
+		t.Errorf("pod after restart is %q, response names %q; want a new pod", pod.Name, data.PodName)
+	}
+	if pod.Labels["uid"] != "abc" || pod.Labels["app"] != "web" {
+		t.Errorf("restarted pod labels = %v, want the original labels", pod.Labels)
+	}
+}
+
+func TestPodOperationRestartOwnedPod(t *testing.T) {
+	pod := testPod("web-7d9f-x2k4", map[string]string{"deployment-uid": "abc", "app": "web"})
+	isController := true
+	pod.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-7d9f", UID: "rs-uid", Controller: &isController,
+	}}
+	h := newTestPodHandler(pod)
+	if err := h.uidIndex.Record(h.k8sClient.Context, "Pod", "abc", "default", pod.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := serve(h.PodOperation, http.MethodPost, operationRoute, "/pods/abc/operation", `{"operation":"restart"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("restart returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// The replica set creates the replacement; the handler must not add its own
+	pods, err := h.k8sClient.ClientSet.CoreV1().Pods("default").List(h.k8sClient.Context, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 0 {
+		t.Errorf("%d pods after restarting an owned pod, want 0 until the controller recreates it", len(pods.Items))
+	}
+}
+
+func TestPodOperationStopStartByDeploymentUID(t *testing.T) {
+	// A stopped deployment has no pods, so it must be found by its own label
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"uid": "abc"}},
+	}
+	replicas := int32(2)
+	deployment.Spec.Replicas = &replicas
+	h := newTestPodHandler(deployment)
+	fakeScale(h.k8sClient)
+
+	for _, tt := range []struct {
+		operation string
+		want      int32
+	}{
+		{"stop", 0},
+		{"start", 1},
+		{"start", 1},
+	} {
+		rec := serve(h.PodOperation, http.MethodPost, operationRoute, "/pods/abc/operation", `{"operation":"`+tt.operation+`"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s returned %d: %s", tt.operation, rec.Code, rec.Body.String())
+		}
+		var data models.PodOperationResponse
+		decodeResponse(t, rec, &data)
+		if data.Deployment != "web" || data.Replicas == nil || *data.Replicas != tt.want {
+			t.Errorf("%s response = %+v, want deployment web at %d replicas", tt.operation, data, tt.want)
+		}
+		if replicas := deploymentReplicas(t, h.k8sClient, "web"); replicas != tt.want {
+			t.Errorf("after %s the deployment has %d replicas, want %d", tt.operation, replicas, tt.want)
+		}
+	}
+}
+
+func TestPodOperationStopBarePod(t *testing.T) {
+	h := newTestPodHandler(testPod("web", map[string]string{"uid": "abc"}))
+
+	rec := serve(h.PodOperation, http.MethodPost, operationRoute, "/pods/abc/operation", `{"operation":"stop"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("stop of a bare pod returned %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec = serve(h.PodOperation, http.MethodPost, operationRoute, "/pods/missing/operation", `{"operation":"stop"}`)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("stop of an unknown uid returned %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+const envRoute = "/pods/:uid/env"
+
+func TestUpdatePodEnv(t *testing.T) {
+	h := newTestPodHandler(testPod("web-old", map[string]string{"uid": "abc", "app": "web"}))
+
+	rec := serve(h.UpdatePodEnv, http.MethodPatch, envRoute, "/pods/abc/env", `{"env":{"LOG_LEVEL":"debug"}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("UpdatePodEnv returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	pods, err := h.k8sClient.ClientSet.CoreV1().Pods("default").List(h.k8sClient.Context, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 1 || pods.Items[0].Name == "web-old" {
+		t.Fatalf("pods after UpdatePodEnv = %d, want only the replacement", len(pods.Items))
+	}
+	env := pods.Items[0].Spec.Containers[0].Env
+	if len(env) != 1 || env[0].Name != "LOG_LEVEL" || env[0].Value != "debug" {
+		t.Errorf("replacement env = %v, want LOG_LEVEL=debug", env)
+	}
+}
+
+func TestUpdatePodEnvProtectedPods(t *testing.T) {
+	system := testPod("coredns", map[string]string{"uid": "sys"})
+	system.Namespace = "kube-system"
+	owned := testPod("web-7d9f-x2k4", map[string]string{"uid": "owned"})
+	isController := true
+	owned.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-7d9f", UID: "rs-uid", Controller: &isController,
+	}}
+	h := newTestPodHandler(system, owned)
+
+	for _, target := range []string{"/pods/sys/env?namespace=kube-system", "/pods/owned/env"} {
+		rec := serve(h.UpdatePodEnv, http.MethodPatch, envRoute, target, `{"env":{"LOG_LEVEL":"debug"}}`)
+		if rec.Code != http.StatusConflict {
+			t.Errorf("UpdatePodEnv(%s) returned %d, want %d", target, rec.Code, http.StatusConflict)
+		}
+	}
+
+	for _, pod := range []*corev1.Pod{system, owned} {
+		if _, err := h.k8sClient.ClientSet.CoreV1().Pods(pod.Namespace).Get(h.k8sClient.Context, pod.Name, metav1.GetOptions{}); err != nil {
+			t.Errorf("protected pod %s was removed: %v", pod.Name, err)
+		}
+	}
+}
+
+func TestUpdatePodEnvKeepsPodWhenCreateFails(t *testing.T) {
+	h := newTestPodHandler(testPod("web-old", map[string]string{"uid": "abc", "app": "web"}))
+	h.k8sClient.ClientSet.(*fake.Clientset).PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("admission webhook denied the request")
+	})
+
+	rec := serve(h.UpdatePodEnv, http.MethodPatch, envRoute, "/pods/abc/env", `{"env":{"LOG_LEVEL":"debug"}}`)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("UpdatePodEnv returned %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if _, err := h.k8sClient.ClientSet.CoreV1().Pods("default").Get(h.k8sClient.Context, "web-old", metav1.GetOptions{}); err != nil {
+		t.Errorf("original pod lost after a failed create: %v", err)
+	}
+}
+
+func TestCreatePodInNamespace(t *testing.T) {
+	h := newTestPodHandler()
+
+	rec := serve(h.CreatePod, http.MethodPost, "/pods", "/pods",
+		`{"name":"web","image":"nginx","container_name":"nginx","namespace":"team-a"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("CreatePod returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var created models.PodResponse
+	decodeResponse(t, rec, &created)
+	if created.Namespace != "team-a" {
+		t.Errorf("CreatePod namespace = %q, want team-a", created.Namespace)
+	}
+
+	rec = serve(h.GetPodByUID, http.MethodGet, "/pods/:uid", "/pods/"+created.UID+"?namespace=team-a", "")
+	var got models.PodResponse
+	decodeResponse(t, rec, &got)
+	if rec.Code != http.StatusOK || got.Name != created.Name {
+		t.Errorf("GetPodByUID in team-a returned %d with %q, want %s", rec.Code, got.Name, created.Name)
+	}
+
+	// Lookups stay within the requested namespace
+	rec = serve(h.GetPodByUID, http.MethodGet, "/pods/:uid", "/pods/"+created.UID, "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GetPodByUID in the default namespace returned %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestInvalidNamespaceRejected(t *testing.T) {
+	h := newTestPodHandler()
+
+	rec := serve(h.CreatePod, http.MethodPost, "/pods", "/pods",
+		`{"name":"web","image":"nginx","container_name":"nginx","namespace":"Team_A"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("CreatePod in namespace Team_A returned %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	rec = serve(h.ListPods, http.MethodGet, "/pods", "/pods?namespace=Team_A", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("ListPods in namespace Team_A returned %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestStreamLogEventsIncremental(t *testing.T) {
+	logs, writer := io.Pipe()
+	router := gin.New()
+	router.GET("/logs", func(c *gin.Context) { streamLogEvents(c, logs) })
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+	events := bufio.NewReader(resp.Body)
+
+	// nextData returns the data of the next event
+	nextData := func() string {
+		t.Helper()
+		for {
+			line, err := events.ReadString('\n')
+			if err != nil {
+				t.Fatalf("reading events: %v", err)
+			}
+			if data, ok := strings.CutPrefix(line, "data:"); ok {
+				return strings.TrimSpace(data)
+			}
+		}
+	}
+
+	// Each line must arrive before the next one is written
+	for _, line := range []string{"first", "second"} {
+		go fmt.Fprintln(writer, line)
+		if got := nextData(); got != line {
+			t.Errorf("event data = %q, want %q", got, line)
+		}
+	}
+	writer.Close()
+	if got := nextData(); got != "log stream closed" {
+		t.Errorf("final event data = %q, want the end of stream", got)
+	}
+}
+
+func TestGetPodLogsFollow(t *testing.T) {
+	h := newTestPodHandler(testPod("web", map[string]string{"uid": "abc"}))
+
+	// Streaming needs a real connection rather than a response recorder
+	router := gin.New()
+	router.GET("/pods/:uid/logs", h.GetPodLogs)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/pods/abc/logs?follow=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GetPodLogs with follow returned %d: %s", resp.StatusCode, data)
+	}
+	body := string(data)
+	// The fake clientset always serves "fake logs"
+	if !strings.Contains(body, "event:log\ndata:fake logs") || !strings.Contains(body, "event:end") {
+		t.Errorf("GetPodLogs with follow = %q, want a log event and an end event", body)
+	}
+
+	rec := serve(h.GetPodLogs, http.MethodGet, "/pods/:uid/logs", "/pods/abc/logs?follow=true&previous=true", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("GetPodLogs with follow and previous returned %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetPodLogsContainerSelection(t *testing.T) {
+	multi := testPod("multi", map[string]string{"uid": "multi"})
+	multi.Spec.Containers = append(multi.Spec.Containers, corev1.Container{Name: "sidecar", Image: "envoy"})
+	h := newTestPodHandler(testPod("single", map[string]string{"uid": "single"}), multi)
+
+	for _, tt := range []struct {
+		target string
+		want   int
+	}{
+		// The only container is picked without being named
+		{"/pods/single/logs", http.StatusOK},
+		{"/pods/multi/logs", http.StatusBadRequest},
+		{"/pods/multi/logs?container=sidecar", http.StatusOK},
+		{"/pods/multi/logs?container=missing", http.StatusBadRequest},
+	} {
+		rec := serve(h.GetPodLogs, http.MethodGet, "/pods/:uid/logs", tt.target, "")
+		if rec.Code != tt.want {
+			t.Errorf("GetPodLogs(%s) returned %d, want %d: %s", tt.target, rec.Code, tt.want, rec.Body.String())
+		}
+		if tt.want == http.StatusBadRequest {
+			resp := decodeResponse(t, rec, nil)
+			if !strings.Contains(resp.Error, "app, sidecar") {
+				t.Errorf("GetPodLogs(%s) error = %q, want it to list the containers", tt.target, resp.Error)
+			}
+		}
+	}
+}
+
+// createPod creates a pod from body through CreatePod and returns the stored pod.
+func createPod(t *testing.T, h *PodHandler, body string) *corev1.Pod {
+	t.Helper()
+	rec := serve(h.CreatePod, http.MethodPost, "/pods", "/pods", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("CreatePod returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var created models.PodResponse
+	decodeResponse(t, rec, &created)
+	pod, err := h.k8sClient.ClientSet.CoreV1().Pods(created.Namespace).Get(h.k8sClient.Context, created.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pod
+}
+
+// createPodStatus returns the status CreatePod answers body with.
+func createPodStatus(h *PodHandler, body string) int {
+	return serve(h.CreatePod, http.MethodPost, "/pods", "/pods", body).Code
+}
+
+func TestCreatePodContainers(t *testing.T) {
+	h := newTestPodHandler()
+
+	pod := createPod(t, h, `{"name":"web","containers":[
+		{"name":"app","image":"nginx","port":80},
+		{"name":"sidecar","image":"envoy","command":["envoy"],"env":{"MODE":"proxy"}}]}`)
+	if len(pod.Spec.Containers) != 2 {
+		t.Fatalf("pod has %d containers, want 2", len(pod.Spec.Containers))
+	}
+	app, sidecar := pod.Spec.Containers[0], pod.Spec.Containers[1]
+	if app.Name != "app" || app.Image != "nginx" || len(app.Ports) != 1 || app.Ports[0].ContainerPort != 80 {
+		t.Errorf("first container = %+v, want app running nginx on port 80", app)
+	}
+	if sidecar.Name != "sidecar" || sidecar.Image != "envoy" || len(sidecar.Command) != 1 ||
+		!slices.ContainsFunc(sidecar.Env, func(e corev1.EnvVar) bool { return e.Name == "MODE" && e.Value == "proxy" }) {
+		t.Errorf("second container = %+v, want sidecar running envoy with MODE=proxy", sidecar)
+	}
+
+	// The single-container fields keep working
+	pod = createPod(t, h, `{"name":"legacy","image":"nginx","container_name":"nginx","port":8080}`)
+	if len(pod.Spec.Containers) != 1 || pod.Spec.Containers[0].Name != "nginx" || pod.Spec.Containers[0].Ports[0].ContainerPort != 8080 {
+		t.Errorf("single-container pod = %+v, want one nginx container on port 8080", pod.Spec.Containers)
+	}
+
+	for _, body := range []string{
+		`{"name":"dup","containers":[{"name":"app","image":"nginx"},{"name":"app","image":"envoy"}]}`,
+		`{"name":"bad","containers":[{"name":"App_1","image":"nginx"}]}`,
+	} {
+		if code := createPodStatus(h, body); code != http.StatusBadRequest {
+			t.Errorf("CreatePod(%s) returned %d, want %d", body, code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestCreatePodResources(t *testing.T) {
+	h := newTestPodHandler()
+
+	pod := createPod(t, h, `{"name":"web","image":"nginx","container_name":"nginx",
+		"resources":{"requests":{"cpu":"250m","memory":"64Mi"},"limits":{"cpu":"1","memory":"128Mi"}}}`)
+	resources := pod.Spec.Containers[0].Resources
+	for _, tt := range []struct {
+		name string
+		got  resource.Quantity
+		want string
+	}{
+		{"cpu request", resources.Requests[corev1.ResourceCPU], "250m"},
+		{"memory request", resources.Requests[corev1.ResourceMemory], "64Mi"},
+		{"cpu limit", resources.Limits[corev1.ResourceCPU], "1"},
+		{"memory limit", resources.Limits[corev1.ResourceMemory], "128Mi"},
+	} {
+		if tt.got.Cmp(resource.MustParse(tt.want)) != 0 {
+			t.Errorf("%s = %s, want %s", tt.name, tt.got.String(), tt.want)
+		}
+	}
+
+	for _, body := range []string{
+		`{"name":"web","image":"nginx","container_name":"nginx","resources":{"requests":{"cpu":"500x"}}}`,
+		`{"name":"web","image":"nginx","container_name":"nginx","resources":{"limits":{"gpu":"1"}}}`,
+	} {
+		if code := createPodStatus(h, body); code != http.StatusBadRequest {
+			t.Errorf("CreatePod(%s) returned %d, want %d", body, code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetPodByUIDStatusDetails(t *testing.T) {
+	pod := testPod("web", map[string]string{"uid": "abc"})
+	pod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+		{Type: corev1.PodReady, Status: corev1.ConditionFalse, Reason: "ContainersNotReady"},
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:         "app",
+		RestartCount: 4,
+		State: corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off restarting failed container"},
+		},
+		LastTerminationState: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1},
+		},
+	}}
+	h := newTestPodHandler(pod)
+
+	rec := serve(h.GetPodByUID, http.MethodGet, "/pods/:uid", "/pods/abc", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GetPodByUID returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var got models.PodResponse
+	decodeResponse(t, rec, &got)
+
+	if got.RestartCount != 4 {
+		t.Errorf("RestartCount = %d, want 4", got.RestartCount)
+	}
+	if len(got.Conditions) != 2 || got.Conditions[1].Type != "Ready" || got.Conditions[1].Reason != "ContainersNotReady" {
+		t.Errorf("Conditions = %+v, want PodScheduled and a not-ready Ready condition", got.Conditions)
+	}
+	want := models.ContainerStatus{
+		Name:                  "app",
+		RestartCount:          4,
+		State:                 "waiting",
+		Reason:                "CrashLoopBackOff",
+		Message:               "back-off restarting failed container",
+		LastTerminationReason: "Error",
+	}
+	if len(got.Containers) != 1 || got.Containers[0] != want {
+		t.Errorf("Containers = %+v, want [%+v]", got.Containers, want)
+	}
+}
+
+// setCreatedPodStatus makes pods created through h start with status.
+func setCreatedPodStatus(h *PodHandler, status corev1.PodStatus) {
+	h.k8sClient.ClientSet.(*fake.Clientset).PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		action.(k8stesting.CreateAction).GetObject().(*corev1.Pod).Status = status
+		// Let the default reactor store the pod
+		return false, nil, nil
+	})
+}
+
+func TestCreatePodWaitTimeout(t *testing.T) {
+	h := newTestPodHandler()
+	setCreatedPodStatus(h, corev1.PodStatus{Phase: corev1.PodPending})
+
+	start := time.Now()
+	rec := serve(h.CreatePod, http.MethodPost, "/pods", "/pods?wait=true&timeout=1s",
+		`{"name":"web","image":"nginx","container_name":"nginx"}`)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("CreatePod with a 1s timeout took %v", elapsed)
+	}
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("CreatePod returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var created models.PodResponse
+	resp := decodeResponse(t, rec, &created)
+	if created.Status != "Pending" {
+		t.Errorf("Status = %q, want the last observed phase Pending", created.Status)
+	}
+	if !strings.Contains(resp.Message, "not ready after 1s") {
+		t.Errorf("Message = %q, want it to report the timeout", resp.Message)
+	}
+}
+
+func TestCreatePodWaitReady(t *testing.T) {
+	h := newTestPodHandler()
+	setCreatedPodStatus(h, corev1.PodStatus{
+		Phase:      corev1.PodRunning,
+		Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+	})
+
+	rec := serve(h.CreatePod, http.MethodPost, "/pods", "/pods?wait=true&timeout=5s",
+		`{"name":"web","image":"nginx","container_name":"nginx"}`)
+	resp := decodeResponse(t, rec, nil)
+	if rec.Code != http.StatusCreated || resp.Message != "Pod created and ready" {
+		t.Errorf("CreatePod returned %d with %q, want a ready pod", rec.Code, resp.Message)
+	}
+
+	rec = serve(h.CreatePod, http.MethodPost, "/pods", "/pods?wait=true&timeout=forever",
+		`{"name":"web","image":"nginx","container_name":"nginx"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("CreatePod with an invalid timeout returned %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestListPodsLabelSelector(t *testing.T) {
+	h := newTestPodHandler(
+		testPod("web-1", map[string]string{"uid": "a", "app": "nginx"}),
+		testPod("web-2", map[string]string{"uid": "b", "app": "nginx"}),
+		testPod("db", map[string]string{"uid": "c", "app": "postgres"}),
+	)
+
+	rec := serve(h.ListPods, http.MethodGet, "/pods", "/pods?labelSelector=app%3Dnginx", "")
+	var list models.ListResponse
+	decodeResponse(t, rec, &list)
+	if rec.Code != http.StatusOK || list.Count != 2 {
+		t.Errorf("ListPods(app=nginx) returned %d with %d pods, want 2", rec.Code, list.Count)
+	}
+
+	rec = serve(h.ListPods, http.MethodGet, "/pods", "/pods?labelSelector=app%3D%3D%3D", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("ListPods with an invalid selector returned %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetPodEvents(t *testing.T) {
+	pod := testPod("web", map[string]string{"uid": "abc"})
+	pod.Status.Phase = corev1.PodPending
+	now := time.Now()
+	event := func(name, reason, message string, age time.Duration) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web", Namespace: "default"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        message,
+			Count:          3,
+			LastTimestamp:  metav1.NewTime(now.Add(-age)),
+		}
+	}
+	h := newTestPodHandler(pod,
+		event("web.2", "FailedScheduling", "0/3 nodes are available: 3 Insufficient cpu.", time.Minute),
+		event("web.1", "FailedScheduling", "0/3 nodes are available: 3 node(s) had untolerated taint.", time.Hour),
+	)
+	var selector string
+	h.k8sClient.ClientSet.(*fake.Clientset).PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selector = action.(k8stesting.ListAction).GetListRestrictions().Fields.String()
+		return false, nil, nil
+	})
+
+	rec := serve(h.GetPodEvents, http.MethodGet, "/pods/:uid/events", "/pods/abc/events", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GetPodEvents returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var got models.PodEventsResponse
+	decodeResponse(t, rec, &got)
+
+	if selector != "involvedObject.name=web" {
+		t.Errorf("events listed with field selector %q, want involvedObject.name=web", selector)
+	}
+	if len(got.Events) != 2 {
+		t.Fatalf("GetPodEvents returned %d events, want 2", len(got.Events))
+	}
+	// Oldest first
+	latest := got.Events[1]
+	if latest.Reason != "FailedScheduling" || latest.Type != "Warning" || latest.Count != 3 ||
+		!strings.Contains(latest.Message, "Insufficient cpu") {
+		t.Errorf("latest event = %+v, want the FailedScheduling event for insufficient cpu", latest)
+	}
+	if !got.Events[0].LastTimestamp.Before(latest.LastTimestamp) {
+		t.Errorf("events are not sorted oldest first: %+v", got.Events)
+	}
+}
+
+func TestCreatePodImagePullSettings(t *testing.T) {
+	registry := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "default"}, Type: corev1.SecretTypeDockerConfigJson}
+	h := newTestPodHandler(registry)
+
+	pod := createPod(t, h, `{"name":"web","image":"registry.example.com/web","container_name":"web",
+		"image_pull_policy":"Always","image_pull_secrets":["registry"]}`)
+	if len(pod.Spec.ImagePullSecrets) != 1 || pod.Spec.ImagePullSecrets[0].Name != "registry" {
+		t.Errorf("pod imagePullSecrets = %+v, want [registry]", pod.Spec.ImagePullSecrets)
+	}
+	if policy := pod.Spec.Containers[0].ImagePullPolicy; policy != corev1.PullAlways {
+		t.Errorf("container imagePullPolicy = %q, want Always", policy)
+	}
+
+	pod = createPod(t, h, `{"name":"multi","image_pull_secrets":["registry"],"containers":[
+		{"name":"app","image":"registry.example.com/app","image_pull_policy":"IfNotPresent"},
+		{"name":"sidecar","image":"envoy","image_pull_policy":"Never"}]}`)
+	if got := []corev1.PullPolicy{pod.Spec.Containers[0].ImagePullPolicy, pod.Spec.Containers[1].ImagePullPolicy}; got[0] != corev1.PullIfNotPresent || got[1] != corev1.PullNever {
+		t.Errorf("container imagePullPolicies = %v, want [IfNotPresent Never]", got)
+	}
+
+	for _, body := range []string{
+		`{"name":"web","image":"nginx","container_name":"nginx","image_pull_secrets":["missing"]}`,
+		`{"name":"web","image":"nginx","container_name":"nginx","image_pull_secrets":["Not_Valid"]}`,
+		`{"name":"web","image":"nginx","container_name":"nginx","image_pull_policy":"Sometimes"}`,
+		`{"name":"web","containers":[{"name":"app","image":"nginx","image_pull_policy":"Sometimes"}]}`,
+	} {
+		if code := createPodStatus(h, body); code != http.StatusBadRequest {
+			t.Errorf("CreatePod(%s) returned %d, want %d", body, code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestCreatePodNodeScheduling(t *testing.T) {
+	h := newTestPodHandler()
+
+	pod := createPod(t, h, `{"name":"trainer","image":"pytorch","container_name":"trainer",
+		"node_selector":{"accelerator":"nvidia-a100"},
+		"required_node_affinity":[
+			{"key":"topology.kubernetes.io/zone","operator":"In","values":["us-east-1a","us-east-1b"]},
+			{"key":"node.example.com/spot","operator":"DoesNotExist"}]}`)
+
+	if pod.Spec.NodeSelector["accelerator"] != "nvidia-a100" {
+		t.Errorf("pod nodeSelector = %v, want accelerator=nvidia-a100", pod.Spec.NodeSelector)
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		t.Fatalf("pod affinity = %+v, want required node affinity", pod.Spec.Affinity)
+	}
+	terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 || len(terms[0].MatchExpressions) != 2 {
+		t.Fatalf("node selector terms = %+v, want one term with both requirements", terms)
+	}
+	zone, spot := terms[0].MatchExpressions[0], terms[0].MatchExpressions[1]
+	if zone.Key != "topology.kubernetes.io/zone" || zone.Operator != corev1.NodeSelectorOpIn || !slices.Equal(zone.Values, []string{"us-east-1a", "us-east-1b"}) {
+		t.Errorf("first requirement = %+v, want zone In [us-east-1a us-east-1b]", zone)
+	}
+	if spot.Key != "node.example.com/spot" || spot.Operator != corev1.NodeSelectorOpDoesNotExist || len(spot.Values) != 0 {
+		t.Errorf("second requirement = %+v, want node.example.com/spot DoesNotExist", spot)
+	}
+
+	// Without constraints the pod can run anywhere
+	pod = createPod(t, h, `{"name":"web","image":"nginx","container_name":"nginx"}`)
+	if pod.Spec.NodeSelector != nil || pod.Spec.Affinity != nil {
+		t.Errorf("unconstrained pod has nodeSelector %v and affinity %+v, want neither", pod.Spec.NodeSelector, pod.Spec.Affinity)
+	}
+
+	for _, scheduling := range []string{
+		`"node_selector":{"bad key!":"x"}`,
+		`"node_selector":{"disktype":"not a valid value"}`,
+		`"required_node_affinity":[{"key":"zone","operator":"Near","values":["a"]}]`,
+		`"required_node_affinity":[{"key":"zone","operator":"In"}]`,
+		`"required_node_affinity":[{"key":"zone","operator":"Exists","values":["a"]}]`,
+		`"required_node_affinity":[{"key":"cpus","operator":"Gt","values":["many"]}]`,
+	} {
+		body := `{"name":"web","image":"nginx","container_name":"nginx",` + scheduling + `}`
+		if code := createPodStatus(h, body); code != http.StatusBadRequest {
+			t.Errorf("CreatePod with %s returned %d, want %d", scheduling, code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestCreatePodCommandAndArgs(t *testing.T) {
+	h := newTestPodHandler()
+
+	pod := createPod(t, h, `{"name":"debug","image":"busybox","container_name":"busybox","command":["sleep"],"args":["infinity"]}`)
+	container := pod.Spec.Containers[0]
+	if !slices.Equal(container.Command, []string{"sleep"}) || !slices.Equal(container.Args, []string{"infinity"}) {
+		t.Errorf("container command/args = %v %v, want [sleep] [infinity]", container.Command, container.Args)
+	}
+
+	pod = createPod(t, h, `{"name":"web","containers":[{"name":"app","image":"python","command":["python","-m"],"args":["http.server","8000"]}]}`)
+	container = pod.Spec.Containers[0]
+	if !slices.Equal(container.Command, []string{"python", "-m"}) || !slices.Equal(container.Args, []string{"http.server", "8000"}) {
+		t.Errorf("container command/args = %v %v, want [python -m] [http.server 8000]", container.Command, container.Args)
+	}
+
+	// By default the image entrypoint is left alone
+	pod = createPod(t, h, `{"name":"web","image":"nginx","container_name":"nginx"}`)
+	if container := pod.Spec.Containers[0]; container.Command != nil || container.Args != nil {
+		t.Errorf("container command/args = %v %v, want neither set", container.Command, container.Args)
+	}
+
+	body := `{"name":"web","command":["sleep"],"containers":[{"name":"app","image":"busybox"}]}`
+	if code := createPodStatus(h, body); code != http.StatusBadRequest {
+		t.Errorf("CreatePod with command alongside containers returned %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestCreatePodRestartPolicy(t *testing.T) {
+	h := newTestPodHandler()
+
+	for _, policy := range []corev1.RestartPolicy{corev1.RestartPolicyAlways, corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever} {
+		pod := createPod(t, h, `{"name":"job","image":"busybox","container_name":"busybox","restart_policy":"`+string(policy)+`"}`)
+		if pod.Spec.RestartPolicy != policy {
+			t.Errorf("restart_policy %s gave pod restartPolicy %q", policy, pod.Spec.RestartPolicy)
+		}
+	}
+
+	// Unset leaves the Kubernetes default of Always to the API server
+	pod := createPod(t, h, `{"name":"web","image":"nginx","container_name":"nginx"}`)
+	if pod.Spec.RestartPolicy != "" {
+		t.Errorf("pod restartPolicy = %q, want it left unset", pod.Spec.RestartPolicy)
+	}
+
+	for _, policy := range []string{"Sometimes", "never"} {
+		body := `{"name":"job","image":"busybox","container_name":"busybox","restart_policy":"` + policy + `"}`
+		if code := createPodStatus(h, body); code != http.StatusBadRequest {
+			t.Errorf("CreatePod with restart_policy %q returned %d, want %d", policy, code, http.StatusBadRequest)
+		}
+	}
+}
+
+// slowClientset delays pod creates outside the fake clientset's lock, so
+// concurrent creates really overlap.
+type slowClientset struct {
+	*fake.Clientset
+	delay func(*corev1.Pod)
+}
+
+func (c slowClientset) CoreV1() typedcorev1.CoreV1Interface {
+	return slowCoreV1{c.Clientset.CoreV1(), c.delay}
+}
+
+type slowCoreV1 struct {
+	typedcorev1.CoreV1Interface
+	delay func(*corev1.Pod)
+}
+
+func (c slowCoreV1) Pods(namespace string) typedcorev1.PodInterface {
+	return slowPods{c.CoreV1Interface.Pods(namespace), c.delay}
+}
+
+type slowPods struct {
+	typedcorev1.PodInterface
+	delay func(*corev1.Pod)
+}
+
+func (p slowPods) Create(ctx context.Context, pod *corev1.Pod, opts metav1.CreateOptions) (*corev1.Pod, error) {
+	p.delay(pod)
+	return p.PodInterface.Create(ctx, pod, opts)
+}
+
+func TestCreatePodsBatchKeepsOrder(t *testing.T) {
+	h := newTestPodHandler()
+	h.batchWorkers = 4
+
+	// Earlier pods take longer to create, so they finish last
+	const size = 8
+	var mu sync.Mutex
+	var running, peak int
+	h.k8sClient.ClientSet = slowClientset{
+		Clientset: h.k8sClient.ClientSet.(*fake.Clientset),
+		delay: func(pod *corev1.Pod) {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+
+			var i int
+			fmt.Sscanf(pod.Labels["app"], "pod-%d", &i)
+			time.Sleep(time.Duration(size-i) * 5 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+		},
+	}
+
+	var pods []string
+	for i := range size {
+		pods = append(pods, fmt.Sprintf(`{"name":"pod-%d","image":"nginx"}`, i))
+	}
+	body := `{"pods":[` + strings.Join(pods, ",") + `]}`
+
+	rec := serve(h.CreatePodsBatch, http.MethodPost, "/pods/batch", "/pods/batch", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("CreatePodsBatch returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var data models.BatchResponse
+	decodeResponse(t, rec, &data)
+
+	if data.Succeeded != size || len(data.Results) != size {
+		t.Fatalf("batch = %+v, want %d successful results", data, size)
+	}
+	for i, result := range data.Results {
+		name := fmt.Sprintf("pod-%d", i)
+		if result.Index != i || result.Name != name || !strings.HasPrefix(result.PodName, name+"-") {
+			t.Errorf("result %d = %+v, want the result for %s", i, result, name)
+		}
+	}
+	if peak < 2 || peak > h.batchWorkers {
+		t.Errorf("%d pods were created at once, want between 2 and %d", peak, h.batchWorkers)
+	}
+}
+
+func TestCreatePodsBatchMixed(t *testing.T) {
+	h := newTestPodHandler()
+
+	body := `{"pods":[
+		{"name":"web","image":"nginx"},
+		{"name":"cron","image":"nginx","restart_policy":"Sometimes"},
+		{"name":"api","image":"nginx","namespace":"Bad_NS"},
+		{"name":"worker","image":"busybox","namespace":"jobs"}]}`
+	rec := serve(h.CreatePodsBatch, http.MethodPost, "/pods/batch", "/pods/batch", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("CreatePodsBatch returned %d, want 200 despite failed items: %s", rec.Code, rec.Body.String())
+	}
+	var data models.BatchResponse
+	resp := decodeResponse(t, rec, &data)
+	if data.Succeeded != 2 || data.Failed != 2 || resp.Message != "2 created, 2 failed" {
+		t.Errorf("batch = %d created, %d failed (%q), want 2 and 2", data.Succeeded, data.Failed, resp.Message)
+	}
+
+	wantSuccess := []bool{true, false, false, true}
+	for i, result := range data.Results {
+		if result.Success != wantSuccess[i] {
+			t.Errorf("result %d = %+v, want success %v", i, result, wantSuccess[i])
+			continue
+		}
+		if result.Success && (result.UID == "" || result.Error != "") {
+			t.Errorf("result %d = %+v, want a UID and no error", i, result)
+		}
+		if !result.Success && (result.Error == "" || result.UID != "") {
+			t.Errorf("result %d = %+v, want an error and no UID", i, result)
+		}
+	}
+
+	// The valid pods were created despite the invalid ones between them
+	for _, created := range []struct{ namespace, uid string }{
+		{"default", data.Results[0].UID},
+		{"jobs", data.Results[3].UID},
+	} {
+		pods, err := h.k8sClient.ClientSet.CoreV1().Pods(created.namespace).List(h.k8sClient.Context, metav1.ListOptions{LabelSelector: "uid=" + created.uid})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pods.Items) != 1 {
+			t.Errorf("%d pods with uid %s in %s, want 1", len(pods.Items), created.uid, created.namespace)
+		}
+	}
+	all, err := h.k8sClient.ClientSet.CoreV1().Pods(metav1.NamespaceAll).List(h.k8sClient.Context, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all.Items) != 2 {
+		t.Errorf("%d pods created, want only the 2 valid ones", len(all.Items))
+	}
+}
+
+func TestDeletePodByUIDGracePeriod(t *testing.T) {
+	seconds := func(n int64) *int64 { return &n }
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantGrace  *int64 // nil leaves the pod's own grace period
+	}{
+		{"default", "", http.StatusOK, nil},
+		{"grace period", "?gracePeriodSeconds=10", http.StatusOK, seconds(10)},
+		{"zero grace period", "?gracePeriodSeconds=0", http.StatusOK, seconds(0)},
+		{"force", "?force=true", http.StatusOK, seconds(0)},
+		{"force with zero", "?force=true&gracePeriodSeconds=0", http.StatusOK, seconds(0)},
+		{"negative", "?gracePeriodSeconds=-1", http.StatusBadRequest, nil},
+		{"non-numeric", "?gracePeriodSeconds=soon", http.StatusBadRequest, nil},
+		{"force with grace period", "?force=true&gracePeriodSeconds=5", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestPodHandler(testPod("web", map[string]string{"uid": "abc"}))
+			rec := serve(h.DeletePodByUID, http.MethodDelete, "/pods/:uid", "/pods/abc"+tt.query, "")
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("DeletePodByUID returned %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			var deletes []metav1.DeleteOptions
+			for _, action := range h.k8sClient.ClientSet.(*fake.Clientset).Actions() {
+				if action, ok := action.(k8stesting.DeleteAction); ok && action.GetResource().Resource == "pods" {
+					deletes = append(deletes, action.GetDeleteOptions())
+				}
+			}
+			if tt.wantStatus != http.StatusOK {
+				if len(deletes) != 0 {
+					t.Errorf("pod deleted despite the rejected request")
+				}
+				return
+			}
+			if len(deletes) != 1 {
+				t.Fatalf("%d pod deletes, want 1", len(deletes))
+			}
+			got := deletes[0].GracePeriodSeconds
+			if (got == nil) != (tt.wantGrace == nil) || (got != nil && *got != *tt.wantGrace) {
+				t.Errorf("GracePeriodSeconds = %s, want %s", formatGrace(got), formatGrace(tt.wantGrace))
+			}
+		})
+	}
+}
+
+// formatGrace formats an optional grace period for test messages.
+func formatGrace(seconds *int64) string {
+	if seconds == nil {
+		return "unset"
+	}
+	return fmt.Sprintf("%ds", *seconds)
+}
+
+func TestPodAnnotationsPersist(t *testing.T) {
+	h := newTestPodHandler()
+
+	annotations := map[string]string{"team": "platform", "example.com/owner": "alice@example.com"}
+	pod := createPod(t, h, `{"name":"web","image":"nginx",
+		"annotations":{"team":"platform","example.com/owner":"alice@example.com"}}`)
+
+	rec := serve(h.GetPodByUID, http.MethodGet, "/pods/:uid", "/pods/"+pod.Labels["uid"], "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GetPodByUID returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var got models.PodResponse
+	decodeResponse(t, rec, &got)
+	if !maps.Equal(got.Annotations, annotations) {
+		t.Errorf("annotations = %v, want %v", got.Annotations, annotations)
+	}
+
+	if status := createPodStatus(h, `{"name":"bad","image":"nginx","annotations":{"not a key!":"x"}}`); status != http.StatusBadRequest {
+		t.Errorf("CreatePod with an invalid annotation key returned %d, want 400", status)
+	}
+}
+
+func TestUpdatePodLabels(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+		want map[string]string
+	}{
+		{"add", `{"set":{"tier":"frontend"}}`, http.StatusOK,
+			map[string]string{"uid": "abc", "app": "web", "tier": "frontend"}},
+		{"update", `{"set":{"app":"api"}}`, http.StatusOK,
+			map[string]string{"uid": "abc", "app": "api"}},
+		{"remove", `{"remove":["app"]}`, http.StatusOK,
+			map[string]string{"uid": "abc"}},
+		{"overwrite uid", `{"set":{"uid":"other"}}`, http.StatusBadRequest, nil},
+		{"remove uid", `{"remove":["uid"]}`, http.StatusBadRequest, nil},
+		{"set and remove", `{"set":{"app":"api"},"remove":["app"]}`, http.StatusBadRequest, nil},
+		{"empty", `{}`, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestPodHandler(testPod("web", map[string]string{"uid": "abc", "app": "web"}))
+
+			rec := serve(h.UpdatePodLabels, http.MethodPatch, "/pods/:uid/labels", "/pods/abc/labels", tt.body)
+			if rec.Code != tt.code {
+				t.Fatalf("UpdatePodLabels returned %d, want %d: %s", rec.Code, tt.code, rec.Body.String())
+			}
+
+			pod, err := h.k8sClient.ClientSet.CoreV1().Pods("default").Get(h.k8sClient.Context, "web", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.want
+			if want == nil {
+				want = map[string]string{"uid": "abc", "app": "web"}
+			}
+			if !maps.Equal(pod.Labels, want) {
+				t.Errorf("pod labels = %v, want %v", pod.Labels, want)
+			}
+			if tt.code == http.StatusOK {
+				var got models.PodLabelsResponse
+				decodeResponse(t, rec, &got)
+				if !maps.Equal(got.Labels, want) {
+					t.Errorf("response labels = %v, want %v", got.Labels, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForwardAddressEnv names the environment variable holding the address
+// port-forwards listen on, on the host running this API.
+const PortForwardAddressEnv = "PORT_FORWARD_ADDRESS"
+
+const (
+	defaultPortForwardAddress  = "127.0.0.1"
+	defaultPortForwardDuration = 5 * time.Minute
+	maxPortForwardDuration     = 30 * time.Minute
+
+	// portForwardReadyTimeout bounds how long establishing the forward may take
+	portForwardReadyTimeout = 10 * time.Second
+)
+
+// PortForward opens a short-lived port-forward from this API's host to a port
+// of the pod, using the API server's SPDY port-forward subresource. The
+// forward accepts connections until ?duration= (default 5m) elapses.
+func (h *PodHandler) PortForward(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	podPort, err := strconv.Atoi(c.Query("podPort"))
+	if err != nil || len(validation.IsValidPortNum(podPort)) > 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid podPort %q: must be between 1 and 65535", c.Query("podPort")),
+		})
+		return
+	}
+
+	// Zero lets the OS pick a free local port
+	localPort, err := strconv.Atoi(c.DefaultQuery("localPort", "0"))
+	if err != nil || (localPort != 0 && len(validation.IsValidPortNum(localPort)) > 0) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid localPort %q: must be between 1 and 65535, or 0 for any free port", c.Query("localPort")),
+		})
+		return
+	}
+
+	duration, err := time.ParseDuration(c.DefaultQuery("duration", defaultPortForwardDuration.String()))
+	if err != nil || duration <= 0 || duration > maxPortForwardDuration {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid duration %q: must be a duration up to %s", c.Query("duration"), maxPortForwardDuration),
+		})
+		return
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if pod == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Pod not found",
+		})
+		return
+	}
+
+	if pod.Status.Phase != corev1.PodRunning {
+		c.JSON(http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Pod is not running (status: %s)", pod.Status.Phase),
+		})
+		return
+	}
+
+	address := os.Getenv(PortForwardAddressEnv)
+	if address == "" {
+		address = defaultPortForwardAddress
+	}
+
+	forwarded, err := h.startPortForward(pod, address, localPort, podPort, duration)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to establish port-forward: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Port-forward established",
+		Data: models.PortForwardResponse{
+			UID:          uid,
+			PodName:      pod.Name,
+			Namespace:    pod.Namespace,
+			PodPort:      podPort,
+			LocalAddress: net.JoinHostPort(address, strconv.Itoa(int(forwarded.Local))),
+			ExpiresAt:    time.Now().Add(duration),
+		},
+	})
+}
+
+// dialerFactory creates the dialer opening the streaming connection a
+// port-forward to pod runs over.
+type dialerFactory func(client *k8s.K8sClient, pod *corev1.Pod) (httpstream.Dialer, error)
+
+// newSPDYDialer dials the API server's port-forward subresource over SPDY.
+func newSPDYDialer(client *k8s.K8sClient, pod *corev1.Pod) (httpstream.Dialer, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(client.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	url := client.ClientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	return spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url), nil
+}
+
+// startPortForward forwards address:localPort to podPort of pod until
+// duration elapses, returning once the listener is ready.
+func (h *PodHandler) startPortForward(pod *corev1.Pod, address string, localPort, podPort int, duration time.Duration) (portforward.ForwardedPort, error) {
+	dialer, err := h.newDialer(h.k8sClient, pod)
+	if err != nil {
+		return portforward.ForwardedPort{}, err
+	}
+
+	stop := make(chan struct{})
+	ready := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{address},
+		[]string{fmt.Sprintf("%d:%d", localPort, podPort)}, stop, ready, io.Discard, io.Discard)
+	if err != nil {
+		return portforward.ForwardedPort{}, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-ready:
+	case err := <-done:
+		return portforward.ForwardedPort{}, err
+	case <-time.After(portForwardReadyTimeout):
+		close(stop)
+		return portforward.ForwardedPort{}, fmt.Errorf("timed out after %s", portForwardReadyTimeout)
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		close(stop)
+		return portforward.ForwardedPort{}, fmt.Errorf("no forwarded port: %v", err)
+	}
+
+	expiry := time.AfterFunc(duration, func() { close(stop) })
+	go func() {
+		err := <-done
+		// The forward can also end early, e.g. when the pod goes away
+		if expiry.Stop() {
+			close(stop)
+		}
+		slog.Info("Port-forward closed", "pod", pod.Name, "namespace", pod.Namespace, "pod_port", podPort, "error", err)
+	}()
+
+	return ports[0], nil
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/tools/portforward"
+)
+
+const portForwardRoute = "/pods/:uid/portforward"
+
+// fakeStreamConn is a port-forward connection to nowhere. Streams cannot be
+// created on it, but it tracks when it is closed.
+type fakeStreamConn struct {
+	closed    chan bool
+	closeOnce sync.Once
+}
+
+func newFakeStreamConn() *fakeStreamConn {
+	return &fakeStreamConn{closed: make(chan bool)}
+}
+
+func (c *fakeStreamConn) CreateStream(http.Header) (httpstream.Stream, error) {
+	return nil, errors.New("no streams in tests")
+}
+
+func (c *fakeStreamConn) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *fakeStreamConn) CloseChan() <-chan bool             { return c.closed }
+func (c *fakeStreamConn) SetIdleTimeout(time.Duration)       {}
+func (c *fakeStreamConn) RemoveStreams(...httpstream.Stream) {}
+
+type fakeDialer struct {
+	conn *fakeStreamConn
+	err  error
+}
+
+func (d fakeDialer) Dial(...string) (httpstream.Connection, string, error) {
+	if d.err != nil {
+		return nil, "", d.err
+	}
+	return d.conn, portforward.PortForwardProtocolV1Name, nil
+}
+
+// newPortForwardTestHandler returns a pod handler holding a running pod with
+// UID "abc" whose port-forwards connect through dialer.
+func newPortForwardTestHandler(dialer fakeDialer) *PodHandler {
+	h := newTestPodHandler(testPod("web", map[string]string{"uid": "abc"}))
+	h.newDialer = func(*k8s.K8sClient, *corev1.Pod) (httpstream.Dialer, error) {
+		return dialer, nil
+	}
+	return h
+}
+
+// startTestPortForward opens a forward through h and returns its local address.
+func startTestPortForward(t *testing.T, h *PodHandler, query string) string {
+	t.Helper()
+	rec := serve(h.PortForward, http.MethodGet, portForwardRoute, "/pods/abc/portforward?podPort=80"+query, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PortForward returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var data models.PortForwardResponse
+	decodeResponse(t, rec, &data)
+	return data.LocalAddress
+}
+
+// waitClosed waits for conn to be closed and for address to stop accepting
+// connections.
+func waitClosed(t *testing.T, conn *fakeStreamConn, address string) {
+	t.Helper()
+	select {
+	case <-conn.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection to the pod was not closed")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		local, err := net.Dial("tcp", address)
+		if err != nil {
+			return
+		}
+		local.Close()
+		if time.Now().After(deadline) {
+			t.Fatalf("%s still accepts connections after the forward ended", address)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPortForwardLifecycle(t *testing.T) {
+	conn := newFakeStreamConn()
+	h := newPortForwardTestHandler(fakeDialer{conn: conn})
+
+	address := startTestPortForward(t, h, "&duration=1m")
+	if host, _, err := net.SplitHostPort(address); err != nil || host != defaultPortForwardAddress {
+		t.Fatalf("local address = %q, want one on %s", address, defaultPortForwardAddress)
+	}
+	local, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatalf("forward is not listening on %s: %v", address, err)
+	}
+	local.Close()
+
+	// Losing the connection to the pod ends the forward before its duration
+	conn.Close()
+	waitClosed(t, conn, address)
+}
+
+func TestPortForwardExpires(t *testing.T) {
+	conn := newFakeStreamConn()
+	h := newPortForwardTestHandler(fakeDialer{conn: conn})
+
+	address := startTestPortForward(t, h, "&duration=100ms")
+	waitClosed(t, conn, address)
+}
+
+func TestPortForwardDialFailure(t *testing.T) {
+	h := newPortForwardTestHandler(fakeDialer{err: errors.New("upgrade refused")})
+
+	rec := serve(h.PortForward, http.MethodGet, portForwardRoute, "/pods/abc/portforward?podPort=80", "")
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("PortForward returned %d, want 502: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/k8s"
+	"kubernetes-api/pkg/models"
+	"kubernetes-api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+type SecretHandler struct {
+	k8sClient *k8s.K8sClient
+	activity  *activity.Log
+}
+
+func NewSecretHandler(client *k8s.K8sClient, activityLog *activity.Log) *SecretHandler {
+	return &SecretHandler{k8sClient: client, activity: activityLog}
+}
+
+// CreateSecret creates an Opaque secret. Only its metadata and key names are
+// returned; the values are never echoed back.
+func (h *SecretHandler) CreateSecret(c *gin.Context) {
+	var req models.CreateSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	namespace, err := resolveNamespace(req.Namespace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Name is required",
+		})
+		return
+	}
+	if len(req.Data) == 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Data must contain at least one key",
+		})
+		return
+	}
+
+	data := make(map[string][]byte, len(req.Data))
+	for key, value := range req.Data {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   fmt.Sprintf("Invalid key %q: %s", key, strings.Join(errs, ", ")),
+			})
+			return
+		}
+		data[key] = []byte(value)
+	}
+
+	uid, err := h.generateSecretUID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: utils.GeneratePodName(utils.SanitizeName(req.Name)),
+			Labels: map[string]string{
+				"app": req.Name,
+				"uid": uid,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+
+	createdSecret, err := h.k8sClient.ClientSet.CoreV1().Secrets(namespace).Create(
+		h.k8sClient.Context, secret, metav1.CreateOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	h.activity.Record("create", "Secret", uid, createdSecret.Name, createdSecret.Namespace)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Secret created successfully",
+		Data:    secretResponse(createdSecret),
+	})
+}
+
+func secretResponse(secret *corev1.Secret) models.SecretResponse {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return models.SecretResponse{
+		UID:       secret.Labels["uid"],
+		Name:      secret.Name,
+		Namespace: secret.Namespace,
+		Type:      string(secret.Type),
+		Keys:      keys,
+		CreatedAt: secret.CreationTimestamp.Time,
+	}
+}
+
+func (h *SecretHandler) generateSecretUID() (string, error) {
+	return generateFreeUID(func(opts metav1.ListOptions) (bool, error) {
+		secrets, err := h.k8sClient.ClientSet.CoreV1().Secrets(metav1.NamespaceAll).List(h.k8sClient.Context, opts)
+		if err != nil {
+			return false, err
+		}
+		return len(secrets.Items) > 0, nil
+	})
+}
+
+// validateSecretEnv checks the shape of secret-backed environment variables,
+// without looking anything up in the cluster.
+func validateSecretEnv(vars []models.SecretEnvVar) error {
+	for _, v := range vars {
+		if errs := validation.IsEnvVarName(v.Name); len(errs) > 0 {
+			return fmt.Errorf("secret_env: invalid name %q: %s", v.Name, strings.Join(errs, ", "))
+		}
+		if v.SecretUID == "" || v.Key == "" {
+			return fmt.Errorf("secret_env %s: secret_uid and key are required", v.Name)
+		}
+	}
+	return nil
+}
+
+var errSecretNotFound = errors.New("secret not found")
+
+// resolveSecrets maps each referenced secret UID to its name in namespace,
+// failing if a secret does not exist or lacks a referenced key.
+func resolveSecrets(client *k8s.K8sClient, namespace string, vars []models.SecretEnvVar) (map[string]string, error) {
+	names := make(map[string]string)
+	for _, v := range vars {
+		secrets, err := client.ClientSet.CoreV1().Secrets(namespace).List(
+			client.Context, metav1.ListOptions{
+				LabelSelector: "uid=" + v.SecretUID,
+			})
+		if err != nil {
+			return nil, err
+		}
+		if len(secrets.Items) == 0 {
+			return nil, fmt.Errorf("%w: %s in namespace %s", errSecretNotFound, v.SecretUID, namespace)
+		}
+		if _, ok := secrets.Items[0].Data[v.Key]; !ok {
+			return nil, fmt.Errorf("%w: secret %s has no key %q", errSecretNotFound, v.SecretUID, v.Key)
+		}
+		names[v.SecretUID] = secrets.Items[0].Name
+	}
+	return names, nil
+}
+
+// attachSecretEnv adds the secret-backed variables to every container of pod
+// as secretKeyRef references, so the values never pass through this API.
+func attachSecretEnv(pod *corev1.Pod, vars []models.SecretEnvVar, names map[string]string) {
+	for _, v := range vars {
+		for i := range pod.Spec.Containers {
+			pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, corev1.EnvVar{
+				Name: v.Name,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: names[v.SecretUID]},
+						Key:                  v.Key,
+					},
+				},
+			})
+		}
+	}
+}
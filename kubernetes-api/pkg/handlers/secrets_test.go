@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/index"
+	"kubernetes-api/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const secretValue = "s3cr3t-value"
+
+func TestCreateSecretHidesValues(t *testing.T) {
+	h := NewSecretHandler(newTestClient(), activity.NewLog(10))
+
+	rec := serve(h.CreateSecret, http.MethodPost, "/secrets", "/secrets", `{"name":"db","data":{"password":"`+secretValue+`","user":"admin"}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("CreateSecret returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), secretValue) {
+		t.Errorf("CreateSecret response %s contains the secret value", rec.Body.String())
+	}
+
+	var created models.SecretResponse
+	decodeResponse(t, rec, &created)
+	if created.UID == "" || created.Type != "Opaque" || strings.Join(created.Keys, ",") != "password,user" {
+		t.Errorf("CreateSecret() = %+v, want an Opaque secret with keys password and user", created)
+	}
+
+	secret, err := h.k8sClient.ClientSet.CoreV1().Secrets("default").Get(h.k8sClient.Context, created.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data["password"]) != secretValue {
+		t.Errorf("stored password = %q, want %q", secret.Data["password"], secretValue)
+	}
+}
+
+func TestCreateSecretInvalid(t *testing.T) {
+	h := NewSecretHandler(newTestClient(), activity.NewLog(10))
+
+	for _, body := range []string{
+		`{"data":{"password":"` + secretValue + `"}}`,
+		`{"name":"db"}`,
+		`{"name":"db","data":{"bad key!":"` + secretValue + `"}}`,
+	} {
+		rec := serve(h.CreateSecret, http.MethodPost, "/secrets", "/secrets", body)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("CreateSecret(%s) returned %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+		if strings.Contains(rec.Body.String(), secretValue) {
+			t.Errorf("CreateSecret error response %s contains the secret value", rec.Body.String())
+		}
+	}
+}
+
+func TestCreatePodWithSecretEnv(t *testing.T) {
+	client := newTestClient()
+	secrets := NewSecretHandler(client, activity.NewLog(10))
+	pods := NewPodHandler(client, activity.NewLog(10), index.New(client.ClientSet, ""))
+
+	rec := serve(secrets.CreateSecret, http.MethodPost, "/secrets", "/secrets", `{"name":"db","data":{"password":"`+secretValue+`"}}`)
+	var secret models.SecretResponse
+	decodeResponse(t, rec, &secret)
+
+	body := `{"name":"web","image":"nginx","container_name":"nginx",
+		"secret_env":[{"name":"DB_PASSWORD","secret_uid":"` + secret.UID + `","key":"password"}]}`
+	rec = serve(pods.CreatePod, http.MethodPost, "/pods", "/pods", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("CreatePod returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var created models.PodResponse
+	decodeResponse(t, rec, &created)
+
+	get := serve(pods.GetPodByUID, http.MethodGet, "/pods/:uid", "/pods/"+created.UID, "")
+	for name, response := range map[string]string{"CreatePod": rec.Body.String(), "GetPodByUID": get.Body.String()} {
+		if strings.Contains(response, secretValue) {
+			t.Errorf("%s response %s contains the secret value", name, response)
+		}
+	}
+
+	pod, err := client.ClientSet.CoreV1().Pods("default").Get(client.Context, created.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := pod.Spec.Containers[0].Env
+	i := slices.IndexFunc(env, func(e corev1.EnvVar) bool { return e.Name == "DB_PASSWORD" })
+	if i < 0 || env[i].Value != "" || env[i].ValueFrom == nil || env[i].ValueFrom.SecretKeyRef == nil ||
+		env[i].ValueFrom.SecretKeyRef.Name != secret.Name || env[i].ValueFrom.SecretKeyRef.Key != "password" {
+		t.Errorf("container env = %+v, want DB_PASSWORD referencing %s/password", env, secret.Name)
+	}
+
+	for _, ref := range []string{
+		`{"name":"DB_PASSWORD","secret_uid":"missing","key":"password"}`,
+		`{"name":"DB_PASSWORD","secret_uid":"` + secret.UID + `","key":"missing"}`,
+		`{"name":"1BAD","secret_uid":"` + secret.UID + `","key":"password"}`,
+	} {
+		body := `{"name":"web","image":"nginx","container_name":"nginx","secret_env":[` + ref + `]}`
+		if code := createPodStatus(pods, body); code != http.StatusBadRequest {
+			t.Errorf("CreatePod with secret_env %s returned %d, want %d", ref, code, http.StatusBadRequest)
+		}
+	}
+}
@@ -1,8 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/index"
 	"kubernetes-api/pkg/k8s"
 	"kubernetes-api/pkg/models"
 	"kubernetes-api/pkg/utils"
@@ -11,14 +19,18 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 type ServiceHandler struct {
 	k8sClient *k8s.K8sClient
+	activity  *activity.Log
+	uidIndex  *index.Index
 }
 
-func NewServiceHandler(client *k8s.K8sClient) *ServiceHandler {
-	return &ServiceHandler{k8sClient: client}
+func NewServiceHandler(client *k8s.K8sClient, activityLog *activity.Log, uidIndex *index.Index) *ServiceHandler {
+	return &ServiceHandler{k8sClient: client, activity: activityLog, uidIndex: uidIndex}
 }
 
 func (h *ServiceHandler) CreateService(c *gin.Context) {
@@ -31,13 +43,87 @@ func (h *ServiceHandler) CreateService(c *gin.Context) {
 		return
 	}
 
-	uid := utils.GenerateUID()
-	serviceName := utils.GeneratePodName(utils.SanitizeName(req.Name))
+	namespace, err := resolveNamespace(req.Namespace)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "name is required",
+		})
+		return
+	}
+
+	if err := validateAnnotations(req.Annotations); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
 
 	serviceType := corev1.ServiceTypeClusterIP
 	if req.ServiceType != "" {
 		serviceType = corev1.ServiceType(req.ServiceType)
 	}
+	switch serviceType {
+	case corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeExternalName:
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("invalid service_type %q: must be ClusterIP, NodePort, LoadBalancer or ExternalName", req.ServiceType),
+		})
+		return
+	}
+
+	var ports []corev1.ServicePort
+	if serviceType == corev1.ServiceTypeExternalName {
+		// ExternalName services are DNS aliases: no selector, ports optional
+		err = validateExternalName(req.ExternalName)
+		if err == nil && (req.Port != 0 || len(req.Ports) > 0) {
+			ports, err = buildServicePorts(req, serviceType)
+		}
+	} else if req.ExternalName != "" {
+		err = fmt.Errorf("external_name is only valid with service_type ExternalName")
+	} else {
+		ports, err = buildServicePorts(req, serviceType)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// Refuse to create a service that selects nothing
+	if serviceType != corev1.ServiceTypeExternalName {
+		status, err := h.checkPodExists(namespace, req.PodUID)
+		if err != nil {
+			c.JSON(status, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	uid, err := h.generateServiceUID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	serviceName := utils.GeneratePodName(utils.SanitizeName(req.Name))
 
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -45,22 +131,22 @@ func (h *ServiceHandler) CreateService(c *gin.Context) {
 			Labels: map[string]string{
 				"uid": uid,
 			},
+			Annotations: req.Annotations,
 		},
 		Spec: corev1.ServiceSpec{
-			Selector: map[string]string{
-				"uid": req.PodUID,
-			},
-			Ports: []corev1.ServicePort{
-				{
-					Port:       req.Port,
-					TargetPort: intstr.FromInt(int(req.TargetPort)),
-				},
-			},
-			Type: serviceType,
+			Ports: ports,
+			Type:  serviceType,
 		},
 	}
+	if serviceType == corev1.ServiceTypeExternalName {
+		service.Spec.ExternalName = req.ExternalName
+	} else {
+		service.Spec.Selector = map[string]string{
+			"uid": req.PodUID,
+		}
+	}
 
-	createdService, err := h.k8sClient.ClientSet.CoreV1().Services("default").Create(
+	createdService, err := h.k8sClient.ClientSet.CoreV1().Services(namespace).Create(
 		h.k8sClient.Context, service, metav1.CreateOptions{})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
@@ -69,27 +155,31 @@ func (h *ServiceHandler) CreateService(c *gin.Context) {
 		})
 		return
 	}
-
-	response := models.ServiceResponse{
-		UID:         uid,
-		Name:        createdService.Name,
-		Namespace:   createdService.Namespace,
-		ServiceType: string(createdService.Spec.Type),
-		ClusterIP:   createdService.Spec.ClusterIP,
-		Port:        createdService.Spec.Ports[0].Port,
-		TargetPort:  createdService.Spec.Ports[0].TargetPort.IntVal,
-	}
+	h.activity.Record("create", "Service", uid, createdService.Name, createdService.Namespace)
+	recordUID(h.k8sClient, h.uidIndex, "Service", uid, createdService.Namespace, createdService.Name)
 
 	c.JSON(http.StatusCreated, models.APIResponse{
 		Success: true,
 		Message: "Service created successfully",
-		Data:    response,
+		Data:    serviceResponse(createdService),
 	})
 }
 
 func (h *ServiceHandler) ListServices(c *gin.Context) {
-	services, err := h.k8sClient.ClientSet.CoreV1().Services("default").List(
-		h.k8sClient.Context, metav1.ListOptions{})
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	opts, ok := pageOptions(c)
+	if !ok {
+		return
+	}
+	// Filter on the server so pages are not thinned out by unmanaged services
+	opts.LabelSelector = "uid"
+
+	services, err := h.k8sClient.ClientSet.CoreV1().Services(namespace).List(
+		h.k8sClient.Context, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
@@ -101,18 +191,7 @@ func (h *ServiceHandler) ListServices(c *gin.Context) {
 	var serviceResponses []models.ServiceResponse
 	for _, service := range services.Items {
 		if service.Labels["uid"] != "" {
-			serviceResponse := models.ServiceResponse{
-				UID:         service.Labels["uid"],
-				Name:        service.Name,
-				Namespace:   service.Namespace,
-				ServiceType: string(service.Spec.Type),
-				ClusterIP:   service.Spec.ClusterIP,
-			}
-			if len(service.Spec.Ports) > 0 {
-				serviceResponse.Port = service.Spec.Ports[0].Port
-				serviceResponse.TargetPort = service.Spec.Ports[0].TargetPort.IntVal
-			}
-			serviceResponses = append(serviceResponses, serviceResponse)
+			serviceResponses = append(serviceResponses, serviceResponse(&service))
 		}
 	}
 
@@ -125,8 +204,383 @@ func (h *ServiceHandler) ListServices(c *gin.Context) {
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Data: models.ListResponse{
-			Items: items,
-			Count: len(serviceResponses),
+			Items:    items,
+			Count:    len(serviceResponses),
+			Continue: services.Continue,
 		},
 	})
 }
+
+// GetServiceByUID returns the service with the given UID. With ?wait=true a
+// LoadBalancer service is polled for up to ?timeout= (default 30s) until it
+// gets an external address; running out of time is not an error.
+func (h *ServiceHandler) GetServiceByUID(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	waitIngress := c.Query("wait") == "true"
+	timeout, err := time.ParseDuration(c.DefaultQuery("timeout", "30s"))
+	if err != nil || timeout <= 0 || timeout > maxReadyTimeout {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid timeout %q: must be a duration up to %s", c.Query("timeout"), maxReadyTimeout),
+		})
+		return
+	}
+
+	service, err := serviceByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if service == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Service not found",
+		})
+		return
+	}
+
+	message := ""
+	if waitIngress && service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		service = h.waitForIngress(c.Request.Context(), service, timeout)
+		message = "Load balancer address assigned"
+		if len(service.Status.LoadBalancer.Ingress) == 0 {
+			message = fmt.Sprintf("No load balancer address after %s", timeout)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    serviceResponse(service),
+	})
+}
+
+// waitForIngress polls service until the cloud provider assigns it a load
+// balancer address or timeout elapses, returning the last observed service.
+func (h *ServiceHandler) waitForIngress(ctx context.Context, service *corev1.Service, timeout time.Duration) *corev1.Service {
+	// A timeout is reported through the missing ingress, not an error
+	_ = wait.PollUntilContextTimeout(ctx, readyPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		current, err := h.k8sClient.ClientSet.CoreV1().Services(service.Namespace).Get(ctx, service.Name, metav1.GetOptions{})
+		if err != nil {
+			// Transient API errors are retried until the timeout
+			return false, nil
+		}
+		service = current
+		return len(service.Status.LoadBalancer.Ingress) > 0, nil
+	})
+	return service
+}
+
+func (h *ServiceHandler) DeleteServiceByUID(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	service, err := serviceByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if service == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Service not found",
+		})
+		return
+	}
+
+	err = h.k8sClient.ClientSet.CoreV1().Services(namespace).Delete(
+		h.k8sClient.Context, service.Name, metav1.DeleteOptions{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	h.activity.Record("delete", "Service", uid, service.Name, service.Namespace)
+	forgetUID(h.k8sClient, h.uidIndex, "Service", uid)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Service deleted successfully",
+	})
+}
+
+// checkPodExists verifies that a pod labelled with podUID exists in namespace,
+// returning the HTTP status to report when it does not.
+func (h *ServiceHandler) checkPodExists(namespace, podUID string) (int, error) {
+	if podUID == "" {
+		return http.StatusBadRequest, fmt.Errorf("pod_uid is required")
+	}
+	if errs := validation.IsValidLabelValue(podUID); len(errs) > 0 {
+		return http.StatusBadRequest, fmt.Errorf("invalid pod_uid %q: %s", podUID, strings.Join(errs, ", "))
+	}
+
+	pods, err := h.k8sClient.ClientSet.CoreV1().Pods(namespace).List(
+		h.k8sClient.Context, metav1.ListOptions{
+			LabelSelector: "uid=" + podUID,
+			Limit:         1,
+		})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if len(pods.Items) == 0 {
+		return http.StatusNotFound, fmt.Errorf("pod %s not found in namespace %s", podUID, namespace)
+	}
+	return http.StatusOK, nil
+}
+
+func serviceResponse(service *corev1.Service) models.ServiceResponse {
+	response := models.ServiceResponse{
+		UID:          service.Labels["uid"],
+		Name:         service.Name,
+		Namespace:    service.Namespace,
+		Annotations:  service.Annotations,
+		ServiceType:  string(service.Spec.Type),
+		ClusterIP:    service.Spec.ClusterIP,
+		ExternalName: service.Spec.ExternalName,
+		Ports:        servicePortsResponse(service.Spec.Ports),
+	}
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		response.Ingress = append(response.Ingress, models.ServiceIngress{
+			IP:       ingress.IP,
+			Hostname: ingress.Hostname,
+		})
+	}
+	if len(service.Spec.Ports) > 0 {
+		response.Port = service.Spec.Ports[0].Port
+		response.TargetPort = service.Spec.Ports[0].TargetPort
+	}
+	return response
+}
+
+// buildServicePorts turns the request's port list (or its single port/target_port
+// shortcut) into service ports. Ports must be named when there is more than one,
+// and neither names nor port/protocol pairs may repeat.
+func buildServicePorts(req models.CreateServiceRequest, serviceType corev1.ServiceType) ([]corev1.ServicePort, error) {
+	specs := req.Ports
+	if len(specs) == 0 {
+		specs = []models.ServicePortSpec{{Port: req.Port, TargetPort: req.TargetPort}}
+	} else if req.Port != 0 {
+		return nil, fmt.Errorf("specify either port/target_port or ports, not both")
+	}
+
+	names := make(map[string]bool)
+	seen := make(map[string]bool)
+	var ports []corev1.ServicePort
+	for _, spec := range specs {
+		if err := validateServicePorts(spec.Port, spec.TargetPort); err != nil {
+			return nil, err
+		}
+
+		protocol := corev1.ProtocolTCP
+		if spec.Protocol != "" {
+			protocol = corev1.Protocol(strings.ToUpper(spec.Protocol))
+		}
+		switch protocol {
+		case corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP:
+		default:
+			return nil, fmt.Errorf("invalid protocol %q: must be TCP, UDP or SCTP", spec.Protocol)
+		}
+
+		if len(specs) > 1 {
+			if spec.Name == "" {
+				return nil, fmt.Errorf("port %d: name is required when more than one port is given", spec.Port)
+			}
+			if errs := validation.IsDNS1123Label(spec.Name); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid port name %q: %s", spec.Name, strings.Join(errs, ", "))
+			}
+			if names[spec.Name] {
+				return nil, fmt.Errorf("duplicate port name %q", spec.Name)
+			}
+			names[spec.Name] = true
+		}
+
+		if spec.NodePort != 0 {
+			if serviceType != corev1.ServiceTypeNodePort && serviceType != corev1.ServiceTypeLoadBalancer {
+				return nil, fmt.Errorf("port %d: node_port is only valid for NodePort and LoadBalancer services", spec.Port)
+			}
+			if errs := validation.IsValidPortNum(int(spec.NodePort)); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid node_port %d: %s", spec.NodePort, strings.Join(errs, ", "))
+			}
+		}
+
+		key := fmt.Sprintf("%d/%s", spec.Port, protocol)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate port %s", key)
+		}
+		seen[key] = true
+
+		ports = append(ports, corev1.ServicePort{
+			Name:       spec.Name,
+			Port:       spec.Port,
+			TargetPort: spec.TargetPort,
+			Protocol:   protocol,
+			NodePort:   spec.NodePort,
+		})
+	}
+
+	return ports, nil
+}
+
+// servicePortsResponse converts service ports to their API representation.
+func servicePortsResponse(ports []corev1.ServicePort) []models.ServicePortSpec {
+	specs := []models.ServicePortSpec{}
+	for _, port := range ports {
+		specs = append(specs, models.ServicePortSpec{
+			Name:       port.Name,
+			Port:       port.Port,
+			TargetPort: port.TargetPort,
+			Protocol:   string(port.Protocol),
+			NodePort:   port.NodePort,
+		})
+	}
+	return specs
+}
+
+// validateExternalName checks that name is a DNS name an ExternalName service
+// can alias, e.g. "db.example.com".
+func validateExternalName(name string) error {
+	if name == "" {
+		return fmt.Errorf("external_name is required for ExternalName services")
+	}
+	if errs := validation.IsDNS1123Subdomain(strings.TrimSuffix(name, ".")); len(errs) > 0 {
+		return fmt.Errorf("invalid external_name %q: %s", name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validateServicePorts checks that port is a valid port number and that
+// targetPort is either a valid port number or a valid named port. A zero
+// targetPort is allowed and defaults to port.
+func validateServicePorts(port int32, targetPort intstr.IntOrString) error {
+	if errs := validation.IsValidPortNum(int(port)); len(errs) > 0 {
+		return fmt.Errorf("invalid port %d: %s", port, strings.Join(errs, ", "))
+	}
+
+	if targetPort.Type == intstr.String {
+		if errs := validation.IsValidPortName(targetPort.StrVal); len(errs) > 0 {
+			return fmt.Errorf("invalid target_port %q: %s", targetPort.StrVal, strings.Join(errs, ", "))
+		}
+		return nil
+	}
+
+	if targetPort.IntVal == 0 {
+		return nil
+	}
+	if errs := validation.IsValidPortNum(int(targetPort.IntVal)); len(errs) > 0 {
+		return fmt.Errorf("invalid target_port %d: %s", targetPort.IntVal, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// ProbeService issues an HTTP GET to a service through the API server's
+// service proxy, which reaches the service from inside the cluster network.
+func (h *ServiceHandler) ProbeService(c *gin.Context) {
+	uid := c.Param("uid")
+	namespace, ok := queryNamespace(c)
+	if !ok {
+		return
+	}
+
+	path := c.DefaultQuery("path", "/")
+	timeout, err := time.ParseDuration(c.DefaultQuery("timeout", "5s"))
+	if err != nil || timeout <= 0 || timeout > maxReadyTimeout {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid timeout %q: must be a duration up to %s", c.Query("timeout"), maxReadyTimeout),
+		})
+		return
+	}
+
+	service, err := serviceByUID(h.k8sClient, h.uidIndex, namespace, uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if service == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Service not found",
+		})
+		return
+	}
+
+	if service.Spec.Type == corev1.ServiceTypeExternalName || len(service.Spec.Ports) == 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Service has no ports to probe",
+		})
+		return
+	}
+
+	port := c.DefaultQuery("port", strconv.Itoa(int(service.Spec.Ports[0].Port)))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := h.k8sClient.ClientSet.CoreV1().RESTClient().Get().
+		Namespace(service.Namespace).
+		Resource("services").
+		Name(service.Name + ":" + port).
+		SubResource("proxy").
+		Suffix(path).
+		Do(ctx)
+	latency := time.Since(start)
+
+	var statusCode int
+	result.StatusCode(&statusCode)
+	probeErr := result.Error()
+
+	response := models.ServiceProbeResponse{
+		UID:        uid,
+		Name:       service.Name,
+		Port:       port,
+		Path:       path,
+		StatusCode: statusCode,
+		LatencyMs:  latency.Milliseconds(),
+	}
+
+	// The proxy reports backend failures as 503s, so classify them by message
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		response.Outcome = "timeout"
+		response.StatusCode = 0
+	case probeErr != nil && strings.Contains(probeErr.Error(), "connection refused"):
+		response.Outcome = "connection_refused"
+	case probeErr != nil && strings.Contains(probeErr.Error(), "no endpoints available"):
+		response.Outcome = "no_endpoints"
+	case statusCode == 0:
+		response.Outcome = "error"
+	case statusCode >= http.StatusBadRequest:
+		response.Outcome = "http_error"
+	default:
+		response.Outcome = "ok"
+	}
+	if probeErr != nil && response.Outcome != "http_error" {
+		response.Error = probeErr.Error()
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"maps"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"kubernetes-api/pkg/activity"
+	"kubernetes-api/pkg/index"
+	"kubernetes-api/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetServiceByUID(t *testing.T) {
+	h := newTestServiceHandler(testService("web", map[string]string{"uid": "abc"}))
+
+	rec := serve(h.GetServiceByUID, http.MethodGet, "/services/:uid", "/services/abc", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GetServiceByUID returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var got models.ServiceResponse
+	decodeResponse(t, rec, &got)
+	if got.UID != "abc" || got.Name != "web" || got.Port != 80 {
+		t.Errorf("GetServiceByUID = %+v, want service web with uid abc on port 80", got)
+	}
+
+	rec = serve(h.GetServiceByUID, http.MethodGet, "/services/:uid", "/services/missing", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GetServiceByUID of an unknown uid returned %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestDeleteServiceByUID(t *testing.T) {
+	h := newTestServiceHandler(testService("web", map[string]string{"uid": "abc"}))
+
+	rec := serve(h.DeleteServiceByUID, http.MethodDelete, "/services/:uid", "/services/abc", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("DeleteServiceByUID returned %d: %s", rec.Code, rec.Body.String())
+	}
+	services, err := h.k8sClient.ClientSet.CoreV1().Services("default").List(h.k8sClient.Context, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services.Items) != 0 {
+		t.Errorf("%d services left after DeleteServiceByUID, want 0", len(services.Items))
+	}
+
+	rec = serve(h.DeleteServiceByUID, http.MethodDelete, "/services/:uid", "/services/abc", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("DeleteServiceByUID of a deleted service returned %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestTwoPortPodBehindTwoPortService(t *testing.T) {
+	client := newTestClient()
+	pods := NewPodHandler(client, activity.NewLog(10), index.New(client.ClientSet, ""))
+	services := NewServiceHandler(client, activity.NewLog(10), index.New(client.ClientSet, ""))
+
+	pod := createPod(t, pods, `{"name":"web","image":"web","container_name":"web",
+		"ports":[{"name":"http","container_port":8080},{"name":"metrics","container_port":9090,"protocol":"tcp"}]}`)
+	if ports := pod.Spec.Containers[0].Ports; len(ports) != 2 ||
+		ports[0].Name != "http" || ports[0].ContainerPort != 8080 ||
+		ports[1].Name != "metrics" || ports[1].ContainerPort != 9090 || ports[1].Protocol != corev1.ProtocolTCP {
+		t.Fatalf("container ports = %+v, want http 8080 and metrics 9090", ports)
+	}
+
+	body := `{"name":"web","pod_uid":"` + pod.Labels["uid"] + `","service_type":"NodePort","ports":[
+		{"name":"http","port":80,"target_port":"http","node_port":30080},
+		{"name":"metrics","port":9090,"target_port":9090}]}`
+	rec := serve(services.CreateService, http.MethodPost, "/services", "/services", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("CreateService returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var created models.ServiceResponse
+	decodeResponse(t, rec, &created)
+
+	want := []models.ServicePortSpec{
+		{Name: "http", Port: 80, TargetPort: intstr.FromString("http"), Protocol: "TCP", NodePort: 30080},
+		{Name: "metrics", Port: 9090, TargetPort: intstr.FromInt32(9090), Protocol: "TCP"},
+	}
+	if !reflect.DeepEqual(created.Ports, want) {
+		t.Errorf("CreateService ports = %+v, want %+v", created.Ports, want)
+	}
+	if created.Port != 80 || created.TargetPort != intstr.FromString("http") {
+		t.Errorf("CreateService port/target_port = %d/%s, want the first port 80/http", created.Port, created.TargetPort.String())
+	}
+
+	service, err := client.ClientSet.CoreV1().Services("default").Get(client.Context, created.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(service.Spec.Ports) != 2 || service.Spec.Ports[0].NodePort != 30080 || service.Spec.Selector["uid"] != pod.Labels["uid"] {
+		t.Errorf("stored service spec = %+v, want both ports selecting the pod", service.Spec)
+	}
+}
+
+func TestCreateServiceInvalidPorts(t *testing.T) {
+	h := newTestServiceHandler(testPod("web", map[string]string{"uid": "abc"}))
+
+	for _, ports := range []string{
+		`"ports":[{"port":80},{"port":9090}]`,
+		`"ports":[{"name":"http","port":80},{"name":"http","port":9090}]`,
+		`"ports":[{"name":"a","port":80},{"name":"b","port":80}]`,
+		`"port":80,"ports":[{"port":9090}]`,
+		`"ports":[{"port":80,"node_port":30080}]`,
+		`"ports":[{"port":80,"protocol":"HTTP"}]`,
+		`"ports":[{"port":70000}]`,
+	} {
+		body := `{"name":"web","pod_uid":"abc",` + ports + `}`
+		if rec := serve(h.CreateService, http.MethodPost, "/services", "/services", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("CreateService with %s returned %d, want %d", ports, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetServiceByUIDWaitForIngress(t *testing.T) {
+	lb := testService("web", map[string]string{"uid": "abc"})
+	lb.Spec.Type = corev1.ServiceTypeLoadBalancer
+	h := newTestServiceHandler(lb)
+
+	// The load balancer address is assigned once the service has been polled
+	polls := 0
+	h.k8sClient.ClientSet.(*fake.Clientset).PrependReactor("get", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		polls++
+		if polls == 2 {
+			assigned := lb.DeepCopy()
+			assigned.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.10", Hostname: "web.example.com"}}
+			h.k8sClient.ClientSet.(*fake.Clientset).Tracker().Update(corev1.SchemeGroupVersion.WithResource("services"), assigned, "default")
+		}
+		return false, nil, nil
+	})
+
+	rec := serve(h.GetServiceByUID, http.MethodGet, "/services/:uid", "/services/abc?wait=true&timeout=10s", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GetServiceByUID returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var got models.ServiceResponse
+	resp := decodeResponse(t, rec, &got)
+	if polls != 2 {
+		t.Errorf("service was polled %d times, want 2", polls)
+	}
+	if len(got.Ingress) != 1 || got.Ingress[0].IP != "203.0.113.10" || got.Ingress[0].Hostname != "web.example.com" {
+		t.Errorf("GetServiceByUID ingress = %+v, want the assigned address", got.Ingress)
+	}
+	if resp.Message != "Load balancer address assigned" {
+		t.Errorf("Message = %q, want the address reported as assigned", resp.Message)
+	}
+}
+
+func TestGetServiceByUIDWaitTimeout(t *testing.T) {
+	lb := testService("web", map[string]string{"uid": "abc"})
+	lb.Spec.Type = corev1.ServiceTypeLoadBalancer
+	h := newTestServiceHandler(lb)
+
+	rec := serve(h.GetServiceByUID, http.MethodGet, "/services/:uid", "/services/abc?wait=true&timeout=1s", "")
+	var got models.ServiceResponse
+	resp := decodeResponse(t, rec, &got)
+	if rec.Code != http.StatusOK || len(got.Ingress) != 0 || !strings.Contains(resp.Message, "No load balancer address after 1s") {
+		t.Errorf("GetServiceByUID returned %d with %+v and %q, want the timeout reported", rec.Code, got.Ingress, resp.Message)
+	}
+
+	rec = serve(h.GetServiceByUID, http.MethodGet, "/services/:uid", "/services/abc?wait=true&timeout=forever", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("GetServiceByUID with an invalid timeout returned %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetServiceByUIDNodePorts(t *testing.T) {
+	svc := testService("web", map[string]string{"uid": "abc"})
+	svc.Spec.Type = corev1.ServiceTypeNodePort
+	svc.Spec.Ports = []corev1.ServicePort{
+		{Name: "http", Port: 80, NodePort: 30080, Protocol: corev1.ProtocolTCP},
+		{Name: "metrics", Port: 9090, NodePort: 30090, Protocol: corev1.ProtocolTCP},
+	}
+	h := newTestServiceHandler(svc)
+
+	// wait only applies to LoadBalancer services, so this returns at once
+	rec := serve(h.GetServiceByUID, http.MethodGet, "/services/:uid", "/services/abc?wait=true", "")
+	var got models.ServiceResponse
+	decodeResponse(t, rec, &got)
+	if len(got.Ports) != 2 || got.Ports[0].NodePort != 30080 || got.Ports[1].NodePort != 30090 {
+		t.Errorf("GetServiceByUID ports = %+v, want node ports 30080 and 30090", got.Ports)
+	}
+}
+
+func TestCreateServiceMissingTargetPod(t *testing.T) {
+	other := testPod("web-other", map[string]string{"uid": "elsewhere"})
+	other.Namespace = "team-a"
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"unknown pod", `{"name":"web","pod_uid":"missing","port":80}`, http.StatusNotFound},
+		{"pod in another namespace", `{"name":"web","pod_uid":"elsewhere","port":80}`, http.StatusNotFound},
+		{"no pod_uid", `{"name":"web","port":80}`, http.StatusBadRequest},
+		{"invalid pod_uid", `{"name":"web","pod_uid":"not a uid!","port":80}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestServiceHandler(other)
+			rec := serve(h.CreateService, http.MethodPost, "/services", "/services", tt.body)
+			if rec.Code != tt.want {
+				t.Fatalf("CreateService returned %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+
+			for _, action := range h.k8sClient.ClientSet.(*fake.Clientset).Actions() {
+				if action.GetVerb() == "create" && action.GetResource().Resource == "services" {
+					t.Errorf("a service was created for a request rejected with %d", rec.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceAnnotationsPersist(t *testing.T) {
+	client := newTestClient()
+	pods := NewPodHandler(client, activity.NewLog(10), index.New(client.ClientSet, ""))
+	services := NewServiceHandler(client, activity.NewLog(10), index.New(client.ClientSet, ""))
+	pod := createPod(t, pods, `{"name":"web","image":"nginx"}`)
+
+	annotations := map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"}
+	body := `{"name":"web","pod_uid":"` + pod.Labels["uid"] + `","port":80,
+		"annotations":{"service.beta.kubernetes.io/aws-load-balancer-internal":"true"}}`
+	rec := serve(services.CreateService, http.MethodPost, "/services", "/services", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("CreateService returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var created models.ServiceResponse
+	decodeResponse(t, rec, &created)
+
+	rec = serve(services.GetServiceByUID, http.MethodGet, "/services/:uid", "/services/"+created.UID, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GetServiceByUID returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var got models.ServiceResponse
+	decodeResponse(t, rec, &got)
+	if !maps.Equal(got.Annotations, annotations) {
+		t.Errorf("annotations = %v, want %v", got.Annotations, annotations)
+	}
+}
+
+func TestProbeServiceTimeout(t *testing.T) {
+	h := newTestServiceHandler()
+
+	// An accepted timeout gets as far as looking the service up
+	tests := []struct {
+		timeout string
+		code    int
+	}{
+		{"5m", http.StatusNotFound},
+		{"5m1s", http.StatusBadRequest},
+		{"1h", http.StatusBadRequest},
+		{"0s", http.StatusBadRequest},
+		{"soon", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := serve(h.ProbeService, http.MethodGet, "/services/:uid/probe", "/services/abc/probe?timeout="+tt.timeout, "")
+		if rec.Code != tt.code {
+			t.Errorf("timeout %s: ProbeService returned %d, want %d: %s", tt.timeout, rec.Code, tt.code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+
+	"kubernetes-api/pkg/utils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxUIDAttempts bounds how many times a colliding UID is regenerated before
+// giving up.
+const maxUIDAttempts = 5
+
+// generateFreeUID returns a new UID that taken reports as unused. taken is
+// given a "uid=<uid>" label selector and should check every namespace, since
+// the UID index is not namespaced.
+func generateFreeUID(taken func(opts metav1.ListOptions) (bool, error)) (string, error) {
+	for attempt := 0; attempt < maxUIDAttempts; attempt++ {
+		uid := utils.NewUID()
+		inUse, err := taken(metav1.ListOptions{LabelSelector: "uid=" + uid, Limit: 1})
+		if err != nil {
+			return "", fmt.Errorf("failed to check UID %s for collisions: %v", uid, err)
+		}
+		if !inUse {
+			return uid, nil
+		}
+	}
+	return "", fmt.Errorf("could not generate an unused UID after %d attempts", maxUIDAttempts)
+}
+
+func (h *PodHandler) generatePodUID() (string, error) {
+	return generateFreeUID(func(opts metav1.ListOptions) (bool, error) {
+		pods, err := h.k8sClient.ClientSet.CoreV1().Pods(metav1.NamespaceAll).List(h.k8sClient.Context, opts)
+		if err != nil {
+			return false, err
+		}
+		return len(pods.Items) > 0, nil
+	})
+}
+
+func (h *ServiceHandler) generateServiceUID() (string, error) {
+	return generateFreeUID(func(opts metav1.ListOptions) (bool, error) {
+		services, err := h.k8sClient.ClientSet.CoreV1().Services(metav1.NamespaceAll).List(h.k8sClient.Context, opts)
+		if err != nil {
+			return false, err
+		}
+		return len(services.Items) > 0, nil
+	})
+}
+
+func (h *DeploymentHandler) generateDeploymentUID() (string, error) {
+	return generateFreeUID(func(opts metav1.ListOptions) (bool, error) {
+		deployments, err := h.k8sClient.ClientSet.AppsV1().Deployments(metav1.NamespaceAll).List(h.k8sClient.Context, opts)
+		if err != nil {
+			return false, err
+		}
+		return len(deployments.Items) > 0, nil
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGeneratePodUIDRetriesOnCollision(t *testing.T) {
+	h := newTestPodHandler()
+
+	// Seed a pod carrying whichever UID is tried first, so it collides
+	var tried []string
+	h.k8sClient.ClientSet.(*fake.Clientset).PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selector := action.(k8stesting.ListAction).GetListRestrictions().Labels.String()
+		uid, ok := strings.CutPrefix(selector, "uid=")
+		if !ok {
+			return false, nil, nil
+		}
+		tried = append(tried, uid)
+		if len(tried) == 1 {
+			h.k8sClient.ClientSet.(*fake.Clientset).Tracker().Add(testPod("existing", map[string]string{"uid": uid}))
+		}
+		return false, nil, nil
+	})
+
+	uid, err := h.generatePodUID()
+	if err != nil {
+		t.Fatalf("generatePodUID() failed: %v", err)
+	}
+	if len(tried) != 2 || uid != tried[1] {
+		t.Errorf("generatePodUID() tried %v and returned %q, want a second, unused UID", tried, uid)
+	}
+}
+
+func TestCreatePodFailsWithoutFreeUID(t *testing.T) {
+	h := newTestPodHandler()
+
+	// Every UID is reported as taken
+	h.k8sClient.ClientSet.(*fake.Clientset).PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selector := action.(k8stesting.ListAction).GetListRestrictions().Labels.String()
+		uid, _ := strings.CutPrefix(selector, "uid=")
+		return true, &corev1.PodList{Items: []corev1.Pod{*testPod("existing", map[string]string{"uid": uid})}}, nil
+	})
+
+	rec := serve(h.CreatePod, http.MethodPost, "/pods", "/pods", `{"name":"web","image":"nginx","container_name":"nginx"}`)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("CreatePod with every UID taken returned %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	for _, action := range h.k8sClient.ClientSet.(*fake.Clientset).Actions() {
+		if action.GetVerb() == "create" {
+			t.Errorf("CreatePod created a pod although no UID was free")
+		}
+	}
+}
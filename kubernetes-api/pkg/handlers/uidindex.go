@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"kubernetes-api/pkg/index"
+	"kubernetes-api/pkg/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// recordUID adds an object to the UID index. The index is only a fallback for
+// lookups, so a failure is logged rather than failing the request.
+func recordUID(client *k8s.K8sClient, uidIndex *index.Index, kind, uid, namespace, name string) {
+	if err := uidIndex.Record(client.Context, kind, uid, namespace, name); err != nil {
+		slog.Warn("Failed to record object in the UID index", "kind", kind, "uid", uid, "error", err)
+	}
+}
+
+func forgetUID(client *k8s.K8sClient, uidIndex *index.Index, kind, uid string) {
+	if err := uidIndex.Remove(client.Context, kind, uid); err != nil {
+		slog.Warn("Failed to remove object from the UID index", "kind", kind, "uid", uid, "error", err)
+	}
+}
+
+// podByUID finds the pod with the given UID in namespace. The uid label is the
+// primary lookup; pods that lost the label are found through the UID index.
+// Terminating pods are skipped, and while a replacement is starting alongside
+// an old pod the newest one wins. It returns nil when there is no such pod.
+func podByUID(client *k8s.K8sClient, uidIndex *index.Index, namespace, uid string) (*corev1.Pod, error) {
+	pods, err := client.ClientSet.CoreV1().Pods(namespace).List(
+		client.Context, metav1.ListOptions{
+			LabelSelector: "uid=" + uid,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var newest *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if newest == nil || newest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			newest = pod
+		}
+	}
+	if newest != nil {
+		return newest, nil
+	}
+	return indexedPod(client, uidIndex, namespace, uid)
+}
+
+// serviceByUID is podByUID for services.
+func serviceByUID(client *k8s.K8sClient, uidIndex *index.Index, namespace, uid string) (*corev1.Service, error) {
+	services, err := client.ClientSet.CoreV1().Services(namespace).List(
+		client.Context, metav1.ListOptions{
+			LabelSelector: "uid=" + uid,
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(services.Items) > 0 {
+		return &services.Items[0], nil
+	}
+	return indexedService(client, uidIndex, namespace, uid)
+}
+
+// indexedPod resolves a pod through the UID index, for pods that no longer
+// carry their uid label. It returns nil when the index has no live pod for uid
+// in namespace.
+func indexedPod(client *k8s.K8sClient, uidIndex *index.Index, namespace, uid string) (*corev1.Pod, error) {
+	entry, found, err := uidIndex.Lookup(client.Context, "Pod", uid)
+	if err != nil || !found || entry.Namespace != namespace {
+		return nil, err
+	}
+
+	pod, err := client.ClientSet.CoreV1().Pods(namespace).Get(client.Context, entry.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) || (err == nil && pod.DeletionTimestamp != nil) {
+		return nil, nil
+	}
+	return pod, err
+}
+
+// indexedService is indexedPod for services.
+func indexedService(client *k8s.K8sClient, uidIndex *index.Index, namespace, uid string) (*corev1.Service, error) {
+	entry, found, err := uidIndex.Lookup(client.Context, "Service", uid)
+	if err != nil || !found || entry.Namespace != namespace {
+		return nil, err
+	}
+
+	service, err := client.ClientSet.CoreV1().Services(namespace).Get(client.Context, entry.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return service, err
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestPodRoutesFallBackToUIDIndex(t *testing.T) {
+	// The pod has lost its uid label, but the index still knows it
+	h := newTestPodHandler(testPod("web-1", map[string]string{"app": "web"}))
+	if err := h.uidIndex.Record(h.k8sClient.Context, "Pod", "abc123", "default", "web-1"); err != nil {
+		t.Fatalf("Record() failed: %v", err)
+	}
+
+	routes := []struct {
+		name    string
+		handler gin.HandlerFunc
+		route   string
+	}{
+		{"get", h.GetPodByUID, "/pods/:uid"},
+		{"restarts", h.GetPodRestarts, "/pods/:uid/restarts"},
+		{"scheduling", h.GetPodScheduling, "/pods/:uid/scheduling"},
+		{"events", h.GetPodEvents, "/pods/:uid/events"},
+		{"logs", h.GetPodLogs, "/pods/:uid/logs"},
+		{"export", h.ExportPod, "/pods/:uid/export"},
+	}
+	for _, tt := range routes {
+		t.Run(tt.name, func(t *testing.T) {
+			target := strings.Replace(tt.route, ":uid", "abc123", 1)
+			rec := serve(tt.handler, http.MethodGet, tt.route, target, "")
+			if rec.Code != http.StatusOK {
+				t.Errorf("GET %s = %d, want 200 with the pod resolved through the index: %s", target, rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestPodByUIDPrefersLabel(t *testing.T) {
+	h := newTestPodHandler(
+		testPod("labelled", map[string]string{"uid": "abc123"}),
+		testPod("stale", nil),
+	)
+	// A stale index entry must not win over the label
+	if err := h.uidIndex.Record(h.k8sClient.Context, "Pod", "abc123", "default", "stale"); err != nil {
+		t.Fatalf("Record() failed: %v", err)
+	}
+
+	pod, err := podByUID(h.k8sClient, h.uidIndex, "default", "abc123")
+	if err != nil {
+		t.Fatalf("podByUID() failed: %v", err)
+	}
+	if pod == nil || pod.Name != "labelled" {
+		t.Errorf("podByUID() = %v, want the labelled pod", pod)
+	}
+
+	pod, err = podByUID(h.k8sClient, h.uidIndex, "default", "missing")
+	if err != nil || pod != nil {
+		t.Errorf("podByUID(missing) = %v, %v, want nil, nil", pod, err)
+	}
+}
+
+func TestPodByUIDSkipsTerminatingAndPrefersNewest(t *testing.T) {
+	now := time.Now()
+	pod := func(name string, age time.Duration, terminating bool) *corev1.Pod {
+		p := testPod(name, map[string]string{"uid": "abc123"})
+		p.CreationTimestamp = metav1.NewTime(now.Add(-age))
+		if terminating {
+			p.DeletionTimestamp = &metav1.Time{Time: now}
+		}
+		return p
+	}
+
+	tests := []struct {
+		name string
+		pods []runtime.Object
+		want string
+	}{
+		{"newest wins", []runtime.Object{pod("old", time.Hour, false), pod("new", time.Minute, false), pod("mid", 10*time.Minute, false)}, "new"},
+		{"terminating skipped", []runtime.Object{pod("live", time.Hour, false), pod("leaving", time.Minute, true)}, "live"},
+		{"only terminating", []runtime.Object{pod("leaving", time.Minute, true)}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestPodHandler(tt.pods...)
+			got, err := podByUID(h.k8sClient, h.uidIndex, "default", "abc123")
+			if err != nil {
+				t.Fatalf("podByUID() failed: %v", err)
+			}
+			if name := podName(got); name != tt.want {
+				t.Errorf("podByUID() = %q, want %q", name, tt.want)
+			}
+		})
+	}
+
+	// A terminating pod is not resolved through the index either
+	h := newTestPodHandler(pod("leaving", time.Minute, true))
+	leaving, _ := h.k8sClient.ClientSet.CoreV1().Pods("default").Get(h.k8sClient.Context, "leaving", metav1.GetOptions{})
+	leaving.Labels = nil
+	if _, err := h.k8sClient.ClientSet.CoreV1().Pods("default").Update(h.k8sClient.Context, leaving, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.uidIndex.Record(h.k8sClient.Context, "Pod", "abc123", "default", "leaving"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := podByUID(h.k8sClient, h.uidIndex, "default", "abc123"); err != nil || got != nil {
+		t.Errorf("podByUID() through the index = %q, %v, want nil", podName(got), err)
+	}
+}
+
+// podName returns the name of pod, or "" for nil.
+func podName(pod *corev1.Pod) string {
+	if pod == nil {
+		return ""
+	}
+	return pod.Name
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"kubernetes-api/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	volumeTypeEmptyDir = "emptyDir"
+	volumeTypeHostPath = "hostPath"
+)
+
+// validateVolumes checks the volumes declared by a create request and that
+// every container's mounts refer to one of them. Names beginning with
+// "configmap-" are reserved for config_map_refs mounts.
+func validateVolumes(req models.CreatePodRequest) error {
+	declared := make(map[string]bool)
+	for _, volume := range req.Volumes {
+		if errs := validation.IsDNS1123Label(volume.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid volume name %q: %s", volume.Name, strings.Join(errs, ", "))
+		}
+		if strings.HasPrefix(volume.Name, "configmap-") {
+			return fmt.Errorf("volume %q: the configmap- prefix is reserved", volume.Name)
+		}
+		if declared[volume.Name] {
+			return fmt.Errorf("duplicate volume name %q", volume.Name)
+		}
+		declared[volume.Name] = true
+
+		if _, err := volumeSource(volume); err != nil {
+			return fmt.Errorf("volume %q: %w", volume.Name, err)
+		}
+	}
+
+	mounts := map[string][]models.VolumeMountSpec{req.ContainerName: req.VolumeMounts}
+	if len(req.Containers) > 0 {
+		mounts = make(map[string][]models.VolumeMountSpec)
+		for _, container := range req.Containers {
+			mounts[container.Name] = container.VolumeMounts
+		}
+	}
+	for container, containerMounts := range mounts {
+		paths := make(map[string]bool)
+		for _, mount := range containerMounts {
+			if !declared[mount.Name] {
+				return fmt.Errorf("container %q: volume mount %q does not match a declared volume", container, mount.Name)
+			}
+			if !path.IsAbs(mount.MountPath) {
+				return fmt.Errorf("container %q: mount_path %q must be absolute", container, mount.MountPath)
+			}
+			if paths[mount.MountPath] {
+				return fmt.Errorf("container %q: mount_path %q is used more than once", container, mount.MountPath)
+			}
+			paths[mount.MountPath] = true
+		}
+	}
+	return nil
+}
+
+// volumeSource converts spec into the matching Kubernetes volume source.
+func volumeSource(spec models.VolumeSpec) (corev1.VolumeSource, error) {
+	switch spec.Type {
+	case volumeTypeEmptyDir:
+		if spec.Path != "" {
+			return corev1.VolumeSource{}, fmt.Errorf("path only applies to hostPath volumes")
+		}
+		emptyDir := &corev1.EmptyDirVolumeSource{}
+		switch corev1.StorageMedium(spec.Medium) {
+		case corev1.StorageMediumDefault, corev1.StorageMediumMemory:
+			emptyDir.Medium = corev1.StorageMedium(spec.Medium)
+		default:
+			return corev1.VolumeSource{}, fmt.Errorf("invalid medium %q: must be empty or Memory", spec.Medium)
+		}
+		if spec.SizeLimit != "" {
+			limit, err := resource.ParseQuantity(spec.SizeLimit)
+			if err != nil {
+				return corev1.VolumeSource{}, fmt.Errorf("invalid size_limit %q: %w", spec.SizeLimit, err)
+			}
+			emptyDir.SizeLimit = &limit
+		}
+		return corev1.VolumeSource{EmptyDir: emptyDir}, nil
+
+	case volumeTypeHostPath:
+		if !path.IsAbs(spec.Path) {
+			return corev1.VolumeSource{}, fmt.Errorf("hostPath volumes need an absolute path, got %q", spec.Path)
+		}
+		if spec.Medium != "" || spec.SizeLimit != "" {
+			return corev1.VolumeSource{}, fmt.Errorf("medium and size_limit only apply to emptyDir volumes")
+		}
+		return corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: spec.Path}}, nil
+	}
+	return corev1.VolumeSource{}, fmt.Errorf("invalid type %q: must be emptyDir or hostPath", spec.Type)
+}
+
+// podVolumes builds the pod volumes declared in req.
+func podVolumes(req models.CreatePodRequest) ([]corev1.Volume, error) {
+	var volumes []corev1.Volume
+	for _, spec := range req.Volumes {
+		source, err := volumeSource(spec)
+		if err != nil {
+			return nil, fmt.Errorf("volume %q: %w", spec.Name, err)
+		}
+		volumes = append(volumes, corev1.Volume{Name: spec.Name, VolumeSource: source})
+	}
+	return volumes, nil
+}
+
+func volumeMounts(specs []models.VolumeMountSpec) []corev1.VolumeMount {
+	var mounts []corev1.VolumeMount
+	for _, spec := range specs {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      spec.Name,
+			MountPath: spec.MountPath,
+			ReadOnly:  spec.ReadOnly,
+		})
+	}
+	return mounts
+}
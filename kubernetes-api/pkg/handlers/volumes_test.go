@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestCreatePodSharedEmptyDir(t *testing.T) {
+	h := newTestPodHandler()
+
+	pod := createPod(t, h, `{"name":"web",
+		"volumes":[{"name":"shared","type":"emptyDir","medium":"Memory","size_limit":"64Mi"},{"name":"logs","type":"hostPath","path":"/var/log/web"}],
+		"containers":[
+			{"name":"app","image":"nginx","volume_mounts":[{"name":"shared","mount_path":"/usr/share/nginx/html"},{"name":"logs","mount_path":"/var/log/nginx"}]},
+			{"name":"sync","image":"git-sync","volume_mounts":[{"name":"shared","mount_path":"/data","read_only":true}]}]}`)
+
+	if len(pod.Spec.Volumes) != 2 {
+		t.Fatalf("pod volumes = %+v, want shared and logs", pod.Spec.Volumes)
+	}
+	shared, logs := pod.Spec.Volumes[0], pod.Spec.Volumes[1]
+	if shared.Name != "shared" || shared.EmptyDir == nil || shared.EmptyDir.Medium != corev1.StorageMediumMemory ||
+		shared.EmptyDir.SizeLimit == nil || shared.EmptyDir.SizeLimit.Cmp(resource.MustParse("64Mi")) != 0 {
+		t.Errorf("first volume = %+v, want a 64Mi in-memory emptyDir named shared", shared)
+	}
+	if logs.Name != "logs" || logs.HostPath == nil || logs.HostPath.Path != "/var/log/web" {
+		t.Errorf("second volume = %+v, want hostPath /var/log/web named logs", logs)
+	}
+
+	app, sync := pod.Spec.Containers[0].VolumeMounts, pod.Spec.Containers[1].VolumeMounts
+	if len(app) != 2 || app[0].Name != "shared" || app[0].MountPath != "/usr/share/nginx/html" || app[0].ReadOnly {
+		t.Errorf("app mounts = %+v, want shared writable at /usr/share/nginx/html", app)
+	}
+	if len(sync) != 1 || sync[0].Name != "shared" || sync[0].MountPath != "/data" || !sync[0].ReadOnly {
+		t.Errorf("sync mounts = %+v, want shared read-only at /data", sync)
+	}
+
+	// The single-container shorthand takes volume_mounts directly
+	pod = createPod(t, h, `{"name":"scratch","image":"busybox","container_name":"busybox",
+		"volumes":[{"name":"tmp","type":"emptyDir"}],"volume_mounts":[{"name":"tmp","mount_path":"/tmp"}]}`)
+	if mounts := pod.Spec.Containers[0].VolumeMounts; len(mounts) != 1 || mounts[0].Name != "tmp" || mounts[0].MountPath != "/tmp" {
+		t.Errorf("shorthand container mounts = %+v, want tmp at /tmp", mounts)
+	}
+}
+
+func TestCreatePodInvalidVolumes(t *testing.T) {
+	h := newTestPodHandler()
+
+	for _, volumes := range []string{
+		`"volumes":[{"name":"data","type":"emptyDir"}],"volume_mounts":[{"name":"other","mount_path":"/data"}]`,
+		`"volumes":[{"name":"data","type":"emptyDir"}],"volume_mounts":[{"name":"data","mount_path":"data"}]`,
+		`"volumes":[{"name":"data","type":"emptyDir"}],"volume_mounts":[{"name":"data","mount_path":"/data"},{"name":"data","mount_path":"/data"}]`,
+		`"volumes":[{"name":"data","type":"emptyDir"},{"name":"data","type":"emptyDir"}]`,
+		`"volumes":[{"name":"configmap-x","type":"emptyDir"}]`,
+		`"volumes":[{"name":"data","type":"nfs"}]`,
+		`"volumes":[{"name":"data","type":"hostPath","path":"relative"}]`,
+		`"volumes":[{"name":"data","type":"emptyDir","medium":"Disk"}]`,
+		`"volumes":[{"name":"data","type":"emptyDir","size_limit":"lots"}]`,
+		`"volumes":[{"name":"data","type":"hostPath","path":"/data","size_limit":"1Gi"}]`,
+	} {
+		body := `{"name":"web","image":"nginx","container_name":"nginx",` + volumes + `}`
+		if code := createPodStatus(h, body); code != http.StatusBadRequest {
+			t.Errorf("CreatePod with %s returned %d, want %d", volumes, code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package index
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// ConfigMapName is the ConfigMap holding the index.
+const ConfigMapName = "kubernetes-api-uid-index"
+
+// NamespaceEnv names the environment variable selecting the namespace the
+// index ConfigMap lives in. It defaults to DefaultNamespace.
+const NamespaceEnv = "UID_INDEX_NAMESPACE"
+
+const DefaultNamespace = "default"
+
+type Entry struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Index persists which object each generated UID was given to, so a resource
+// can still be found after its uid label is removed or the API restarts.
+// Entries are stored in a ConfigMap as "<kind>.<uid>" -> "<namespace>/<name>".
+type Index struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+func New(client kubernetes.Interface, namespace string) *Index {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	return &Index{client: client, namespace: namespace}
+}
+
+// NewFromEnv creates an index stored in the namespace named by NamespaceEnv.
+func NewFromEnv(client kubernetes.Interface) *Index {
+	return New(client, os.Getenv(NamespaceEnv))
+}
+
+// Record maps uid to the named object, replacing any previous entry.
+func (i *Index) Record(ctx context.Context, kind, uid, namespace, name string) error {
+	return i.update(ctx, func(data map[string]string) {
+		data[key(kind, uid)] = namespace + "/" + name
+	})
+}
+
+// Remove drops the entry for uid, if any.
+func (i *Index) Remove(ctx context.Context, kind, uid string) error {
+	return i.update(ctx, func(data map[string]string) {
+		delete(data, key(kind, uid))
+	})
+}
+
+// Lookup returns the object uid was recorded for and whether one was found.
+func (i *Index) Lookup(ctx context.Context, kind, uid string) (Entry, bool, error) {
+	configMap, err := i.client.CoreV1().ConfigMaps(i.namespace).Get(ctx, ConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, err
+	}
+
+	namespace, name, found := strings.Cut(configMap.Data[key(kind, uid)], "/")
+	if !found {
+		return Entry{}, false, nil
+	}
+	return Entry{Kind: kind, Namespace: namespace, Name: name}, true, nil
+}
+
+// update applies mutate to the index data, creating the ConfigMap on first use
+// and retrying when another writer updated it concurrently.
+func (i *Index) update(ctx context.Context, mutate func(map[string]string)) error {
+	configMaps := i.client.CoreV1().ConfigMaps(i.namespace)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := configMaps.Get(ctx, ConfigMapName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName},
+				Data:       map[string]string{},
+			}
+			mutate(configMap.Data)
+			_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// Lost the race to create it; retry as an update
+				return apierrors.NewConflict(corev1.Resource("configmaps"), ConfigMapName, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		mutate(configMap.Data)
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// key builds a ConfigMap data key. Generated UIDs are hex, so the result is a
+// valid key.
+func key(kind, uid string) string {
+	return strings.ToLower(kind) + "." + uid
+}
@@ -6,14 +6,19 @@ import (
 	"os"
 	"path/filepath"
 
+	"kubernetes-api/pkg/metrics"
+
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 type K8sClient struct {
-	ClientSet *kubernetes.Clientset
-	Context   context.Context
+	ClientSet     kubernetes.Interface
+	MetricsClient metricsclient.Interface // metrics.k8s.io, served by metrics-server
+	Config        *rest.Config            // for streaming connections such as port-forwards
+	Context       context.Context
 }
 
 func NewK8sClient() (*K8sClient, error) {
@@ -35,13 +40,23 @@ func NewK8sClient() (*K8sClient, error) {
 		}
 	}
 
+	// Count failed API calls for the /metrics endpoint
+	config.Wrap(metrics.WrapTransport)
+
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create clientset: %v", err)
 	}
 
+	metricsClientset, err := metricsclient.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics clientset: %v", err)
+	}
+
 	return &K8sClient{
-		ClientSet: clientset,
-		Context:   context.Background(),
+		ClientSet:     clientset,
+		MetricsClient: metricsClientset,
+		Config:        config,
+		Context:       context.Background(),
 	}, nil
 }
@@ -0,0 +1,26 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// LevelEnv names the environment variable setting the minimum log level:
+// debug, info (the default), warn or error.
+const LevelEnv = "LOG_LEVEL"
+
+// New returns a logger writing JSON records to stdout at the given level.
+// Unknown levels fall back to info.
+func New(level string) *slog.Logger {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(strings.ToUpper(level))); err != nil {
+		lvl = slog.LevelInfo
+	}
+	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: lvl}))
+}
+
+// NewFromEnv returns a logger at the level named by LevelEnv.
+func NewFromEnv() *slog.Logger {
+	return New(os.Getenv(LevelEnv))
+}
@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "kubernetes_api"
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests handled, by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	kubernetesErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kubernetes_client_errors_total",
+		Help:      "Failed calls to the Kubernetes API server, by operation, resource and status code (\"error\" when no response was received).",
+	}, []string{"operation", "resource", "code"})
+)
+
+var registry = prometheus.NewRegistry()
+
+// Register adds the API's collectors, plus the Go runtime and process
+// collectors, to the registry served by Handler. Call it once at startup.
+func Register() {
+	registry.MustRegister(
+		requestsTotal,
+		requestDuration,
+		kubernetesErrorsTotal,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the registered metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records the count and latency of each request. Requests that
+// match no route are grouped under "unmatched" to keep label cardinality
+// bounded.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		requestsTotal.WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status())).Inc()
+		requestDuration.WithLabelValues(route, c.Request.Method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// WrapTransport counts failed Kubernetes API calls made through rt. It is
+// meant for rest.Config.Wrap.
+func WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := rt.RoundTrip(req)
+		switch {
+		case err != nil:
+			operation, resource := describe(req)
+			kubernetesErrorsTotal.WithLabelValues(operation, resource, "error").Inc()
+		case resp.StatusCode >= http.StatusBadRequest:
+			operation, resource := describe(req)
+			kubernetesErrorsTotal.WithLabelValues(operation, resource, strconv.Itoa(resp.StatusCode)).Inc()
+		}
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// describe derives the operation (create, get, list, watch, update, patch,
+// delete) and resource of a Kubernetes API request from its method and path,
+// e.g. GET /api/v1/namespaces/default/pods is a list of pods.
+func describe(req *http.Request) (operation, resource string) {
+	path := strings.Trim(req.URL.Path, "/")
+	var parts []string
+	switch {
+	case strings.HasPrefix(path, "api/"):
+		parts = strings.Split(path, "/")[2:] // api/<version>/...
+	case strings.HasPrefix(path, "apis/"):
+		parts = strings.Split(path, "/")[3:] // apis/<group>/<version>/...
+	}
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		parts = parts[2:]
+	}
+
+	resource = "other"
+	if len(parts) > 0 && parts[0] != "" {
+		resource = parts[0]
+		if len(parts) > 2 {
+			resource += "/" + parts[2] // subresource, e.g. pods/log
+		}
+	}
+
+	switch req.Method {
+	case http.MethodPost:
+		operation = "create"
+	case http.MethodPut:
+		operation = "update"
+	case http.MethodPatch:
+		operation = "patch"
+	case http.MethodDelete:
+		operation = "delete"
+	default:
+		switch {
+		case req.URL.Query().Get("watch") == "true":
+			operation = "watch"
+		case len(parts) == 1:
+			operation = "list"
+		default:
+			operation = "get"
+		}
+	}
+	return operation, resource
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	Register()
+}
+
+// scrape returns the body served by Handler.
+func scrape(t *testing.T) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics returned %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+// wantSample fails the test unless the scraped metrics contain line.
+func wantSample(t *testing.T, metrics, line string) {
+	t.Helper()
+	if !strings.Contains(metrics, line+"\n") {
+		t.Errorf("/metrics is missing %q", line)
+	}
+}
+
+func TestMiddlewareCountsRequests(t *testing.T) {
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/api/v1/pods/:uid", func(c *gin.Context) {
+		if c.Param("uid") == "missing" {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/api/v1/pods/a", "/api/v1/pods/b", "/api/v1/pods/missing", "/nowhere"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	metrics := scrape(t)
+	wantSample(t, metrics, `kubernetes_api_http_requests_total{method="GET",route="/api/v1/pods/:uid",status="200"} 2`)
+	wantSample(t, metrics, `kubernetes_api_http_requests_total{method="GET",route="/api/v1/pods/:uid",status="404"} 1`)
+	wantSample(t, metrics, `kubernetes_api_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	wantSample(t, metrics, `kubernetes_api_http_request_duration_seconds_count{method="GET",route="/api/v1/pods/:uid"} 3`)
+}
+
+func TestWrapTransportCountsErrors(t *testing.T) {
+	transport := WrapTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Path {
+		case "/api/v1/namespaces/default/pods/web":
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+		case "/apis/apps/v1/namespaces/default/deployments":
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodDelete, "http://cluster/api/v1/namespaces/default/pods/web", nil),
+		httptest.NewRequest(http.MethodPost, "http://cluster/apis/apps/v1/namespaces/default/deployments", nil),
+		httptest.NewRequest(http.MethodGet, "http://cluster/api/v1/namespaces/default/pods", nil),
+	} {
+		transport.RoundTrip(req)
+	}
+
+	metrics := scrape(t)
+	wantSample(t, metrics, `kubernetes_api_kubernetes_client_errors_total{code="404",operation="delete",resource="pods"} 1`)
+	wantSample(t, metrics, `kubernetes_api_kubernetes_client_errors_total{code="error",operation="create",resource="deployments"} 1`)
+	if strings.Contains(metrics, `operation="list"`) {
+		t.Error("a successful list was counted as an error")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		method, url         string
+		operation, resource string
+	}{
+		{http.MethodGet, "/api/v1/namespaces/default/pods", "list", "pods"},
+		{http.MethodGet, "/api/v1/namespaces/default/pods/web", "get", "pods"},
+		{http.MethodGet, "/api/v1/namespaces/default/pods/web/log", "get", "pods/log"},
+		{http.MethodGet, "/api/v1/pods?watch=true", "watch", "pods"},
+		{http.MethodGet, "/api/v1/nodes", "list", "nodes"},
+		{http.MethodPut, "/apis/apps/v1/namespaces/default/deployments/web/scale", "update", "deployments/scale"},
+		{http.MethodPatch, "/api/v1/namespaces/default/services/web", "patch", "services"},
+		{http.MethodGet, "/version", "get", "other"},
+	}
+
+	for _, tt := range tests {
+		operation, resource := describe(httptest.NewRequest(tt.method, tt.url, nil))
+		if operation != tt.operation || resource != tt.resource {
+			t.Errorf("describe(%s %s) = %s, %s; want %s, %s", tt.method, tt.url, operation, resource, tt.operation, tt.resource)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenEnv and TokenFileEnv name the environment variables holding the API
+// bearer token, directly or as the path of a file containing it. TokenEnv
+// wins when both are set.
+const (
+	TokenEnv     = "API_TOKEN"
+	TokenFileEnv = "API_TOKEN_FILE"
+)
+
+// tokenHashKey is the context key under which BearerAuth stores the SHA-256
+// hash of a validated token, so later middleware can tell clients apart
+// without holding the token itself.
+const tokenHashKey = "middleware.tokenHash"
+
+// LoadToken returns the configured bearer token, or "" when authentication is
+// disabled.
+func LoadToken() (string, error) {
+	if token := os.Getenv(TokenEnv); token != "" {
+		return token, nil
+	}
+
+	path := os.Getenv(TokenFileEnv)
+	if path == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", TokenFileEnv, err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("%s %q is empty", TokenFileEnv, path)
+	}
+	return token, nil
+}
+
+// BearerAuth rejects requests without an "Authorization: Bearer <token>"
+// header matching token, except for the paths in exempt. An empty token
+// disables the check. Accepted tokens are recorded for the rate limiter.
+func BearerAuth(token string, exempt ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" || c.Request.Method == http.MethodOptions || slices.Contains(exempt, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="kubernetes-api"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.APIResponse{
+				Success: false,
+				Error:   "Missing or invalid bearer token",
+			})
+			return
+		}
+
+		sum := sha256.Sum256([]byte(provided))
+		c.Set(tokenHashKey, hex.EncodeToString(sum[:]))
+		c.Next()
+	}
+}
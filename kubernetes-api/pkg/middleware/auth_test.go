@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authRouter returns a router guarded by BearerAuth with /health exempt.
+func authRouter(token string) *gin.Engine {
+	r := gin.New()
+	r.Use(BearerAuth(token, "/health"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/health", ok)
+	r.GET("/api/v1/pods", ok)
+	return r
+}
+
+func TestBearerAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		path          string
+		authorization string
+		want          int
+	}{
+		{"authorized", "secret", "/api/v1/pods", "Bearer secret", http.StatusOK},
+		{"wrong token", "secret", "/api/v1/pods", "Bearer wrong", http.StatusUnauthorized},
+		{"wrong scheme", "secret", "/api/v1/pods", "Basic secret", http.StatusUnauthorized},
+		{"missing header", "secret", "/api/v1/pods", "", http.StatusUnauthorized},
+		{"health exempt", "secret", "/health", "", http.StatusOK},
+		{"disabled", "", "/api/v1/pods", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			authRouter(tt.token).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("GET %s with %q returned %d, want %d", tt.path, tt.authorization, rec.Code, tt.want)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 response has no WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+func TestLoadToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(TokenEnv, "")
+	t.Setenv(TokenFileEnv, path)
+	if token, err := LoadToken(); err != nil || token != "from-file" {
+		t.Errorf("LoadToken() from a file = %q, %v; want %q", token, err, "from-file")
+	}
+
+	t.Setenv(TokenEnv, "from-env")
+	if token, err := LoadToken(); err != nil || token != "from-env" {
+		t.Errorf("LoadToken() with both set = %q, %v; want %q", token, err, "from-env")
+	}
+
+	t.Setenv(TokenEnv, "")
+	t.Setenv(TokenFileEnv, "")
+	if token, err := LoadToken(); err != nil || token != "" {
+		t.Errorf("LoadToken() with nothing set = %q, %v; want authentication disabled", token, err)
+	}
+}
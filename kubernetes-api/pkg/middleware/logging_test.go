@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// logRequest serves req through RequestLogger and returns the response and the
+// decoded log record.
+func logRequest(t *testing.T, req *http.Request) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	r := gin.New()
+	r.Use(RequestLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	r.GET("/pods/:uid", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("log output %q is not a JSON record: %v", buf.String(), err)
+	}
+	return rec, record
+}
+
+func TestRequestLoggerRecord(t *testing.T) {
+	rec, record := logRequest(t, httptest.NewRequest(http.MethodGet, "/pods/abc123", nil))
+
+	want := map[string]any{
+		"msg":    "request",
+		"level":  "WARN",
+		"method": "GET",
+		"path":   "/pods/abc123",
+		"route":  "/pods/:uid",
+		"status": float64(http.StatusNotFound),
+		"uid":    "abc123",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("record[%q] = %v, want %v", key, record[key], value)
+		}
+	}
+	if _, ok := record["latency"]; !ok {
+		t.Error("record has no latency")
+	}
+
+	requestID := rec.Header().Get(RequestIDHeader)
+	if requestID == "" {
+		t.Fatalf("response has no %s header", RequestIDHeader)
+	}
+	if record["request_id"] != requestID {
+		t.Errorf("record request_id = %v, want the %s header %q", record["request_id"], RequestIDHeader, requestID)
+	}
+}
+
+func TestRequestLoggerKeepsClientRequestID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/pods/abc123", nil)
+	req.Header.Set(RequestIDHeader, "client-id")
+
+	rec, record := logRequest(t, req)
+	if got := rec.Header().Get(RequestIDHeader); got != "client-id" {
+		t.Errorf("%s header = %q, want the client's ID", RequestIDHeader, got)
+	}
+	if record["request_id"] != "client-id" {
+		t.Errorf("record request_id = %v, want the client's ID", record["request_id"])
+	}
+}
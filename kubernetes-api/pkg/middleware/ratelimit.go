@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"kubernetes-api/pkg/models"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// RateEnv and BurstEnv name the environment variables setting the sustained
+// requests per second and the burst size allowed per client. A rate of 0
+// disables rate limiting.
+const (
+	RateEnv  = "RATE_LIMIT_RPS"
+	BurstEnv = "RATE_LIMIT_BURST"
+)
+
+const (
+	DefaultRate  = 10
+	DefaultBurst = 20
+)
+
+// clientTTL is how long an idle client's bucket is kept, and maxClients caps
+// how many are tracked at once.
+const (
+	clientTTL  = 10 * time.Minute
+	maxClients = 10000
+)
+
+type client struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter keeps a token bucket per client: the bearer token once
+// BearerAuth has validated it, the client IP otherwise. It must run after
+// BearerAuth, or every client is keyed by IP.
+type RateLimiter struct {
+	rate  rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	clients   map[string]*client
+	lastSweep time.Time
+}
+
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst <= 0 {
+		burst = DefaultBurst
+	}
+	return &RateLimiter{
+		rate:      rate.Limit(rps),
+		burst:     burst,
+		clients:   make(map[string]*client),
+		lastSweep: time.Now(),
+	}
+}
+
+// NewRateLimiterFromEnv reads RateEnv and BurstEnv, falling back to
+// DefaultRate and DefaultBurst when they are unset or invalid.
+func NewRateLimiterFromEnv() *RateLimiter {
+	rps, err := strconv.ParseFloat(os.Getenv(RateEnv), 64)
+	if err != nil || rps < 0 {
+		rps = DefaultRate
+	}
+	burst, _ := strconv.Atoi(os.Getenv(BurstEnv))
+	return NewRateLimiter(rps, burst)
+}
+
+// Enabled reports whether requests are limited at all.
+func (l *RateLimiter) Enabled() bool {
+	return l.rate > 0
+}
+
+// Middleware rejects requests over the client's limit with 429 and a
+// Retry-After header, except for the paths in exempt.
+func (l *RateLimiter) Middleware(exempt ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.Enabled() || slices.Contains(exempt, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		reservation := l.limiter(clientKey(c)).Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			reservation.Cancel()
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, models.APIResponse{
+				Success: false,
+				Error:   "Rate limit exceeded, retry later",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// clientKey identifies the client of c. Unvalidated tokens are ignored, so a
+// client cannot dodge its limit, or grow the client map, by inventing tokens.
+func clientKey(c *gin.Context) string {
+	if hash := c.GetString(tokenHashKey); hash != "" {
+		return "token:" + hash
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func (l *RateLimiter) limiter(key string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > clientTTL {
+		l.evictIdle(now)
+	}
+
+	entry, ok := l.clients[key]
+	if !ok {
+		if len(l.clients) >= maxClients {
+			l.evictIdle(now)
+		}
+		if len(l.clients) >= maxClients {
+			l.evictOldest()
+		}
+		entry = &client{limiter: rate.NewLimiter(l.rate, l.burst)}
+		l.clients[key] = entry
+	}
+	entry.lastSeen = now
+	return entry.limiter
+}
+
+func (l *RateLimiter) evictIdle(now time.Time) {
+	for key, entry := range l.clients {
+		if now.Sub(entry.lastSeen) > clientTTL {
+			delete(l.clients, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+func (l *RateLimiter) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range l.clients {
+		if oldestKey == "" || entry.lastSeen.Before(oldest) {
+			oldestKey, oldest = key, entry.lastSeen
+		}
+	}
+	delete(l.clients, oldestKey)
+}
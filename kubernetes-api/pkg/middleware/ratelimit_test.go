@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// limitedRouter returns a router guarded by BearerAuth with token, when set,
+// and then by limiter, with /health exempt from both.
+func limitedRouter(limiter *RateLimiter, token string) *gin.Engine {
+	r := gin.New()
+	r.Use(BearerAuth(token, "/health"))
+	r.Use(limiter.Middleware("/health"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/health", ok)
+	r.GET("/api/v1/pods", ok)
+	return r
+}
+
+// get sends a GET for path from remoteAddr with an optional bearer token.
+func get(r *gin.Engine, path, remoteAddr, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimiterRejectsPastBurst(t *testing.T) {
+	r := limitedRouter(NewRateLimiter(1, 3), "")
+
+	for i := 0; i < 3; i++ {
+		if rec := get(r, "/api/v1/pods", "10.0.0.1:1234", ""); rec.Code != http.StatusOK {
+			t.Fatalf("request %d within the burst returned %d", i+1, rec.Code)
+		}
+	}
+
+	rec := get(r, "/api/v1/pods", "10.0.0.1:1234", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request past the burst returned %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 {
+		t.Errorf("Retry-After = %q, want a positive number of seconds", rec.Header().Get("Retry-After"))
+	}
+
+	if rec := get(r, "/health", "10.0.0.1:1234", ""); rec.Code != http.StatusOK {
+		t.Errorf("/health past the limit returned %d, want it exempt", rec.Code)
+	}
+}
+
+func TestRateLimiterKeysByClient(t *testing.T) {
+	r := limitedRouter(NewRateLimiter(1, 1), "")
+
+	get(r, "/api/v1/pods", "10.0.0.1:1234", "")
+	if rec := get(r, "/api/v1/pods", "10.0.0.1:1234", ""); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request from one IP returned %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if rec := get(r, "/api/v1/pods", "10.0.0.2:1234", ""); rec.Code != http.StatusOK {
+		t.Errorf("request from another IP returned %d, want its own bucket", rec.Code)
+	}
+
+	// Without authentication a token is not validated, so it earns no bucket
+	if rec := get(r, "/api/v1/pods", "10.0.0.1:1234", "made-up"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("request with an unvalidated token returned %d, want the IP's bucket", rec.Code)
+	}
+}
+
+func TestRateLimiterKeysByValidatedToken(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	r := limitedRouter(l, "secret")
+
+	// A valid token gets its own bucket, whichever address it comes from
+	if rec := get(r, "/api/v1/pods", "10.0.0.1:1234", "secret"); rec.Code != http.StatusOK {
+		t.Fatalf("first request with the token returned %d", rec.Code)
+	}
+	if rec := get(r, "/api/v1/pods", "10.0.0.3:1234", "secret"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("second request with the token returned %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+
+	// Invalid tokens are turned away before they reach the limiter
+	for i := 0; i < 5; i++ {
+		if rec := get(r, "/api/v1/pods", "10.0.0.1:1234", "guess-"+strconv.Itoa(i)); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("request with an invalid token returned %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	}
+
+	if len(l.clients) != 1 {
+		t.Fatalf("limiter tracks %d clients, want 1", len(l.clients))
+	}
+	for key := range l.clients {
+		if strings.Contains(key, "secret") {
+			t.Errorf("client key %q holds the raw token", key)
+		}
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	r := limitedRouter(NewRateLimiter(0, 1), "")
+	for i := 0; i < 10; i++ {
+		if rec := get(r, "/api/v1/pods", "10.0.0.1:1234", ""); rec.Code != http.StatusOK {
+			t.Fatalf("request %d with rate limiting disabled returned %d", i+1, rec.Code)
+		}
+	}
+}
+
+func TestRateLimiterEvictsClients(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+
+	l.limiter("idle")
+	l.clients["idle"].lastSeen = time.Now().Add(-2 * clientTTL)
+	l.lastSweep = time.Now().Add(-2 * clientTTL)
+	l.limiter("active")
+	if _, ok := l.clients["idle"]; ok {
+		t.Error("idle client was not evicted")
+	}
+
+	for i := 0; i < maxClients+10; i++ {
+		l.limiter(strconv.Itoa(i))
+	}
+	if len(l.clients) > maxClients {
+		t.Errorf("limiter tracks %d clients, want at most %d", len(l.clients), maxClients)
+	}
+}
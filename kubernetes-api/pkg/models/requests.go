@@ -1,20 +1,137 @@
 package models
 
+import "k8s.io/apimachinery/pkg/util/intstr"
+
 type CreatePodRequest struct {
-	Name          string            `json:"name"`
-	Image         string            `json:"image"`
-	ContainerName string            `json:"container_name"`
-	Port          int32             `json:"port,omitempty"`
-	Labels        map[string]string `json:"labels,omitempty"`
-	Env           map[string]string `json:"env,omitempty"`
+	Name             string              `json:"name"`
+	Image            string              `json:"image"`
+	ContainerName    string              `json:"container_name"`
+	Command          []string            `json:"command,omitempty"` // overrides the image entrypoint
+	Args             []string            `json:"args,omitempty"`
+	Port             int32               `json:"port,omitempty"`
+	Ports            []ContainerPortSpec `json:"ports,omitempty"`
+	Labels           map[string]string   `json:"labels,omitempty"`
+	Annotations      map[string]string   `json:"annotations,omitempty"`
+	Env              map[string]string   `json:"env,omitempty"`        // applies to every container
+	Namespace        string              `json:"namespace,omitempty"`  // defaults to "default"
+	Containers       []ContainerSpec     `json:"containers,omitempty"` // replaces image/container_name/port when set
+	Resources        *ResourceSpec       `json:"resources,omitempty"`
+	ImagePullPolicy  string              `json:"image_pull_policy,omitempty"`  // Always, IfNotPresent, Never
+	ImagePullSecrets []string            `json:"image_pull_secrets,omitempty"` // names of registry secrets in the pod's namespace
+	RestartPolicy    string              `json:"restart_policy,omitempty"`     // Always (default), OnFailure, Never
+	ConfigMapRefs    []ConfigMapRef      `json:"config_map_refs,omitempty"`    // checked to exist at create time
+	SecretEnv        []SecretEnvVar      `json:"secret_env,omitempty"`         // applies to every container
+	NodeSelector     map[string]string   `json:"node_selector,omitempty"`
+	NodeAffinity     []NodeRequirement   `json:"required_node_affinity,omitempty"` // all must match
+	Volumes          []VolumeSpec        `json:"volumes,omitempty"`
+	VolumeMounts     []VolumeMountSpec   `json:"volume_mounts,omitempty"` // single-container shorthand only
+}
+
+type VolumeSpec struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`                 // emptyDir, hostPath
+	Path      string `json:"path,omitempty"`       // hostPath only
+	Medium    string `json:"medium,omitempty"`     // emptyDir only: "" or Memory
+	SizeLimit string `json:"size_limit,omitempty"` // emptyDir only, e.g. 1Gi
+}
+
+type VolumeMountSpec struct {
+	Name      string `json:"name"` // a volume declared in volumes
+	MountPath string `json:"mount_path"`
+	ReadOnly  bool   `json:"read_only,omitempty"`
+}
+
+type NodeRequirement struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"` // In, NotIn, Exists, DoesNotExist, Gt, Lt
+	Values   []string `json:"values,omitempty"`
+}
+
+type SecretEnvVar struct {
+	Name      string `json:"name"`
+	SecretUID string `json:"secret_uid"`
+	Key       string `json:"key"`
+}
+
+type CreateSecretRequest struct {
+	Name      string            `json:"name"`
+	Data      map[string]string `json:"data"`                // plain values, stored base64-encoded
+	Namespace string            `json:"namespace,omitempty"` // defaults to "default"
+}
+
+type ConfigMapRef struct {
+	UID       string `json:"uid"`
+	MountPath string `json:"mount_path,omitempty"` // mounts each key as a file in this directory
+	EnvFrom   bool   `json:"env_from,omitempty"`   // exposes each key as an environment variable
+}
+
+type CreateConfigMapRequest struct {
+	Name      string            `json:"name"`
+	Data      map[string]string `json:"data"`
+	Namespace string            `json:"namespace,omitempty"` // defaults to "default"
+}
+
+type ResourceSpec struct {
+	Requests map[string]string `json:"requests,omitempty"` // cpu, memory
+	Limits   map[string]string `json:"limits,omitempty"`
+}
+
+type ContainerSpec struct {
+	Name            string              `json:"name"`
+	Image           string              `json:"image"`
+	Port            int32               `json:"port,omitempty"`
+	Ports           []ContainerPortSpec `json:"ports,omitempty"`
+	Env             map[string]string   `json:"env,omitempty"`
+	Command         []string            `json:"command,omitempty"`
+	Args            []string            `json:"args,omitempty"`
+	Resources       *ResourceSpec       `json:"resources,omitempty"`
+	ImagePullPolicy string              `json:"image_pull_policy,omitempty"`
+	VolumeMounts    []VolumeMountSpec   `json:"volume_mounts,omitempty"`
+}
+
+type UpdatePodLabelsRequest struct {
+	Set    map[string]string `json:"set,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
+type ExecRequest struct {
+	Container string   `json:"container,omitempty"` // defaults to the only container
+	Command   []string `json:"command"`             // run directly, not through a shell
+}
+
+type BatchCreatePodsRequest struct {
+	Pods []CreatePodRequest `json:"pods"`
+}
+
+type ReconcilePodsRequest struct {
+	Pods   []CreatePodRequest `json:"pods"`
+	DryRun bool               `json:"dry_run,omitempty"`
+}
+
+type ServicePortSpec struct {
+	Name       string             `json:"name,omitempty"`
+	Port       int32              `json:"port"`
+	TargetPort intstr.IntOrString `json:"target_port,omitempty"` // port number or named container port
+	Protocol   string             `json:"protocol,omitempty"`    // TCP, UDP, SCTP
+	NodePort   int32              `json:"node_port,omitempty"`   // NodePort and LoadBalancer services only
+}
+
+type ContainerPortSpec struct {
+	Name          string `json:"name,omitempty"` // lets services target the port by name
+	ContainerPort int32  `json:"container_port"`
+	Protocol      string `json:"protocol,omitempty"` // TCP, UDP, SCTP
 }
 
 type CreateServiceRequest struct {
-	Name        string `json:"name"`
-	PodUID      string `json:"pod_uid"`
-	Port        int32  `json:"port"`
-	TargetPort  int32  `json:"target_port"`
-	ServiceType string `json:"service_type,omitempty"`
+	Name         string             `json:"name"`
+	PodUID       string             `json:"pod_uid"`
+	Annotations  map[string]string  `json:"annotations,omitempty"`
+	Port         int32              `json:"port,omitempty"`
+	TargetPort   intstr.IntOrString `json:"target_port,omitempty"` // port number or named container port
+	Ports        []ServicePortSpec  `json:"ports,omitempty"`       // replaces port/target_port when set
+	ServiceType  string             `json:"service_type,omitempty"`
+	ExternalName string             `json:"external_name,omitempty"` // DNS name, required for ExternalName services
+	Namespace    string             `json:"namespace,omitempty"`     // defaults to "default"
 }
 
 type CreateDeploymentRequest struct {
@@ -24,6 +141,16 @@ type CreateDeploymentRequest struct {
 	Replicas      int32             `json:"replicas"`
 	Port          int32             `json:"port,omitempty"`
 	Labels        map[string]string `json:"labels,omitempty"`
+	Namespace     string            `json:"namespace,omitempty"` // defaults to "default"
+}
+
+type ScaleDeploymentRequest struct {
+	Replicas *int32 `json:"replicas"`
+}
+
+type UpdatePodEnvRequest struct {
+	Env       map[string]string `json:"env"`
+	Container string            `json:"container,omitempty"`
 }
 
 type PodOperationRequest struct {
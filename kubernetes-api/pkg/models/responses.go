@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
 
 type APIResponse struct {
 	Success bool        `json:"success"`
@@ -16,23 +20,268 @@ type PodResponse struct {
 	Status       string            `json:"status"`
 	Image        string            `json:"image"`
 	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
 	CreatedAt    time.Time         `json:"created_at"`
 	RestartCount int32             `json:"restart_count"`
 	HostIP       string            `json:"host_ip"`
 	PodIP        string            `json:"pod_ip"`
+	Conditions   []PodCondition    `json:"conditions,omitempty"`
+	Containers   []ContainerStatus `json:"containers,omitempty"`
+}
+
+type PodCondition struct {
+	Type               string    `json:"type"` // PodScheduled, Initialized, ContainersReady, Ready
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"last_transition_time"`
+}
+
+type ContainerStatus struct {
+	Name                  string `json:"name"`
+	Ready                 bool   `json:"ready"`
+	RestartCount          int32  `json:"restart_count"`
+	State                 string `json:"state"`            // waiting, running, terminated
+	Reason                string `json:"reason,omitempty"` // e.g. CrashLoopBackOff, ImagePullBackOff
+	Message               string `json:"message,omitempty"`
+	LastTerminationReason string `json:"last_termination_reason,omitempty"`
 }
 
 type ServiceResponse struct {
-	UID         string `json:"uid"`
-	Name        string `json:"name"`
-	Namespace   string `json:"namespace"`
-	ServiceType string `json:"service_type"`
-	ClusterIP   string `json:"cluster_ip"`
-	Port        int32  `json:"port"`
-	TargetPort  int32  `json:"target_port"`
+	UID          string             `json:"uid"`
+	Name         string             `json:"name"`
+	Namespace    string             `json:"namespace"`
+	Annotations  map[string]string  `json:"annotations,omitempty"`
+	ServiceType  string             `json:"service_type"`
+	ClusterIP    string             `json:"cluster_ip"`
+	ExternalName string             `json:"external_name,omitempty"`
+	Port         int32              `json:"port"`
+	TargetPort   intstr.IntOrString `json:"target_port"`
+	Ports        []ServicePortSpec  `json:"ports"`
+	Ingress      []ServiceIngress   `json:"load_balancer_ingress,omitempty"` // LoadBalancer services, once assigned
+}
+
+type ServiceIngress struct {
+	IP       string `json:"ip,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+}
+
+type DeploymentResponse struct {
+	UID               string            `json:"uid"`
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	Image             string            `json:"image"`
+	Labels            map[string]string `json:"labels"`
+	Replicas          int32             `json:"replicas"` // desired
+	ReadyReplicas     int32             `json:"ready_replicas"`
+	AvailableReplicas int32             `json:"available_replicas"`
+	CreatedAt         time.Time         `json:"created_at"`
+}
+
+type ConfigMapResponse struct {
+	UID       string            `json:"uid"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Data      map[string]string `json:"data"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type SecretResponse struct {
+	UID       string    `json:"uid"`
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Type      string    `json:"type"`
+	Keys      []string  `json:"keys"` // values are never returned
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type NodeResponse struct {
+	Name              string          `json:"name"`
+	Ready             bool            `json:"ready"`
+	Schedulable       bool            `json:"schedulable"` // false when cordoned
+	KubeletVersion    string          `json:"kubelet_version"`
+	InternalIP        string          `json:"internal_ip,omitempty"`
+	AllocatableCPU    string          `json:"allocatable_cpu"`
+	AllocatableMemory string          `json:"allocatable_memory"`
+	AllocatablePods   int64           `json:"allocatable_pods"`
+	Conditions        []NodeCondition `json:"conditions"`
+	CreatedAt         time.Time       `json:"created_at"`
+}
+
+type NodeCondition struct {
+	Type    string `json:"type"` // Ready, MemoryPressure, DiskPressure, PIDPressure, NetworkUnavailable
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type ContainerRestartInfo struct {
+	Name                   string     `json:"name"`
+	RestartCount           int32      `json:"restart_count"`
+	LastTerminationReason  string     `json:"last_termination_reason,omitempty"`
+	LastTerminationMessage string     `json:"last_termination_message,omitempty"`
+	LastExitCode           *int32     `json:"last_exit_code,omitempty"`
+	LastTerminatedAt       *time.Time `json:"last_terminated_at,omitempty"`
+}
+
+type PodRestartsResponse struct {
+	UID        string                 `json:"uid"`
+	Name       string                 `json:"name"`
+	Containers []ContainerRestartInfo `json:"containers"`
+}
+
+type SchedulingEvent struct {
+	Reason        string    `json:"reason"`
+	Message       string    `json:"message"`
+	Count         int32     `json:"count"`
+	LastTimestamp time.Time `json:"last_timestamp"`
+}
+
+type PodSchedulingResponse struct {
+	UID       string            `json:"uid"`
+	Name      string            `json:"name"`
+	Status    string            `json:"status"`
+	Scheduled bool              `json:"scheduled"`
+	NodeName  string            `json:"node_name,omitempty"`
+	Reason    string            `json:"reason,omitempty"`
+	Message   string            `json:"message,omitempty"`
+	Causes    []string          `json:"causes"`
+	Events    []SchedulingEvent `json:"events"`
+}
+
+type PodEvent struct {
+	Type          string    `json:"type"` // Normal or Warning
+	Reason        string    `json:"reason"`
+	Message       string    `json:"message"`
+	Count         int32     `json:"count"`
+	LastTimestamp time.Time `json:"last_timestamp"`
+}
+
+type PodEventsResponse struct {
+	UID    string     `json:"uid"`
+	Name   string     `json:"name"`
+	Events []PodEvent `json:"events"` // oldest first
+}
+
+type ContainerMetrics struct {
+	Name   string `json:"name"`
+	CPU    string `json:"cpu"`    // millicores, e.g. "12m"
+	Memory string `json:"memory"` // mebibytes, e.g. "34.5Mi"
+}
+
+type PodMetricsResponse struct {
+	UID         string             `json:"uid"`
+	Name        string             `json:"name"`
+	Timestamp   time.Time          `json:"timestamp"`
+	Window      string             `json:"window"` // sampling window, e.g. "15s"
+	TotalCPU    string             `json:"total_cpu"`
+	TotalMemory string             `json:"total_memory"`
+	Containers  []ContainerMetrics `json:"containers"`
+}
+
+type ReadyResponse struct {
+	ServerVersion string `json:"server_version"`
+}
+
+type ClusterCapabilitiesResponse struct {
+	ServerVersion string          `json:"server_version"`
+	APIVersions   []string        `json:"api_versions"`
+	Features      map[string]bool `json:"features"`
+	CachedAt      time.Time       `json:"cached_at"`
+}
+
+type PodLabelsResponse struct {
+	UID    string            `json:"uid"`
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels"`
+}
+
+type ExecResponse struct {
+	Container string   `json:"container"`
+	Command   []string `json:"command"`
+	Stdout    string   `json:"stdout"`
+	Stderr    string   `json:"stderr"`
+	ExitCode  int      `json:"exit_code"`
+	Truncated bool     `json:"truncated,omitempty"` // output beyond 1 MiB per stream was dropped
+}
+
+type PortForwardResponse struct {
+	UID          string    `json:"uid"`
+	PodName      string    `json:"pod_name"`
+	Namespace    string    `json:"namespace"`
+	PodPort      int       `json:"pod_port"`
+	LocalAddress string    `json:"local_address"` // on the host running this API
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type ApplyResponse struct {
+	Kind      string `json:"kind"`
+	UID       string `json:"uid"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// ApplyBundleResponse lists the objects created from a multi-document manifest.
+type ApplyBundleResponse struct {
+	Items []ApplyResponse `json:"items"`
+}
+
+type BatchResponse struct {
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"` // in request order
+}
+
+type BatchResult struct {
+	Index     int    `json:"index"`
+	Name      string `json:"name"`
+	Success   bool   `json:"success"`
+	UID       string `json:"uid,omitempty"`
+	PodName   string `json:"pod_name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ReconcileAction struct {
+	Name    string `json:"name"` // value of the pod's "app" label
+	UID     string `json:"uid"`
+	PodName string `json:"pod_name"`
+}
+
+type ReconcilePodsResponse struct {
+	DryRun    bool              `json:"dry_run"`
+	Created   []ReconcileAction `json:"created"`
+	Deleted   []ReconcileAction `json:"deleted"`
+	Unchanged []ReconcileAction `json:"unchanged"`
+	Errors    []string          `json:"errors,omitempty"`
+}
+
+type PodOperationResponse struct {
+	UID        string `json:"uid"`
+	Operation  string `json:"operation"`
+	PodName    string `json:"pod_name"`             // the new pod after a bare pod restarts
+	Deployment string `json:"deployment,omitempty"` // owning deployment, for stop/start
+	Replicas   *int32 `json:"replicas,omitempty"`   // deployment replicas after stop/start
+}
+
+type DeletePodResponse struct {
+	CascadedServices []string `json:"cascaded_services"`
+	CascadeErrors    []string `json:"cascade_errors,omitempty"`
+}
+
+type ServiceProbeResponse struct {
+	UID        string `json:"uid"`
+	Name       string `json:"name"`
+	Port       string `json:"port"`
+	Path       string `json:"path"`
+	Outcome    string `json:"outcome"` // ok, http_error, timeout, connection_refused, no_endpoints, error
+	StatusCode int    `json:"status_code,omitempty"`
+	LatencyMs  int64  `json:"latency_ms"`
+	Error      string `json:"error,omitempty"`
 }
 
 type ListResponse struct {
-	Items []interface{} `json:"items"`
-	Count int           `json:"count"`
+	Items    []interface{} `json:"items"`
+	Count    int           `json:"count"`              // items in this page
+	Continue string        `json:"continue,omitempty"` // token for the next page, if any
 }
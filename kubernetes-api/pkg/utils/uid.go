@@ -2,24 +2,99 @@ package utils
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"os"
+	"regexp"
 	"strings"
 )
 
+// DefaultUIDLength is the number of hex characters in a UID from GenerateUID.
+const DefaultUIDLength = 16
+
+// UIDFormatEnv names the environment variable choosing the format NewUID
+// generates: "hex" (the default, as GenerateUID) or "uuid" (as GenerateUUIDv4).
+const UIDFormatEnv = "UID_FORMAT"
+
+// GenerateUID returns DefaultUIDLength random hex characters.
 func GenerateUID() string {
-	bytes := make([]byte, 4)
+	return GenerateUIDWithLength(DefaultUIDLength)
+}
+
+// GenerateUIDWithLength returns n random hex characters, or DefaultUIDLength
+// of them when n is not positive.
+func GenerateUIDWithLength(n int) string {
+	if n <= 0 {
+		n = DefaultUIDLength
+	}
+	bytes := make([]byte, (n+1)/2)
 	rand.Read(bytes)
-	return fmt.Sprintf("%x", bytes)
+	return hex.EncodeToString(bytes)[:n]
+}
+
+// GenerateUUIDv4 returns a random RFC 4122 version 4 UUID in its canonical
+// form, e.g. "3f2b8c1e-9d4a-4e7b-a1c2-5d6e7f8a9b0c".
+func GenerateUUIDv4() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	bytes[6] = (bytes[6] & 0x0f) | 0x40 // version 4
+	bytes[8] = (bytes[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", bytes[0:4], bytes[4:6], bytes[6:8], bytes[8:10], bytes[10:16])
 }
 
+// NewUID generates a resource UID in the format selected by UIDFormatEnv.
+func NewUID() string {
+	if os.Getenv(UIDFormatEnv) == "uuid" {
+		return GenerateUUIDv4()
+	}
+	return GenerateUID()
+}
+
+// NamePrefixEnv names the environment variable holding a prefix (e.g. "team-a-")
+// added to every generated resource name.
+const NamePrefixEnv = "RESOURCE_NAME_PREFIX"
+
+// maxNameLength is the DNS-1123 label limit that applies to pod and service names.
+const maxNameLength = 63
+
+// GeneratePodName returns "<prefix><baseName>-<uid>". The prefix and base are
+// sanitized together and truncated as needed to stay within 63 characters; the
+// UID suffix is always kept.
 func GeneratePodName(baseName string) string {
 	uid := GenerateUID()
-	return fmt.Sprintf("%s-%s", baseName, uid)
+	suffix := "-" + uid
+
+	name := SanitizeName(os.Getenv(NamePrefixEnv) + baseName)
+	if len(name)+len(suffix) > maxNameLength {
+		name = strings.TrimRight(name[:maxNameLength-len(suffix)], "-")
+	}
+
+	return fmt.Sprintf("%s%s", name, suffix)
 }
 
+// invalidNameChars matches runs of characters that are not allowed in a
+// DNS-1123 label, including hyphens so that repeats collapse to one.
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// SanitizeName turns name into a valid DNS-1123 label that also starts with a
+// letter, as service names require: it is lowercased, every run of other
+// characters becomes a single hyphen, leading and trailing hyphens are
+// trimmed, and the result is cut to 63 characters. A name starting with a
+// digit gets an "r-" prefix, and a name with nothing usable left becomes
+// "resource".
 func SanitizeName(name string) string {
-	// Kubernetes names must be DNS-1123 compliant
-	name = strings.ToLower(name)
-	name = strings.ReplaceAll(name, "_", "-")
+	name = invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-")
+
+	if name == "" {
+		return "resource"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "r-" + name
+	}
+	if len(name) > maxNameLength {
+		name = strings.TrimRight(name[:maxNameLength], "-")
+	}
 	return name
 }
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var hexUID = regexp.MustCompile(`^[0-9a-f]+$`)
+
+func TestGenerateUIDWithLength(t *testing.T) {
+	for _, n := range []int{-1, 0, 1, 7, 8, 16, 31, 32} {
+		want := n
+		if n <= 0 {
+			want = DefaultUIDLength
+		}
+		uid := GenerateUIDWithLength(n)
+		if len(uid) != want || !hexUID.MatchString(uid) {
+			t.Errorf("GenerateUIDWithLength(%d) = %q, want %d hex characters", n, uid, want)
+		}
+	}
+
+	if uid := GenerateUID(); len(uid) != DefaultUIDLength || !hexUID.MatchString(uid) {
+		t.Errorf("GenerateUID() = %q, want %d hex characters", uid, DefaultUIDLength)
+	}
+}
+
+func TestGenerateUUIDv4Format(t *testing.T) {
+	uuid := GenerateUUIDv4()
+	if len(uuid) != 36 {
+		t.Fatalf("GenerateUUIDv4() = %q, want 36 characters", uuid)
+	}
+	for i, c := range uuid {
+		isHyphen := i == 8 || i == 13 || i == 18 || i == 23
+		if isHyphen != (c == '-') {
+			t.Fatalf("GenerateUUIDv4() = %q, want hyphens only at 8, 13, 18 and 23", uuid)
+		}
+	}
+	if uuid[14] != '4' {
+		t.Errorf("GenerateUUIDv4() = %q, want version nibble 4", uuid)
+	}
+	if v := uuid[19]; v != '8' && v != '9' && v != 'a' && v != 'b' {
+		t.Errorf("GenerateUUIDv4() = %q, want RFC 4122 variant nibble", uuid)
+	}
+}
+
+func TestUIDsAreUnique(t *testing.T) {
+	const n = 10000
+	for name, generate := range map[string]func() string{
+		"GenerateUID":    GenerateUID,
+		"GenerateUUIDv4": GenerateUUIDv4,
+	} {
+		seen := make(map[string]bool, n)
+		for i := 0; i < n; i++ {
+			uid := generate()
+			if seen[uid] {
+				t.Fatalf("%s() repeated %q within %d calls", name, uid, n)
+			}
+			seen[uid] = true
+		}
+	}
+}
+
+func TestNewUIDFormat(t *testing.T) {
+	t.Setenv(UIDFormatEnv, "uuid")
+	if uid := NewUID(); len(uid) != 36 {
+		t.Errorf("NewUID() with %s=uuid = %q, want a UUID", UIDFormatEnv, uid)
+	}
+
+	t.Setenv(UIDFormatEnv, "")
+	if uid := NewUID(); len(uid) != DefaultUIDLength {
+		t.Errorf("NewUID() by default = %q, want %d hex characters", uid, DefaultUIDLength)
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"already valid", "web-server", "web-server"},
+		{"mixed case and punctuation", "My App!", "my-app"},
+		{"underscores", "my_app_v2", "my-app-v2"},
+		{"repeated separators", "a--b__c  d", "a-b-c-d"},
+		{"leading and trailing separators", "--web--", "web"},
+		{"unicode", "café-Ünïcode", "caf-n-code"},
+		{"only unicode", "日本語", "resource"},
+		{"leading digit", "123abc", "r-123abc"},
+		{"empty", "", "resource"},
+		{"only separators", "-_-", "resource"},
+		{"over-length", strings.Repeat("a", 200), strings.Repeat("a", 63)},
+		{"over-length ending at a separator", strings.Repeat("a", 62) + "-b", strings.Repeat("a", 62)},
+	}
+
+	dns1123 := regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeName(tt.input)
+			if got != tt.want {
+				t.Errorf("SanitizeName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if len(got) > 63 || !dns1123.MatchString(got) {
+				t.Errorf("SanitizeName(%q) = %q, not a valid DNS-1123 label", tt.input, got)
+			}
+		})
+	}
+}
+
+func TestGeneratePodNameLength(t *testing.T) {
+	t.Setenv(NamePrefixEnv, "team-a-")
+
+	name := GeneratePodName(strings.Repeat("x", 200))
+	if len(name) > 63 {
+		t.Errorf("GeneratePodName() = %q, longer than 63 characters", name)
+	}
+	if !strings.HasPrefix(name, "team-a-x") {
+		t.Errorf("GeneratePodName() = %q, want the prefix kept", name)
+	}
+	uid := name[strings.LastIndex(name, "-")+1:]
+	if len(uid) != DefaultUIDLength {
+		t.Errorf("GeneratePodName() = %q, want the full UID suffix kept", name)
+	}
+}
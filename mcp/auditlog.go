@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultAuditLogSize is the number of tool invocations kept per session.
+const defaultAuditLogSize = 200
+
+// maxAuditArgsLength bounds how much of the raw tool arguments is recorded.
+const maxAuditArgsLength = 200
+
+// redactedTools lists tools whose arguments carry secret values and must not
+// be recorded.
+var redactedTools = map[string]bool{
+	"create_secret": true,
+}
+
+// An AuditEntry records a single tool invocation.
+type AuditEntry struct {
+	// Name of the tool that was called.
+	Tool string `json:"tool"`
+	// Time the call was received.
+	Time time.Time `json:"time"`
+	// Arguments of the call, truncated to maxAuditArgsLength.
+	Args string `json:"args,omitempty"`
+	// Whether the call succeeded.
+	Success bool `json:"success"`
+	// Error message if the call failed.
+	Error string `json:"error,omitempty"`
+}
+
+// An AuditLog keeps a bounded, per-session history of tool invocations.
+type AuditLog struct {
+	mu         sync.Mutex
+	entries    map[string][]AuditEntry // key is session ID
+	maxEntries int
+}
+
+// NewAuditLog creates an audit log keeping at most maxEntries per session.
+func NewAuditLog(maxEntries int) *AuditLog {
+	if maxEntries <= 0 {
+		maxEntries = defaultAuditLogSize
+	}
+	return &AuditLog{
+		entries:    make(map[string][]AuditEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+// Record appends an entry to a session's log, dropping the oldest entries once the cap is reached.
+func (l *AuditLog) Record(sessionID string, entry AuditEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := append(l.entries[sessionID], entry)
+	if len(entries) > l.maxEntries {
+		entries = slices.Clone(entries[len(entries)-l.maxEntries:])
+	}
+	l.entries[sessionID] = entries
+}
+
+// Entries returns a copy of the log for a session, oldest first.
+func (l *AuditLog) Entries(sessionID string) []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.entries[sessionID])
+}
+
+// Middleware returns receiving middleware that records every tools/call request.
+func (l *AuditLog) Middleware() mcp.Middleware[*mcp.ServerSession] {
+	return func(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+		return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, ss, method, params)
+			}
+
+			entry := AuditEntry{Time: time.Now()}
+			if p, ok := params.(*mcp.CallToolParamsFor[json.RawMessage]); ok {
+				entry.Tool = p.Name
+				entry.Args = truncate(string(p.Arguments), maxAuditArgsLength)
+				if redactedTools[p.Name] {
+					entry.Args = "[redacted]"
+				}
+			}
+
+			result, err := next(ctx, ss, method, params)
+
+			// Tool handler errors are reported as results with IsError set
+			switch {
+			case err != nil:
+				entry.Error = err.Error()
+			case isErrorResult(result):
+				entry.Error = resultText(result)
+			default:
+				entry.Success = true
+			}
+			l.Record(ss.ID(), entry)
+
+			return result, err
+		}
+	}
+}
+
+// isErrorResult reports whether a tools/call result carries an error.
+func isErrorResult(result mcp.Result) bool {
+	res, ok := result.(*mcp.CallToolResult)
+	return ok && res.IsError
+}
+
+// resultText joins the text content of a tools/call result.
+func resultText(result mcp.Result) string {
+	res, ok := result.(*mcp.CallToolResult)
+	if !ok {
+		return ""
+	}
+	var parts []string
+	for _, content := range res.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// truncate shortens s to at most n bytes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+var auditLog = NewAuditLog(defaultAuditLogSize)
+
+// AuditLogQuery lists the tool invocations recorded for the caller's MCP
+// session. Other sessions' logs are never exposed.
+func AuditLogQuery(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[any], error) {
+	sessionID := ss.ID()
+
+	entries := auditLog.Entries(sessionID)
+	if len(entries) == 0 {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("No tool invocations recorded for session '%s'", sessionID)},
+			},
+		}, nil
+	}
+
+	var log strings.Builder
+	fmt.Fprintf(&log, "=== Audit Log: %s (%d calls) ===\n", sessionID, len(entries))
+	for i, entry := range entries {
+		status := "ok"
+		if !entry.Success {
+			status = "failed: " + entry.Error
+		}
+		fmt.Fprintf(&log, "%d. [%s] %s %s -> %s\n", i+1, entry.Time.Format(time.RFC3339), entry.Tool, entry.Args, status)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: log.String()},
+		},
+	}, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestAuditLogRecord(t *testing.T) {
+	l := NewAuditLog(2)
+	for _, tool := range []string{"a", "b", "c"} {
+		l.Record("s1", AuditEntry{Tool: tool, Success: true})
+	}
+	entries := l.Entries("s1")
+	if len(entries) != 2 || entries[0].Tool != "b" || entries[1].Tool != "c" {
+		t.Errorf("Entries(s1) = %v, want the last two calls b, c", entries)
+	}
+	if entries := l.Entries("s2"); len(entries) != 0 {
+		t.Errorf("Entries(s2) = %v, want none", entries)
+	}
+}
+
+func TestAuditLogOnlyShowsOwnSession(t *testing.T) {
+	httpServer := httptest.NewServer(newHTTPHandler(newTestServer()))
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	first := connectHTTP(t, httpServer.URL)
+	second := connectHTTP(t, httpServer.URL)
+
+	if _, err := first.CallTool(ctx, &mcp.CallToolParams{Name: "generate_uuid", Arguments: map[string]any{}}); err != nil {
+		t.Fatalf("CallTool(generate_uuid) failed: %v", err)
+	}
+
+	res, err := second.CallTool(ctx, &mcp.CallToolParams{Name: "audit_log", Arguments: map[string]any{}})
+	if err != nil {
+		t.Fatalf("CallTool(audit_log) failed: %v", err)
+	}
+	if text := toolText(t, res); strings.Contains(text, "generate_uuid") {
+		t.Errorf("audit_log of another session shows its calls:\n%s", text)
+	}
+
+	res, err = first.CallTool(ctx, &mcp.CallToolParams{Name: "audit_log", Arguments: map[string]any{}})
+	if err != nil {
+		t.Fatalf("CallTool(audit_log) failed: %v", err)
+	}
+	if text := toolText(t, res); !strings.Contains(text, "generate_uuid") {
+		t.Errorf("audit_log does not show the session's own call:\n%s", text)
+	}
+}
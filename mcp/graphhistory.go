@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultGraphHistorySize is the number of knowledge-graph mutations kept.
+const defaultGraphHistorySize = 500
+
+// Kinds of knowledge-graph mutation recorded in the graph history.
+const (
+	graphActionCreateEntity      = "create_entity"
+	graphActionDeleteEntity      = "delete_entity"
+	graphActionRenameEntity      = "rename_entity"
+	graphActionMergeEntities     = "merge_entities"
+	graphActionAddObservation    = "add_observation"
+	graphActionUpdateObservation = "update_observation"
+	graphActionDeleteObservation = "delete_observation"
+	graphActionCreateRelation    = "create_relation"
+	graphActionDeleteRelation    = "delete_relation"
+)
+
+// A GraphChange records a single mutation of the knowledge graph.
+type GraphChange struct {
+	// Time the change was saved.
+	Time time.Time `json:"time"`
+	// Kind of change, one of the graphAction constants.
+	Action string `json:"action"`
+	// Entity the change applies to; the source entity of a relation.
+	Entity string `json:"entity"`
+	// What changed, e.g. the observation or relation involved.
+	Detail string `json:"detail,omitempty"`
+}
+
+// A graphHistory keeps a bounded, append-only log of knowledge-graph changes.
+type graphHistory struct {
+	mu         sync.Mutex
+	changes    []GraphChange
+	maxEntries int
+}
+
+// newGraphHistory creates a history keeping at most maxEntries changes.
+func newGraphHistory(maxEntries int) *graphHistory {
+	if maxEntries <= 0 {
+		maxEntries = defaultGraphHistorySize
+	}
+	return &graphHistory{maxEntries: maxEntries}
+}
+
+// Record appends changes, dropping the oldest ones once the cap is reached.
+func (h *graphHistory) Record(changes ...GraphChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.changes = append(h.changes, changes...)
+	if len(h.changes) > h.maxEntries {
+		h.changes = slices.Clone(h.changes[len(h.changes)-h.maxEntries:])
+	}
+}
+
+// Changes returns a copy of the history, oldest first.
+func (h *graphHistory) Changes() []GraphChange {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.changes)
+}
+
+// record stamps changes with the current time and adds them to the
+// knowledge base's history, if it keeps one.
+func (k knowledgeBase) record(changes ...GraphChange) {
+	if k.history == nil || len(changes) == 0 {
+		return
+	}
+	now := time.Now()
+	for i := range changes {
+		changes[i].Time = now
+	}
+	k.history.Record(changes...)
+}
+
+// GraphHistoryArgs defines the graph history tool parameters.
+type GraphHistoryArgs struct {
+	Entity string `json:"entity,omitempty" mcp:"only show changes to this entity (optional)"`
+	Limit  int    `json:"limit,omitempty" mcp:"show only the most recent changes (optional)"`
+}
+
+// GraphHistoryResult returns recorded knowledge-graph changes.
+type GraphHistoryResult struct {
+	Changes []GraphChange `json:"changes"`
+}
+
+func (k knowledgeBase) GraphHistory(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[GraphHistoryArgs]) (*mcp.CallToolResultFor[GraphHistoryResult], error) {
+	var res mcp.CallToolResultFor[GraphHistoryResult]
+	args := params.Arguments
+
+	var changes []GraphChange
+	if k.history != nil {
+		changes = k.history.Changes()
+	}
+	if args.Entity != "" {
+		changes = slices.DeleteFunc(changes, func(change GraphChange) bool { return change.Entity != args.Entity })
+	}
+	if args.Limit > 0 && len(changes) > args.Limit {
+		changes = changes[len(changes)-args.Limit:]
+	}
+
+	var log strings.Builder
+	fmt.Fprintf(&log, "=== Knowledge Graph History (%d changes) ===\n", len(changes))
+	for i, change := range changes {
+		fmt.Fprintf(&log, "%d. [%s] %s %s", i+1, change.Time.Format(time.RFC3339), change.Action, change.Entity)
+		if change.Detail != "" {
+			fmt.Fprintf(&log, ": %s", change.Detail)
+		}
+		log.WriteString("\n")
+	}
+
+	res.Content = []mcp.Content{
+		&mcp.TextContent{Text: log.String()},
+	}
+
+	res.StructuredContent = GraphHistoryResult{Changes: changes}
+	return &res, nil
+}
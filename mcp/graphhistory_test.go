@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// changeSummary returns "action entity: detail" for each change.
+func changeSummary(changes []GraphChange) []string {
+	var summary []string
+	for _, change := range changes {
+		summary = append(summary, fmt.Sprintf("%s %s: %s", change.Action, change.Entity, change.Detail))
+	}
+	return summary
+}
+
+func TestGraphHistoryRecordsMutations(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		mutate func(k knowledgeBase) error
+		want   []string
+	}{
+		{
+			name: "create entity",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.CreateEntities(ctx, nil, &mcp.CallToolParamsFor[CreateEntitiesArgs]{Arguments: CreateEntitiesArgs{Entities: []Entity{{Name: "carol", EntityType: "person"}, {Name: "alice"}}}})
+				return err
+			},
+			want: []string{"create_entity carol: person"},
+		},
+		{
+			name: "create relation",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.CreateRelations(ctx, nil, &mcp.CallToolParamsFor[CreateRelationsArgs]{Arguments: CreateRelationsArgs{Relations: []Relation{{From: "bob", To: "go", RelationType: "uses"}, {From: "alice", To: "bob", RelationType: "knows"}}}})
+				return err
+			},
+			want: []string{"create_relation bob: bob -uses-> go"},
+		},
+		{
+			name: "add observation",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.AddObservations(ctx, nil, &mcp.CallToolParamsFor[AddObservationsArgs]{Arguments: AddObservationsArgs{Observations: []Observation{{EntityName: "bob", Contents: []string{"likes tea", "likes chess"}}}}})
+				return err
+			},
+			want: []string{"add_observation bob: likes chess"},
+		},
+		{
+			name: "update observation",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.UpdateObservations(ctx, nil, &mcp.CallToolParamsFor[UpdateObservationsArgs]{Arguments: UpdateObservationsArgs{Updates: []ObservationUpdate{{EntityName: "bob", Old: "likes tea", New: "loves tea"}}}})
+				return err
+			},
+			want: []string{`update_observation bob: "likes tea" -> "loves tea"`},
+		},
+		{
+			name: "rename entity",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.RenameEntity(ctx, nil, &mcp.CallToolParamsFor[RenameEntityArgs]{Arguments: RenameEntityArgs{Name: "bob", NewName: "robert"}})
+				return err
+			},
+			want: []string{"rename_entity robert: renamed from bob"},
+		},
+		{
+			name: "merge entities",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.MergeEntities(ctx, nil, &mcp.CallToolParamsFor[MergeEntitiesArgs]{Arguments: MergeEntitiesArgs{Source: "bob", Target: "alice"}})
+				return err
+			},
+			want: []string{"merge_entities alice: merged in bob"},
+		},
+		{
+			name: "delete entity",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.DeleteEntities(ctx, nil, &mcp.CallToolParamsFor[DeleteEntitiesArgs]{Arguments: DeleteEntitiesArgs{EntityNames: []string{"go", "carol"}}})
+				return err
+			},
+			want: []string{"delete_entity go: "},
+		},
+		{
+			name: "delete observation",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.DeleteObservations(ctx, nil, &mcp.CallToolParamsFor[DeleteObservationsArgs]{Arguments: DeleteObservationsArgs{Deletions: []Observation{{EntityName: "alice", Observations: []string{"likes go", "likes rust"}}}}})
+				return err
+			},
+			want: []string{"delete_observation alice: likes go"},
+		},
+		{
+			name: "delete relation",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.DeleteRelations(ctx, nil, &mcp.CallToolParamsFor[DeleteRelationsArgs]{Arguments: DeleteRelationsArgs{Relations: []Relation{{From: "acme", To: "go", RelationType: "uses"}}}})
+				return err
+			},
+			want: []string{"delete_relation acme: acme -uses-> go"},
+		},
+		{
+			name: "rejected rename",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.RenameEntity(ctx, nil, &mcp.CallToolParamsFor[RenameEntityArgs]{Arguments: RenameEntityArgs{Name: "bob", NewName: "alice"}})
+				return err
+			},
+		},
+		{
+			name: "rejected merge",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.MergeEntities(ctx, nil, &mcp.CallToolParamsFor[MergeEntitiesArgs]{Arguments: MergeEntitiesArgs{Source: "carol", Target: "alice"}})
+				return err
+			},
+		},
+		{
+			name: "rejected update",
+			mutate: func(k knowledgeBase) error {
+				_, err := k.UpdateObservations(ctx, nil, &mcp.CallToolParamsFor[UpdateObservationsArgs]{Arguments: UpdateObservationsArgs{Updates: []ObservationUpdate{{EntityName: "bob", Old: "likes rust", New: "loves rust"}}}})
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newSeededKnowledgeBase(t)
+			seeded := len(k.history.Changes())
+
+			if err := tt.mutate(k); err != nil {
+				t.Fatal(err)
+			}
+			changes := k.history.Changes()[seeded:]
+			if got := changeSummary(changes); !slices.Equal(got, tt.want) {
+				t.Errorf("recorded %q, want %q", got, tt.want)
+			}
+			for _, change := range changes {
+				if change.Time.IsZero() {
+					t.Errorf("change %+v has no time", change)
+				}
+			}
+		})
+	}
+}
+
+func TestGraphHistoryCap(t *testing.T) {
+	h := newGraphHistory(3)
+	for i := range 5 {
+		h.Record(GraphChange{Action: graphActionCreateEntity, Entity: fmt.Sprintf("e%d", i)})
+	}
+	var kept []string
+	for _, change := range h.Changes() {
+		kept = append(kept, change.Entity)
+	}
+	if !slices.Equal(kept, []string{"e2", "e3", "e4"}) {
+		t.Errorf("history kept %v, want the 3 most recent changes", kept)
+	}
+
+	if h := newGraphHistory(0); h.maxEntries != defaultGraphHistorySize {
+		t.Errorf("newGraphHistory(0) keeps %d changes, want %d", h.maxEntries, defaultGraphHistorySize)
+	}
+}
+
+func TestGraphHistoryTool(t *testing.T) {
+	k := newSeededKnowledgeBase(t)
+
+	tests := []struct {
+		name string
+		args GraphHistoryArgs
+		want []string
+	}{
+		{
+			name: "by entity",
+			args: GraphHistoryArgs{Entity: "alice"},
+			want: []string{"create_entity alice: person", "create_relation alice: alice -works_at-> acme", "create_relation alice: alice -knows-> bob"},
+		},
+		{
+			name: "most recent",
+			args: GraphHistoryArgs{Limit: 2},
+			want: []string{"create_relation alice: alice -knows-> bob", "create_relation acme: acme -uses-> go"},
+		},
+		{
+			name: "by entity, most recent",
+			args: GraphHistoryArgs{Entity: "alice", Limit: 1},
+			want: []string{"create_relation alice: alice -knows-> bob"},
+		},
+		{name: "unknown entity", args: GraphHistoryArgs{Entity: "carol"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := callKB(t, k.GraphHistory, tt.args)
+			if got := changeSummary(res.StructuredContent.Changes); !slices.Equal(got, tt.want) {
+				t.Errorf("history = %q, want %q", got, tt.want)
+			}
+			if header := fmt.Sprintf("=== Knowledge Graph History (%d changes) ===\n", len(tt.want)); !strings.HasPrefix(kbText(res), header) {
+				t.Errorf("text = %q, want it to start with %q", kbText(res), header)
+			}
+		})
+	}
+
+	// A knowledge base without a history reports none
+	k.history = nil
+	if res := callKB(t, k.GraphHistory, GraphHistoryArgs{}); len(res.StructuredContent.Changes) != 0 {
+		t.Errorf("history without a log = %v, want none", res.StructuredContent.Changes)
+	}
+}
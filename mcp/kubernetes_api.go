@@ -4,9 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -16,62 +23,478 @@ import (
 const (
 	DefaultAPIBaseURL = "http://localhost:8080"
 	DefaultTimeout    = 30 * time.Second
+
+	// defaultReadyTimeout is how long create_pod waits for readiness by default
+	defaultReadyTimeout = 20 * time.Second
+
+	// APIBaseURLEnv names the environment variable overriding DefaultAPIBaseURL
+	APIBaseURLEnv = "KUBE_API_BASE_URL"
+
+	// APITokenEnv names the environment variable holding the bearer token sent
+	// to the Kubernetes API, when it requires one
+	APITokenEnv = "KUBE_API_TOKEN"
 )
 
 // Kubernetes API request/response types based on the API reference
 
 // CreatePodRequest matches the API reference structure
 type CreatePodRequest struct {
-	Name          string            `json:"name"`
-	Image         string            `json:"image"`
-	ContainerName string            `json:"container_name"`
-	Port          *int              `json:"port,omitempty"`
-	Labels        map[string]string `json:"labels,omitempty"`
-	Env           map[string]string `json:"env,omitempty"`
+	Name             string            `json:"name"`
+	Image            string            `json:"image"`
+	ContainerName    string            `json:"container_name"`
+	Command          []string          `json:"command,omitempty"`
+	Args             []string          `json:"args,omitempty"`
+	Port             *int              `json:"port,omitempty"`
+	Ports            []ContainerPort   `json:"ports,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	Annotations      map[string]string `json:"annotations,omitempty"`
+	Env              map[string]string `json:"env,omitempty"`
+	Namespace        string            `json:"namespace,omitempty"`
+	Containers       []ContainerSpec   `json:"containers,omitempty"`
+	Resources        *ResourceSpec     `json:"resources,omitempty"`
+	ImagePullPolicy  string            `json:"image_pull_policy,omitempty"`
+	ImagePullSecrets []string          `json:"image_pull_secrets,omitempty"`
+	RestartPolicy    string            `json:"restart_policy,omitempty"`
+	ConfigMapRefs    []ConfigMapRef    `json:"config_map_refs,omitempty"`
+	SecretEnv        []SecretEnvVar    `json:"secret_env,omitempty"`
+	NodeSelector     map[string]string `json:"node_selector,omitempty"`
+	NodeAffinity     []NodeRequirement `json:"required_node_affinity,omitempty"`
+	Volumes          []Volume          `json:"volumes,omitempty"`
+	VolumeMounts     []VolumeMount     `json:"volume_mounts,omitempty"`
+}
+
+// NodeRequirement matches the API reference structure
+type NodeRequirement struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values,omitempty"`
+}
+
+// Volume matches the API reference structure
+type Volume struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Path      string `json:"path,omitempty"`
+	Medium    string `json:"medium,omitempty"`
+	SizeLimit string `json:"size_limit,omitempty"`
+}
+
+// VolumeMount matches the API reference structure
+type VolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mount_path"`
+	ReadOnly  bool   `json:"read_only,omitempty"`
+}
+
+// SecretEnvVar matches the API reference structure
+type SecretEnvVar struct {
+	Name      string `json:"name"`
+	SecretUID string `json:"secret_uid"`
+	Key       string `json:"key"`
+}
+
+// CreateSecretRequest matches the API reference structure
+type CreateSecretRequest struct {
+	Name      string            `json:"name"`
+	Data      map[string]string `json:"data"`
+	Namespace string            `json:"namespace,omitempty"`
+}
+
+// ConfigMapRef matches the API reference structure
+type ConfigMapRef struct {
+	UID       string `json:"uid"`
+	MountPath string `json:"mount_path,omitempty"`
+	EnvFrom   bool   `json:"env_from,omitempty"`
+}
+
+// CreateConfigMapRequest matches the API reference structure
+type CreateConfigMapRequest struct {
+	Name      string            `json:"name"`
+	Data      map[string]string `json:"data"`
+	Namespace string            `json:"namespace,omitempty"`
+}
+
+// ResourceSpec matches the API reference structure
+type ResourceSpec struct {
+	Requests map[string]string `json:"requests,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty"`
+}
+
+// ContainerSpec matches the API reference structure
+type ContainerSpec struct {
+	Name            string            `json:"name"`
+	Image           string            `json:"image"`
+	Port            int               `json:"port,omitempty"`
+	Ports           []ContainerPort   `json:"ports,omitempty"`
+	Env             map[string]string `json:"env,omitempty"`
+	Command         []string          `json:"command,omitempty"`
+	Args            []string          `json:"args,omitempty"`
+	Resources       *ResourceSpec     `json:"resources,omitempty"`
+	ImagePullPolicy string            `json:"image_pull_policy,omitempty"`
+	VolumeMounts    []VolumeMount     `json:"volume_mounts,omitempty"`
 }
 
 // CreatePodArgs for MCP tool
 type CreatePodArgs struct {
-	Name          string            `json:"name" mcp:"name of the pod"`
-	Image         string            `json:"image" mcp:"container image to use"`
-	ContainerName string            `json:"container_name" mcp:"name of the container"`
-	Port          *int              `json:"port,omitempty" mcp:"port to expose (optional)"`
-	Labels        map[string]string `json:"labels,omitempty" mcp:"labels to apply (optional)"`
-	Env           map[string]string `json:"env,omitempty" mcp:"environment variables (optional)"`
+	Name             string                `json:"name" mcp:"name of the pod"`
+	Image            string                `json:"image" mcp:"container image to use"`
+	ContainerName    string                `json:"container_name" mcp:"name of the container"`
+	Command          []string              `json:"command,omitempty" mcp:"entrypoint override, e.g. [\"sleep\", \"infinity\"] (optional, defaults to the image entrypoint)"`
+	Args             []string              `json:"args,omitempty" mcp:"arguments to the entrypoint (optional)"`
+	Port             *int                  `json:"port,omitempty" mcp:"port to expose (optional)"`
+	Ports            []ContainerPortArgs   `json:"ports,omitempty" mcp:"several ports to expose, instead of port, e.g. http and metrics (optional)"`
+	Labels           map[string]string     `json:"labels,omitempty" mcp:"labels to apply (optional)"`
+	Annotations      map[string]string     `json:"annotations,omitempty" mcp:"annotations to apply, e.g. prometheus.io/scrape (optional)"`
+	Env              map[string]string     `json:"env,omitempty" mcp:"environment variables (optional)"`
+	Namespace        string                `json:"namespace,omitempty" mcp:"namespace to create the pod in (optional, defaults to default)"`
+	Containers       []ContainerArgs       `json:"containers,omitempty" mcp:"containers for a multi-container pod, instead of image/container_name/port (optional)"`
+	Resources        *ResourceArgs         `json:"resources,omitempty" mcp:"CPU/memory requests and limits for the container (optional)"`
+	ImagePullPolicy  string                `json:"image_pull_policy,omitempty" mcp:"when to pull the image: Always, IfNotPresent or Never (optional)"`
+	ImagePullSecrets []string              `json:"image_pull_secrets,omitempty" mcp:"names of existing registry secrets for pulling private images (optional)"`
+	RestartPolicy    string                `json:"restart_policy,omitempty" mcp:"Always, OnFailure or Never; use Never for one-shot pods (optional, defaults to Always)"`
+	ConfigMapRefs    []ConfigMapRefArgs    `json:"config_map_refs,omitempty" mcp:"config maps to mount as files or inject as environment variables (optional)"`
+	SecretEnv        []SecretEnvArgs       `json:"secret_env,omitempty" mcp:"environment variables whose values come from secrets created with create_secret (optional)"`
+	NodeSelector     map[string]string     `json:"node_selector,omitempty" mcp:"node labels the pod's node must carry, e.g. {\"disktype\": \"ssd\"} (optional)"`
+	NodeAffinity     []NodeRequirementArgs `json:"required_node_affinity,omitempty" mcp:"node label requirements that must all hold, e.g. zone In [a, b] (optional)"`
+	Volumes          []VolumeArgs          `json:"volumes,omitempty" mcp:"emptyDir or hostPath volumes containers can mount (optional)"`
+	VolumeMounts     []VolumeMountArgs     `json:"volume_mounts,omitempty" mcp:"volumes to mount in the single container; use containers[].volume_mounts for multi-container pods (optional)"`
+	WaitForReady     bool                  `json:"wait_for_ready,omitempty" mcp:"wait until the pod is running and ready before returning (optional)"`
+	Timeout          string                `json:"timeout,omitempty" mcp:"how long to wait for readiness, e.g. 20s (optional, defaults to 20s, must be under 30s)"`
+}
+
+// ConfigMapRefArgs references a config map created with create_configmap
+type ConfigMapRefArgs struct {
+	UID       string `json:"uid" mcp:"unique identifier of the config map"`
+	MountPath string `json:"mount_path,omitempty" mcp:"absolute directory to mount the keys as files in (optional)"`
+	EnvFrom   bool   `json:"env_from,omitempty" mcp:"expose the keys as environment variables (optional)"`
+}
+
+// VolumeArgs declares a pod volume
+type VolumeArgs struct {
+	Name      string `json:"name" mcp:"volume name, referenced by volume_mounts"`
+	Type      string `json:"type" mcp:"emptyDir or hostPath"`
+	Path      string `json:"path,omitempty" mcp:"absolute path on the node, hostPath only"`
+	Medium    string `json:"medium,omitempty" mcp:"Memory for a tmpfs-backed emptyDir (optional)"`
+	SizeLimit string `json:"size_limit,omitempty" mcp:"emptyDir size limit, e.g. 1Gi (optional)"`
+}
+
+// VolumeMountArgs mounts a declared volume into a container
+type VolumeMountArgs struct {
+	Name      string `json:"name" mcp:"name of a volume in volumes"`
+	MountPath string `json:"mount_path" mcp:"absolute path to mount the volume at"`
+	ReadOnly  bool   `json:"read_only,omitempty" mcp:"mount read-only (optional)"`
+}
+
+// NodeRequirementArgs is one required node affinity expression
+type NodeRequirementArgs struct {
+	Key      string   `json:"key" mcp:"node label key, e.g. topology.kubernetes.io/zone"`
+	Operator string   `json:"operator" mcp:"In, NotIn, Exists, DoesNotExist, Gt or Lt"`
+	Values   []string `json:"values,omitempty" mcp:"label values; none for Exists/DoesNotExist, one integer for Gt/Lt"`
+}
+
+// SecretEnvArgs sets an environment variable from a secret key
+type SecretEnvArgs struct {
+	Name      string `json:"name" mcp:"environment variable name"`
+	SecretUID string `json:"secret_uid" mcp:"unique identifier of the secret"`
+	Key       string `json:"key" mcp:"key within the secret"`
+}
+
+// CreateSecretArgs for creating a secret
+type CreateSecretArgs struct {
+	Name      string            `json:"name" mcp:"name of the secret"`
+	Data      map[string]string `json:"data" mcp:"key/value pairs to store; values are never returned"`
+	Namespace string            `json:"namespace,omitempty" mcp:"namespace to create the secret in (optional, defaults to default)"`
+}
+
+// CreateConfigMapArgs for creating a config map
+type CreateConfigMapArgs struct {
+	Name      string            `json:"name" mcp:"name of the config map"`
+	Data      map[string]string `json:"data" mcp:"key/value configuration entries"`
+	Namespace string            `json:"namespace,omitempty" mcp:"namespace to create the config map in (optional, defaults to default)"`
+}
+
+// GetConfigMapArgs for retrieving a config map
+type GetConfigMapArgs struct {
+	UID       string `json:"uid" mcp:"unique identifier of the config map"`
+	Namespace string `json:"namespace,omitempty" mcp:"namespace of the config map (optional, defaults to default)"`
+}
+
+// ResourceArgs are CPU/memory requests and limits, e.g. {"cpu": "250m", "memory": "128Mi"}
+type ResourceArgs struct {
+	Requests map[string]string `json:"requests,omitempty" mcp:"minimum cpu/memory reserved for the container (optional)"`
+	Limits   map[string]string `json:"limits,omitempty" mcp:"maximum cpu/memory the container may use (optional)"`
+}
+
+// ContainerArgs describes one container of a multi-container pod
+type ContainerArgs struct {
+	Name            string              `json:"name" mcp:"container name, unique within the pod"`
+	Image           string              `json:"image" mcp:"container image to use"`
+	Port            int                 `json:"port,omitempty" mcp:"port to expose (optional)"`
+	Ports           []ContainerPortArgs `json:"ports,omitempty" mcp:"several ports to expose, instead of port (optional)"`
+	Env             map[string]string   `json:"env,omitempty" mcp:"environment variables for this container (optional)"`
+	Command         []string            `json:"command,omitempty" mcp:"entrypoint override (optional)"`
+	Args            []string            `json:"args,omitempty" mcp:"arguments to the entrypoint (optional)"`
+	Resources       *ResourceArgs       `json:"resources,omitempty" mcp:"CPU/memory requests and limits (optional)"`
+	ImagePullPolicy string              `json:"image_pull_policy,omitempty" mcp:"when to pull the image: Always, IfNotPresent or Never (optional)"`
+	VolumeMounts    []VolumeMountArgs   `json:"volume_mounts,omitempty" mcp:"volumes from the pod's volumes list to mount in this container (optional)"`
+}
+
+// ApplyManifestArgs for creating an object from a raw manifest
+type ApplyManifestArgs struct {
+	Manifest  string `json:"manifest" mcp:"a YAML or JSON manifest of Pods, Services, Deployments or ConfigMaps; multi-document YAML such as an export bundle is applied in order"`
+	Namespace string `json:"namespace,omitempty" mcp:"namespace to create objects in when the manifest sets none (optional, defaults to default)"`
+}
+
+// CreatePodsBatchArgs for creating several pods in one call
+type CreatePodsBatchArgs struct {
+	Pods []CreatePodArgs `json:"pods" mcp:"pods to create (at most 50); each is created independently"`
+}
+
+// BatchCreatePodsRequest matches the API reference structure
+type BatchCreatePodsRequest struct {
+	Pods []CreatePodRequest `json:"pods"`
+}
+
+// ReconcilePodsArgs for making the managed pods match a desired set
+type ReconcilePodsArgs struct {
+	Pods   []CreatePodArgs `json:"pods" mcp:"the complete desired set of pods; each name must be unique"`
+	DryRun bool            `json:"dry_run,omitempty" mcp:"report the planned changes without applying them (optional)"`
+}
+
+// ReconcilePodsRequest matches the API reference structure
+type ReconcilePodsRequest struct {
+	Pods   []CreatePodRequest `json:"pods"`
+	DryRun bool               `json:"dry_run,omitempty"`
 }
 
 // GetPodArgs for retrieving pod by UID
 type GetPodArgs struct {
-	UID string `json:"uid" mcp:"unique identifier of the pod"`
+	UID       string `json:"uid" mcp:"unique identifier of the pod"`
+	Namespace string `json:"namespace,omitempty" mcp:"namespace of the pod (optional, defaults to default)"`
+}
+
+// ListPodsArgs for listing pods
+type ListPodsArgs struct {
+	LabelSelector string `json:"label_selector,omitempty" mcp:"only list pods matching this label selector, e.g. app=nginx (optional)"`
+	Namespace     string `json:"namespace,omitempty" mcp:"namespace to list (optional, defaults to default)"`
 }
 
 // DeletePodArgs for deleting pod by UID
 type DeletePodArgs struct {
-	UID string `json:"uid" mcp:"unique identifier of the pod to delete"`
+	UID                string `json:"uid" mcp:"unique identifier of the pod to delete"`
+	Cascade            bool   `json:"cascade,omitempty" mcp:"also delete services that select only this pod (optional)"`
+	Namespace          string `json:"namespace,omitempty" mcp:"namespace of the pod (optional, defaults to default)"`
+	GracePeriodSeconds *int   `json:"grace_period_seconds,omitempty" mcp:"seconds the pod gets to shut down (optional, defaults to the pod's own grace period)"`
+	Force              bool   `json:"force,omitempty" mcp:"delete immediately with no grace period, for stuck pods (optional)"`
+}
+
+// PodOperationArgs for running a lifecycle operation on a pod
+type PodOperationArgs struct {
+	UID       string `json:"uid" mcp:"unique identifier of the pod"`
+	Operation string `json:"operation" mcp:"restart, delete, stop or start; stop and start scale the pod's owning deployment"`
+	Namespace string `json:"namespace,omitempty" mcp:"namespace of the pod (optional, defaults to default)"`
+}
+
+// PodOperationRequest matches the API reference structure
+type PodOperationRequest struct {
+	UID       string `json:"uid"`
+	Operation string `json:"operation"`
 }
 
 // GetPodLogsArgs for retrieving pod logs
 type GetPodLogsArgs struct {
-	UID   string `json:"uid" mcp:"unique identifier of the pod"`
-	Lines *int   `json:"lines,omitempty" mcp:"number of log lines to retrieve (optional)"`
+	UID       string `json:"uid" mcp:"unique identifier of the pod"`
+	Lines     *int   `json:"lines,omitempty" mcp:"number of log lines to retrieve (optional)"`
+	Previous  bool   `json:"previous,omitempty" mcp:"fetch logs of the previous container instance, e.g. after a crash (optional)"`
+	Container string `json:"container,omitempty" mcp:"container to read logs from, required when the pod has several (optional)"`
+	Namespace string `json:"namespace,omitempty" mcp:"namespace of the pod (optional, defaults to default)"`
+}
+
+// ContainerRestartInfoArgs for retrieving container restart reasons
+type ContainerRestartInfoArgs struct {
+	UID string `json:"uid" mcp:"unique identifier of the pod"`
+}
+
+// ClusterCapabilitiesArgs for discovering cluster features
+type ClusterCapabilitiesArgs struct {
+	Refresh bool `json:"refresh,omitempty" mcp:"bypass the cached result (optional)"`
+}
+
+// GetNodeArgs for retrieving node details
+type GetNodeArgs struct {
+	Name string `json:"name" mcp:"name of the node"`
+}
+
+// ScaleDeploymentArgs for changing a deployment's replica count
+type ScaleDeploymentArgs struct {
+	UID      string `json:"uid" mcp:"unique identifier of the deployment"`
+	Replicas int    `json:"replicas" mcp:"desired number of replicas (0 or more)"`
+}
+
+// ScaleDeploymentRequest matches the API reference structure
+type ScaleDeploymentRequest struct {
+	Replicas int `json:"replicas"`
+}
+
+// RecentActivityArgs for listing recently created/deleted resources
+type RecentActivityArgs struct {
+	Limit int `json:"limit,omitempty" mcp:"maximum number of operations to return, newest first (optional)"`
+}
+
+// WhyPendingArgs for diagnosing an unscheduled pod
+type WhyPendingArgs struct {
+	UID string `json:"uid" mcp:"unique identifier of the pod"`
+}
+
+// GetPodEventsArgs for listing the events of a pod
+type GetPodEventsArgs struct {
+	UID       string `json:"uid" mcp:"unique identifier of the pod"`
+	Namespace string `json:"namespace,omitempty" mcp:"namespace of the pod (optional, defaults to default)"`
+}
+
+// GetPodMetricsArgs for retrieving pod resource usage
+type GetPodMetricsArgs struct {
+	UID       string `json:"uid" mcp:"unique identifier of the pod"`
+	Namespace string `json:"namespace,omitempty" mcp:"namespace of the pod (optional, defaults to default)"`
+}
+
+// UpdatePodEnvRequest matches the API reference structure
+type UpdatePodEnvRequest struct {
+	Env       map[string]string `json:"env"`
+	Container string            `json:"container,omitempty"`
+}
+
+// UpdatePodEnvArgs for MCP tool
+type UpdatePodEnvArgs struct {
+	UID       string            `json:"uid" mcp:"unique identifier of the pod"`
+	Env       map[string]string `json:"env" mcp:"environment variables to set or replace"`
+	Container string            `json:"container,omitempty" mcp:"container to update (optional, defaults to all containers)"`
+}
+
+// UpdatePodLabelsRequest matches the API reference structure
+type UpdatePodLabelsRequest struct {
+	Set    map[string]string `json:"set,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
+// UpdatePodLabelsArgs for MCP tool
+type UpdatePodLabelsArgs struct {
+	UID       string            `json:"uid" mcp:"unique identifier of the pod"`
+	Set       map[string]string `json:"set,omitempty" mcp:"labels to add or change (optional)"`
+	Remove    []string          `json:"remove,omitempty" mcp:"label keys to remove (optional); the uid label cannot be changed or removed"`
+	Namespace string            `json:"namespace,omitempty" mcp:"namespace of the pod (optional, defaults to default)"`
+}
+
+// ExecInPodArgs for running a command in a pod
+type ExecInPodArgs struct {
+	UID       string   `json:"uid" mcp:"unique identifier of the pod"`
+	Command   []string `json:"command" mcp:"command and arguments, run without a shell, e.g. [\"ls\", \"-la\", \"/app\"]; use [\"sh\", \"-c\", \"...\"] for shell syntax"`
+	Container string   `json:"container,omitempty" mcp:"container to run in (optional when the pod has one container)"`
+	Timeout   string   `json:"timeout,omitempty" mcp:"how long the command may run, e.g. 1m (optional, defaults to 30s, at most 5m)"`
+	Namespace string   `json:"namespace,omitempty" mcp:"namespace of the pod (optional, defaults to default)"`
+}
+
+// ExecRequest matches the API reference structure
+type ExecRequest struct {
+	Container string   `json:"container,omitempty"`
+	Command   []string `json:"command"`
+}
+
+// PortForwardArgs for forwarding a pod port
+type PortForwardArgs struct {
+	UID       string `json:"uid" mcp:"unique identifier of the pod"`
+	PodPort   int    `json:"pod_port" mcp:"port inside the pod to forward to"`
+	LocalPort int    `json:"local_port,omitempty" mcp:"port to listen on, on the host running the Kubernetes API (optional, defaults to any free port)"`
+	Duration  string `json:"duration,omitempty" mcp:"how long the forward stays open, e.g. 10m (optional, defaults to 5m, at most 30m)"`
+	Namespace string `json:"namespace,omitempty" mcp:"namespace of the pod (optional, defaults to default)"`
+}
+
+// ExportPodArgs for exporting a single pod's manifest
+type ExportPodArgs struct {
+	UID       string `json:"uid" mcp:"unique identifier of the pod"`
+	Format    string `json:"format,omitempty" mcp:"yaml or json (optional, defaults to yaml)"`
+	Namespace string `json:"namespace,omitempty" mcp:"namespace of the pod (optional, defaults to default)"`
+}
+
+// ExportResourcesArgs for exporting managed resources
+type ExportResourcesArgs struct {
+	LabelSelector string `json:"label_selector,omitempty" mcp:"label selector for resources to export (optional, defaults to all UID-labeled resources)"`
+	Namespace     string `json:"namespace,omitempty" mcp:"namespace to export (optional, defaults to default)"`
+	AllNamespaces bool   `json:"all_namespaces,omitempty" mcp:"export every namespace instead of one (optional)"`
 }
 
 // CreateServiceRequest matches the API reference structure
 type CreateServiceRequest struct {
-	Name        string `json:"name"`
-	PodUID      string `json:"pod_uid"`
-	Port        int    `json:"port"`
-	TargetPort  int    `json:"target_port"`
-	ServiceType string `json:"service_type"` // ClusterIP, NodePort, LoadBalancer
+	Name         string            `json:"name"`
+	PodUID       string            `json:"pod_uid"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	Port         int               `json:"port,omitempty"`
+	TargetPort   interface{}       `json:"target_port,omitempty"` // port number or named container port
+	Ports        []ServicePortSpec `json:"ports,omitempty"`
+	ServiceType  string            `json:"service_type"` // ClusterIP, NodePort, LoadBalancer, ExternalName
+	ExternalName string            `json:"external_name,omitempty"`
+	Namespace    string            `json:"namespace,omitempty"`
+}
+
+// ServicePortSpec matches the API reference structure
+type ServicePortSpec struct {
+	Name       string      `json:"name,omitempty"`
+	Port       int         `json:"port"`
+	TargetPort interface{} `json:"target_port,omitempty"`
+	Protocol   string      `json:"protocol,omitempty"`
+	NodePort   int         `json:"node_port,omitempty"`
+}
+
+// ContainerPort matches the API reference structure
+type ContainerPort struct {
+	Name          string `json:"name,omitempty"`
+	ContainerPort int    `json:"container_port"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+// ServicePortArgs describes one port of a multi-port service
+type ServicePortArgs struct {
+	Name           string `json:"name,omitempty" mcp:"port name (required when more than one port is given)"`
+	Port           int    `json:"port" mcp:"service port (1-65535)"`
+	TargetPort     int    `json:"target_port,omitempty" mcp:"target port number on the pod (optional, defaults to port)"`
+	TargetPortName string `json:"target_port_name,omitempty" mcp:"named container port to target instead of target_port (optional)"`
+	Protocol       string `json:"protocol,omitempty" mcp:"TCP, UDP or SCTP (optional, defaults to TCP)"`
+	NodePort       int    `json:"node_port,omitempty" mcp:"fixed node port for NodePort/LoadBalancer services (optional, allocated by the cluster otherwise)"`
+}
+
+// ContainerPortArgs describes one port a container exposes
+type ContainerPortArgs struct {
+	Name          string `json:"name,omitempty" mcp:"port name services can target, e.g. http or metrics (optional)"`
+	ContainerPort int    `json:"container_port" mcp:"port number (1-65535)"`
+	Protocol      string `json:"protocol,omitempty" mcp:"TCP, UDP or SCTP (optional, defaults to TCP)"`
 }
 
 // CreateServiceArgs for MCP tool
 type CreateServiceArgs struct {
-	Name        string `json:"name" mcp:"name of the service"`
-	PodUID      string `json:"pod_uid" mcp:"UID of the pod to link to"`
-	Port        int    `json:"port" mcp:"service port"`
-	TargetPort  int    `json:"target_port" mcp:"target port on the pod"`
-	ServiceType string `json:"service_type" mcp:"service type (ClusterIP, NodePort, LoadBalancer)"`
+	Name           string            `json:"name" mcp:"name of the service"`
+	PodUID         string            `json:"pod_uid" mcp:"UID of the pod to link to"`
+	Annotations    map[string]string `json:"annotations,omitempty" mcp:"annotations to apply, e.g. load balancer hints (optional)"`
+	Port           int               `json:"port,omitempty" mcp:"service port (1-65535), for a single-port service"`
+	TargetPort     int               `json:"target_port,omitempty" mcp:"target port number on the pod (optional, defaults to port)"`
+	TargetPortName string            `json:"target_port_name,omitempty" mcp:"named container port to target instead of target_port (optional)"`
+	Ports          []ServicePortArgs `json:"ports,omitempty" mcp:"list of ports for a multi-port service, instead of port/target_port (optional)"`
+	ServiceType    string            `json:"service_type" mcp:"service type (ClusterIP, NodePort, LoadBalancer, ExternalName)"`
+	ExternalName   string            `json:"external_name,omitempty" mcp:"DNS name to alias, required for ExternalName services (pod_uid and ports are then optional)"`
+	Namespace      string            `json:"namespace,omitempty" mcp:"namespace to create the service in, same as the pod's (optional, defaults to default)"`
+}
+
+// DeleteServiceArgs for deleting a service by UID
+type DeleteServiceArgs struct {
+	UID       string `json:"uid" mcp:"unique identifier of the service to delete"`
+	Namespace string `json:"namespace,omitempty" mcp:"namespace of the service (optional, defaults to default)"`
+}
+
+// ProbeServiceArgs for checking that a service responds
+type ProbeServiceArgs struct {
+	UID            string `json:"uid" mcp:"unique identifier of the service"`
+	Path           string `json:"path,omitempty" mcp:"HTTP path to request (optional, defaults to /)"`
+	Port           string `json:"port,omitempty" mcp:"service port number or name (optional, defaults to the first port)"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" mcp:"seconds to wait for a response (optional, defaults to 5, at most 300)"`
 }
 
 // APIResponse represents the standard API response format
@@ -82,9 +505,83 @@ type APIResponse struct {
 	Error   string                 `json:"error,omitempty"`
 }
 
+// APIError is returned when the Kubernetes API responds with a failure status
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s", e.Message)
+}
+
+// ToolError is the structured content of a tool result for an expected failure
+type ToolError struct {
+	Code    string `json:"code"` // not_found, invalid_argument, unauthenticated, rate_limited, unavailable, upstream_error, cancelled, deadline_exceeded
+	Message string `json:"message"`
+}
+
+// toolError converts expected failures (the API rejected the request or could
+// not be reached) into a tool result with IsError set, so the caller can tell
+// them apart from genuine server faults, which are still returned as errors.
+func toolError(action string, err error) (*mcp.CallToolResultFor[interface{}], error) {
+	var code, message string
+
+	var apiErr *APIError
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &apiErr):
+		message = apiErr.Message
+		switch {
+		case apiErr.StatusCode == http.StatusNotFound:
+			code = "not_found"
+		case apiErr.StatusCode == http.StatusUnauthorized:
+			code = "unauthenticated"
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			code = "rate_limited"
+		case apiErr.StatusCode >= http.StatusInternalServerError:
+			code = "upstream_error"
+		default:
+			code = "invalid_argument"
+		}
+	case errors.Is(err, context.Canceled):
+		code = "cancelled"
+		message = "the request was cancelled"
+	case errors.Is(err, context.DeadlineExceeded):
+		code = "deadline_exceeded"
+		message = "the request deadline passed before the API responded"
+	case errors.As(err, &urlErr):
+		code = "unavailable"
+		message = urlErr.Err.Error()
+	default:
+		return nil, fmt.Errorf("%s: %w", action, err)
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("%s: %s (%s)", action, message, code)},
+		},
+		StructuredContent: ToolError{Code: code, Message: message},
+		IsError:           true,
+	}, nil
+}
+
+// invalidArgument reports tool arguments rejected before any API call, in the
+// same shape as toolError
+func invalidArgument(action, message string) (*mcp.CallToolResultFor[interface{}], error) {
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("%s: %s (invalid_argument)", action, message)},
+		},
+		StructuredContent: ToolError{Code: "invalid_argument", Message: message},
+		IsError:           true,
+	}, nil
+}
+
 // APIClient handles HTTP requests to the Kubernetes API
 type APIClient struct {
 	BaseURL    string
+	Token      string // sent as "Authorization: Bearer <token>" when set
 	HTTPClient *http.Client
 }
 
@@ -102,12 +599,57 @@ func NewAPIClient(baseURL string) *APIClient {
 	}
 }
 
-// makeRequest performs HTTP requests to the Kubernetes API
-func (c *APIClient) makeRequest(method, endpoint string, payload interface{}) (*APIResponse, error) {
+// NewAPIClientFromEnv creates an API client for the URL in KUBE_API_BASE_URL,
+// falling back to DefaultAPIBaseURL when it is unset, authenticating with the
+// token in KUBE_API_TOKEN if any
+func NewAPIClientFromEnv() (*APIClient, error) {
+	baseURL := os.Getenv(APIBaseURLEnv)
+	if baseURL == "" {
+		client := NewAPIClient("")
+		client.Token = os.Getenv(APITokenEnv)
+		return client, nil
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", APIBaseURLEnv, baseURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid %s %q: must be an absolute http or https URL", APIBaseURLEnv, baseURL)
+	}
+
+	client := NewAPIClient(strings.TrimSuffix(baseURL, "/"))
+	client.Token = os.Getenv(APITokenEnv)
+	return client, nil
+}
+
+// authorize adds the bearer token to req, if the client has one
+func (c *APIClient) authorize(req *http.Request) {
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+}
+
+// rawPayload is sent by makeRequest as the request body unchanged, for
+// endpoints that take something other than JSON, e.g. a YAML manifest
+type rawPayload struct {
+	ContentType string
+	Data        string
+}
+
+// makeRequest performs HTTP requests to the Kubernetes API, abandoning them
+// when ctx is cancelled
+func (c *APIClient) makeRequest(ctx context.Context, method, endpoint string, payload interface{}) (*APIResponse, error) {
 	url := c.BaseURL + endpoint
 
 	var body io.Reader
-	if payload != nil {
+	contentType := "application/json"
+	switch p := payload.(type) {
+	case nil:
+	case rawPayload:
+		body = strings.NewReader(p.Data)
+		contentType = p.ContentType
+	default:
 		jsonData, err := json.Marshal(payload)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request payload: %w", err)
@@ -115,14 +657,15 @@ func (c *APIClient) makeRequest(method, endpoint string, payload interface{}) (*
 		body = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	if payload != nil {
-		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Type", contentType)
 	}
+	c.authorize(req)
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
@@ -135,27 +678,54 @@ func (c *APIClient) makeRequest(method, endpoint string, payload interface{}) (*
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// For logs endpoint, return raw text
-	if endpoint == "/api/v1/pods/logs" || (len(endpoint) > 20 && endpoint[len(endpoint)-5:] == "/logs") {
-		return &APIResponse{
-			Success: true,
-			Data:    map[string]interface{}{"logs": string(respBody)},
-		}, nil
-	}
-
 	var apiResp APIResponse
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return nil, &APIError{StatusCode: resp.StatusCode, Message: resp.Status}
+		}
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	if !apiResp.Success {
-		return &apiResp, fmt.Errorf("API error: %s", apiResp.Error)
+		return &apiResp, &APIError{StatusCode: resp.StatusCode, Message: apiResp.Error}
 	}
 
 	return &apiResp, nil
 }
 
+// makeRawRequest performs HTTP requests to endpoints that return plain text or YAML
+func (c *APIClient) makeRawRequest(ctx context.Context, method, endpoint string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	c.authorize(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Errors are still reported in the standard JSON format
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiResp APIResponse
+		if err := json.Unmarshal(respBody, &apiResp); err == nil && apiResp.Error != "" {
+			return "", &APIError{StatusCode: resp.StatusCode, Message: apiResp.Error}
+		}
+		return "", &APIError{StatusCode: resp.StatusCode, Message: resp.Status}
+	}
+
+	return string(respBody), nil
+}
+
 // Global API client instance
+// kubeAPI is configured from the environment in main
 var kubeAPI = NewAPIClient("")
 
 // MCP Tool implementations
@@ -164,37 +734,129 @@ var kubeAPI = NewAPIClient("")
 func CreatePod(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CreatePodArgs]) (*mcp.CallToolResultFor[interface{}], error) {
 	args := params.Arguments
 
-	req := CreatePodRequest{
-		Name:          args.Name,
-		Image:         args.Image,
-		ContainerName: args.ContainerName,
-		Labels:        args.Labels,
-		Env:           args.Env,
+	endpoint := "/api/v1/pods"
+	if args.WaitForReady {
+		// The wait has to finish within the HTTP client's own timeout
+		timeout := defaultReadyTimeout
+		if args.Timeout != "" {
+			d, err := time.ParseDuration(args.Timeout)
+			if err != nil || d <= 0 || d >= DefaultTimeout {
+				return invalidArgument("failed to create pod", fmt.Sprintf("invalid timeout %q: must be a positive duration under %s", args.Timeout, DefaultTimeout))
+			}
+			timeout = d
+		}
+		endpoint += "?wait=true&timeout=" + timeout.String()
 	}
 
-	if args.Port != nil {
-		req.Port = args.Port
+	resp, err := kubeAPI.makeRequest(ctx, "POST", endpoint, newCreatePodRequest(args))
+	if err != nil {
+		return toolError("failed to create pod", err)
 	}
 
-	resp, err := kubeAPI.makeRequest("POST", "/api/v1/pods", req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create pod: %w", err)
+	result := fmt.Sprintf("Pod created successfully: %s", resp.Message)
+	if args.WaitForReady {
+		result += fmt.Sprintf("\nUID: %v\nStatus: %v", resp.Data["uid"], resp.Data["status"])
+		containers, _ := resp.Data["containers"].([]interface{})
+		for _, item := range containers {
+			container, _ := item.(map[string]interface{})
+			if reason, _ := container["reason"].(string); reason != "" {
+				result += fmt.Sprintf("\n- %v: %v (%s)", container["name"], container["state"], reason)
+			}
+		}
 	}
 
 	return &mcp.CallToolResultFor[interface{}]{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: fmt.Sprintf("Pod created successfully: %s", resp.Message)},
+			&mcp.TextContent{Text: result},
 		},
 	}, nil
 }
 
+// newCreatePodRequest converts create_pod tool arguments to an API request
+func newCreatePodRequest(args CreatePodArgs) CreatePodRequest {
+	req := CreatePodRequest{
+		Name:             args.Name,
+		Image:            args.Image,
+		ContainerName:    args.ContainerName,
+		Command:          args.Command,
+		Args:             args.Args,
+		Labels:           args.Labels,
+		Annotations:      args.Annotations,
+		Env:              args.Env,
+		Namespace:        args.Namespace,
+		ImagePullPolicy:  args.ImagePullPolicy,
+		ImagePullSecrets: args.ImagePullSecrets,
+		RestartPolicy:    args.RestartPolicy,
+	}
+
+	if args.Port != nil {
+		req.Port = args.Port
+	}
+	for _, port := range args.Ports {
+		req.Ports = append(req.Ports, ContainerPort(port))
+	}
+
+	if args.Resources != nil {
+		req.Resources = &ResourceSpec{Requests: args.Resources.Requests, Limits: args.Resources.Limits}
+	}
+
+	for _, container := range args.Containers {
+		spec := ContainerSpec{
+			Name:            container.Name,
+			Image:           container.Image,
+			Port:            container.Port,
+			Env:             container.Env,
+			Command:         container.Command,
+			Args:            container.Args,
+			ImagePullPolicy: container.ImagePullPolicy,
+		}
+		for _, mount := range container.VolumeMounts {
+			spec.VolumeMounts = append(spec.VolumeMounts, VolumeMount(mount))
+		}
+		for _, port := range container.Ports {
+			spec.Ports = append(spec.Ports, ContainerPort(port))
+		}
+		if container.Resources != nil {
+			spec.Resources = &ResourceSpec{Requests: container.Resources.Requests, Limits: container.Resources.Limits}
+		}
+		req.Containers = append(req.Containers, spec)
+	}
+
+	for _, ref := range args.ConfigMapRefs {
+		req.ConfigMapRefs = append(req.ConfigMapRefs, ConfigMapRef(ref))
+	}
+
+	for _, v := range args.SecretEnv {
+		req.SecretEnv = append(req.SecretEnv, SecretEnvVar(v))
+	}
+
+	req.NodeSelector = args.NodeSelector
+	for _, requirement := range args.NodeAffinity {
+		req.NodeAffinity = append(req.NodeAffinity, NodeRequirement(requirement))
+	}
+
+	for _, volume := range args.Volumes {
+		req.Volumes = append(req.Volumes, Volume(volume))
+	}
+	for _, mount := range args.VolumeMounts {
+		req.VolumeMounts = append(req.VolumeMounts, VolumeMount(mount))
+	}
+
+	return req
+}
+
 // GetPod retrieves pod details by UID
 func GetPod(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[GetPodArgs]) (*mcp.CallToolResultFor[interface{}], error) {
 	args := params.Arguments
 
-	resp, err := kubeAPI.makeRequest("GET", fmt.Sprintf("/api/v1/pods/%s", args.UID), nil)
+	endpoint := fmt.Sprintf("/api/v1/pods/%s", args.UID)
+	if args.Namespace != "" {
+		endpoint += "?namespace=" + url.QueryEscape(args.Namespace)
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pod: %w", err)
+		return toolError("failed to get pod", err)
 	}
 
 	// Format the pod data for display
@@ -208,10 +870,25 @@ func GetPod(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolPara
 }
 
 // ListPods retrieves all pods managed by the API
-func ListPods(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[interface{}], error) {
-	resp, err := kubeAPI.makeRequest("GET", "/api/v1/pods", nil)
+func ListPods(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ListPodsArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	query := url.Values{}
+	if args.LabelSelector != "" {
+		query.Set("labelSelector", args.LabelSelector)
+	}
+	if args.Namespace != "" {
+		query.Set("namespace", args.Namespace)
+	}
+
+	endpoint := "/api/v1/pods"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list pods: %w", err)
+		return toolError("failed to list pods", err)
 	}
 
 	// Format the pods list for display
@@ -244,81 +921,578 @@ func ListPods(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolPa
 func DeletePod(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[DeletePodArgs]) (*mcp.CallToolResultFor[interface{}], error) {
 	args := params.Arguments
 
-	resp, err := kubeAPI.makeRequest("DELETE", fmt.Sprintf("/api/v1/pods/%s", args.UID), nil)
+	query := url.Values{}
+	if args.Cascade {
+		query.Set("cascade", "true")
+	}
+	if args.Namespace != "" {
+		query.Set("namespace", args.Namespace)
+	}
+	if args.GracePeriodSeconds != nil {
+		query.Set("gracePeriodSeconds", strconv.Itoa(*args.GracePeriodSeconds))
+	}
+	if args.Force {
+		query.Set("force", "true")
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/pods/%s", args.UID)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "DELETE", endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to delete pod: %w", err)
+		return toolError("failed to delete pod", err)
+	}
+
+	result := fmt.Sprintf("Pod deleted successfully: %s", resp.Message)
+	if services, _ := resp.Data["cascaded_services"].([]interface{}); len(services) > 0 {
+		result += "\nDeleted services:"
+		for _, service := range services {
+			result += fmt.Sprintf("\n- %v", service)
+		}
+	}
+	if cascadeErrors, _ := resp.Data["cascade_errors"].([]interface{}); len(cascadeErrors) > 0 {
+		result += "\nCascade errors:"
+		for _, cascadeErr := range cascadeErrors {
+			result += fmt.Sprintf("\n- %v", cascadeErr)
+		}
 	}
 
 	return &mcp.CallToolResultFor[interface{}]{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: fmt.Sprintf("Pod deleted successfully: %s", resp.Message)},
+			&mcp.TextContent{Text: result},
 		},
 	}, nil
 }
 
-// GetPodLogs retrieves logs from a specific pod
-func GetPodLogs(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[GetPodLogsArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+// ApplyManifest creates Pods, Services, Deployments and ConfigMaps from a raw manifest and returns their UIDs
+func ApplyManifest(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ApplyManifestArgs]) (*mcp.CallToolResultFor[interface{}], error) {
 	args := params.Arguments
 
-	endpoint := fmt.Sprintf("/api/v1/pods/%s/logs", args.UID)
-	if args.Lines != nil {
-		endpoint += fmt.Sprintf("?lines=%d", *args.Lines)
+	if strings.TrimSpace(args.Manifest) == "" {
+		return invalidArgument("failed to apply manifest", "manifest is required")
+	}
+
+	endpoint := "/api/v1/apply"
+	if args.Namespace != "" {
+		endpoint += "?namespace=" + url.QueryEscape(args.Namespace)
 	}
 
-	resp, err := kubeAPI.makeRequest("GET", endpoint, nil)
+	payload := rawPayload{ContentType: "application/yaml", Data: args.Manifest}
+	resp, err := kubeAPI.makeRequest(ctx, "POST", endpoint, payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pod logs: %w", err)
+		return toolError("failed to apply manifest", err)
 	}
 
-	logs, _ := resp.Data["logs"].(string)
+	result := resp.Message
+	items, ok := resp.Data["items"].([]interface{})
+	if !ok {
+		items = []interface{}{resp.Data}
+	}
+	for _, item := range items {
+		applied, _ := item.(map[string]interface{})
+		result += fmt.Sprintf("\n\nKind: %v\nUID: %v\nName: %v\nNamespace: %v",
+			applied["kind"], applied["uid"], applied["name"], applied["namespace"])
+	}
 
 	return &mcp.CallToolResultFor[interface{}]{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: fmt.Sprintf("Pod Logs for %s:\n%s", args.UID, logs)},
+			&mcp.TextContent{Text: result},
 		},
 	}, nil
 }
 
-// CreateService creates a service linked to a pod
-func CreateService(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CreateServiceArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+// CreatePodsBatch creates several pods, reporting the outcome of each one
+func CreatePodsBatch(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CreatePodsBatchArgs]) (*mcp.CallToolResultFor[interface{}], error) {
 	args := params.Arguments
 
-	req := CreateServiceRequest{
-		Name:        args.Name,
-		PodUID:      args.PodUID,
-		Port:        args.Port,
-		TargetPort:  args.TargetPort,
-		ServiceType: args.ServiceType,
+	req := BatchCreatePodsRequest{Pods: []CreatePodRequest{}}
+	for _, pod := range args.Pods {
+		req.Pods = append(req.Pods, newCreatePodRequest(pod))
 	}
 
-	resp, err := kubeAPI.makeRequest("POST", "/api/v1/services", req)
+	resp, err := kubeAPI.makeRequest(ctx, "POST", "/api/v1/pods/batch", req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create service: %w", err)
+		return toolError("failed to create pods", err)
+	}
+
+	result := fmt.Sprintf("Batch complete: %s", resp.Message)
+	results, _ := resp.Data["results"].([]interface{})
+	for _, item := range results {
+		podResult, _ := item.(map[string]interface{})
+		if podResult["success"] == true {
+			result += fmt.Sprintf("\n- %v: created (UID: %v, pod: %v)", podResult["name"], podResult["uid"], podResult["pod_name"])
+		} else {
+			result += fmt.Sprintf("\n- %v: failed: %v", podResult["name"], podResult["error"])
+		}
 	}
 
+	failed, _ := resp.Data["failed"].(float64)
 	return &mcp.CallToolResultFor[interface{}]{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: fmt.Sprintf("Service created successfully: %s", resp.Message)},
+			&mcp.TextContent{Text: result},
 		},
+		IsError: failed > 0,
 	}, nil
 }
 
-// ListServices retrieves all services managed by the API
-func ListServices(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[interface{}], error) {
-	resp, err := kubeAPI.makeRequest("GET", "/api/v1/services", nil)
+// ReconcilePods creates missing pods and deletes extra ones so the managed pods match the desired set
+func ReconcilePods(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ReconcilePodsArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	req := ReconcilePodsRequest{
+		Pods:   []CreatePodRequest{},
+		DryRun: args.DryRun,
+	}
+	for _, pod := range args.Pods {
+		req.Pods = append(req.Pods, newCreatePodRequest(pod))
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "POST", "/api/v1/pods/reconcile", req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list services: %w", err)
+		return toolError("failed to reconcile pods", err)
 	}
 
-	// Format the services list for display
-	if items, ok := resp.Data["items"].([]interface{}); ok {
-		result := fmt.Sprintf("Found %d services:\n", len(items))
-		for i, item := range items {
-			if svc, ok := item.(map[string]interface{}); ok {
-				uid, _ := svc["uid"].(string)
-				name, _ := svc["name"].(string)
-				result += fmt.Sprintf("%d. UID: %s, Name: %s\n", i+1, uid, name)
-			}
+	result := fmt.Sprintf("Reconcile complete: %s", resp.Message)
+	if args.DryRun {
+		result = fmt.Sprintf("Reconcile dry run (nothing changed): %s", resp.Message)
+	}
+	for _, section := range []string{"created", "deleted", "unchanged"} {
+		actions, _ := resp.Data[section].([]interface{})
+		for _, item := range actions {
+			action, _ := item.(map[string]interface{})
+			result += fmt.Sprintf("\n- %s: %v (UID: %v, pod: %v)", section, action["name"], action["uid"], action["pod_name"])
+		}
+	}
+
+	reconcileErrors, _ := resp.Data["errors"].([]interface{})
+	if len(reconcileErrors) > 0 {
+		result += "\nErrors:"
+		for _, reconcileErr := range reconcileErrors {
+			result += fmt.Sprintf("\n- %v", reconcileErr)
+		}
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+		IsError: len(reconcileErrors) > 0,
+	}, nil
+}
+
+// PodOperation restarts, deletes, stops or starts a pod
+func PodOperation(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[PodOperationArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	endpoint := fmt.Sprintf("/api/v1/pods/%s/operation", args.UID)
+	if args.Namespace != "" {
+		endpoint += "?namespace=" + url.QueryEscape(args.Namespace)
+	}
+
+	req := PodOperationRequest{UID: args.UID, Operation: args.Operation}
+	resp, err := kubeAPI.makeRequest(ctx, "POST", endpoint, req)
+	if err != nil {
+		return toolError(fmt.Sprintf("failed to %s pod", args.Operation), err)
+	}
+
+	result := resp.Message
+	if args.Operation == "restart" {
+		result += fmt.Sprintf("\nNew pod: %v (UID unchanged: %s)", resp.Data["pod_name"], args.UID)
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
+// GetPodLogs retrieves logs from a specific pod
+func GetPodLogs(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[GetPodLogsArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	query := url.Values{}
+	if args.Lines != nil {
+		query.Set("lines", strconv.Itoa(*args.Lines))
+	}
+	if args.Previous {
+		query.Set("previous", "true")
+	}
+	if args.Container != "" {
+		query.Set("container", args.Container)
+	}
+	if args.Namespace != "" {
+		query.Set("namespace", args.Namespace)
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/pods/%s/logs", args.UID)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	// Logs are returned as plain text
+	logs, err := kubeAPI.makeRawRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return toolError("failed to get pod logs", err)
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Pod Logs for %s:\n%s", args.UID, logs)},
+		},
+	}, nil
+}
+
+// ContainerRestartInfo reports the restart count and last termination of each container in a pod
+func ContainerRestartInfo(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ContainerRestartInfoArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	resp, err := kubeAPI.makeRequest(ctx, "GET", fmt.Sprintf("/api/v1/pods/%s/restarts", args.UID), nil)
+	if err != nil {
+		return toolError("failed to get container restart info", err)
+	}
+
+	containers, _ := resp.Data["containers"].([]interface{})
+
+	restarted := 0
+	result := fmt.Sprintf("Container restart info for %s:\n", args.UID)
+	for _, item := range containers {
+		container, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := container["name"].(string)
+		restartCount, _ := container["restart_count"].(float64)
+		if restartCount == 0 {
+			result += fmt.Sprintf("- %s: no restarts\n", name)
+			continue
+		}
+
+		restarted++
+		result += fmt.Sprintf("- %s: %d restarts", name, int(restartCount))
+		if reason, _ := container["last_termination_reason"].(string); reason != "" {
+			result += fmt.Sprintf(", last termination: %s", reason)
+		}
+		if exitCode, ok := container["last_exit_code"].(float64); ok {
+			result += fmt.Sprintf(" (exit code %d)", int(exitCode))
+		}
+		if finishedAt, _ := container["last_terminated_at"].(string); finishedAt != "" {
+			result += fmt.Sprintf(" at %s", finishedAt)
+		}
+		if message, _ := container["last_termination_message"].(string); message != "" {
+			result += fmt.Sprintf("\n  message: %s", message)
+		}
+		result += "\n"
+	}
+
+	if restarted == 0 {
+		result = fmt.Sprintf("No restarts recorded for pod %s", args.UID)
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
+// WhyPending summarizes why a pod has not been scheduled
+func WhyPending(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[WhyPendingArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	resp, err := kubeAPI.makeRequest(ctx, "GET", fmt.Sprintf("/api/v1/pods/%s/scheduling", args.UID), nil)
+	if err != nil {
+		return toolError("failed to get pod scheduling", err)
+	}
+
+	status, _ := resp.Data["status"].(string)
+	if scheduled, _ := resp.Data["scheduled"].(bool); scheduled {
+		nodeName, _ := resp.Data["node_name"].(string)
+		return &mcp.CallToolResultFor[interface{}]{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("Pod %s is scheduled on node %s (status: %s)", args.UID, nodeName, status)},
+			},
+		}, nil
+	}
+
+	result := fmt.Sprintf("Pod %s is not scheduled (status: %s)\n", args.UID, status)
+	if causes, _ := resp.Data["causes"].([]interface{}); len(causes) > 0 {
+		result += "Likely causes:\n"
+		for _, cause := range causes {
+			result += fmt.Sprintf("- %v\n", cause)
+		}
+	}
+	if message, _ := resp.Data["message"].(string); message != "" {
+		result += fmt.Sprintf("Scheduler: %s\n", message)
+	}
+	if events, _ := resp.Data["events"].([]interface{}); len(events) > 0 {
+		result += "FailedScheduling events:\n"
+		for _, item := range events {
+			if event, ok := item.(map[string]interface{}); ok {
+				count, _ := event["count"].(float64)
+				message, _ := event["message"].(string)
+				result += fmt.Sprintf("- (x%d) %s\n", int(count), message)
+			}
+		}
+	} else if status == "Pending" {
+		result += "No FailedScheduling events yet; the scheduler may not have attempted the pod.\n"
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
+// GetPodEvents lists the events involving a pod, oldest first
+func GetPodEvents(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[GetPodEventsArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	endpoint := fmt.Sprintf("/api/v1/pods/%s/events", args.UID)
+	if args.Namespace != "" {
+		endpoint += "?" + url.Values{"namespace": {args.Namespace}}.Encode()
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return toolError("failed to get pod events", err)
+	}
+
+	events, _ := resp.Data["events"].([]interface{})
+	if len(events) == 0 {
+		return &mcp.CallToolResultFor[interface{}]{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("No events recorded for pod %s", args.UID)},
+			},
+		}, nil
+	}
+
+	result := fmt.Sprintf("Events for pod %s (oldest first):\n", args.UID)
+	for _, item := range events {
+		if event, ok := item.(map[string]interface{}); ok {
+			count, _ := event["count"].(float64)
+			result += fmt.Sprintf("- %v [%v %v] (x%d) %v\n",
+				event["last_timestamp"], event["type"], event["reason"], int(count), event["message"])
+		}
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
+// GetPodMetrics reports the current CPU and memory usage of each container in a pod
+func GetPodMetrics(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[GetPodMetricsArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	endpoint := fmt.Sprintf("/api/v1/pods/%s/metrics", args.UID)
+	if args.Namespace != "" {
+		endpoint += "?" + url.Values{"namespace": {args.Namespace}}.Encode()
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return toolError("failed to get pod metrics", err)
+	}
+
+	result := fmt.Sprintf("Resource usage for pod %s (window %v):\nTotal: cpu=%v memory=%v\n",
+		args.UID, resp.Data["window"], resp.Data["total_cpu"], resp.Data["total_memory"])
+	if containers, _ := resp.Data["containers"].([]interface{}); len(containers) > 0 {
+		result += "\nContainers:\n"
+		for _, item := range containers {
+			if container, ok := item.(map[string]interface{}); ok {
+				result += fmt.Sprintf("- %v: cpu=%v memory=%v\n", container["name"], container["cpu"], container["memory"])
+			}
+		}
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
+// UpdatePodEnv merges environment variables into a pod by recreating it
+func UpdatePodEnv(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[UpdatePodEnvArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	req := UpdatePodEnvRequest{
+		Env:       args.Env,
+		Container: args.Container,
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "PATCH", fmt.Sprintf("/api/v1/pods/%s/env", args.UID), req)
+	if err != nil {
+		return toolError("failed to update pod env", err)
+	}
+
+	podData, _ := json.MarshalIndent(resp.Data, "", "  ")
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("%s (the pod was deleted and recreated):\n%s", resp.Message, string(podData))},
+		},
+	}, nil
+}
+
+// CreateService creates a service linked to a pod
+func CreateService(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CreateServiceArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	req := CreateServiceRequest{
+		Name:         args.Name,
+		PodUID:       args.PodUID,
+		Annotations:  args.Annotations,
+		Port:         args.Port,
+		TargetPort:   args.TargetPort,
+		ServiceType:  args.ServiceType,
+		ExternalName: args.ExternalName,
+		Namespace:    args.Namespace,
+	}
+
+	if args.TargetPortName != "" {
+		req.TargetPort = args.TargetPortName
+	}
+
+	for _, port := range args.Ports {
+		spec := ServicePortSpec{
+			Name:       port.Name,
+			Port:       port.Port,
+			TargetPort: port.TargetPort,
+			Protocol:   port.Protocol,
+			NodePort:   port.NodePort,
+		}
+		if port.TargetPortName != "" {
+			spec.TargetPort = port.TargetPortName
+		}
+		req.Ports = append(req.Ports, spec)
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "POST", "/api/v1/services", req)
+	if err != nil {
+		return toolError("failed to create service", err)
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Service created successfully: %s", resp.Message)},
+		},
+	}, nil
+}
+
+// CreateConfigMap creates a config map that pods can reference by UID
+func CreateConfigMap(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CreateConfigMapArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	req := CreateConfigMapRequest{
+		Name:      args.Name,
+		Data:      args.Data,
+		Namespace: args.Namespace,
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "POST", "/api/v1/configmaps", req)
+	if err != nil {
+		return toolError("failed to create config map", err)
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("%s\nUID: %v\nName: %v\nNamespace: %v",
+				resp.Message, resp.Data["uid"], resp.Data["name"], resp.Data["namespace"])},
+		},
+	}, nil
+}
+
+// CreateSecret stores sensitive values in a secret that pods can reference by
+// UID. The values are not echoed back.
+func CreateSecret(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CreateSecretArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	req := CreateSecretRequest{
+		Name:      args.Name,
+		Data:      args.Data,
+		Namespace: args.Namespace,
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "POST", "/api/v1/secrets", req)
+	if err != nil {
+		return toolError("failed to create secret", err)
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("%s\nUID: %v\nName: %v\nNamespace: %v\nKeys: %v",
+				resp.Message, resp.Data["uid"], resp.Data["name"], resp.Data["namespace"], resp.Data["keys"])},
+		},
+	}, nil
+}
+
+// GetConfigMap retrieves a config map and its data by UID
+func GetConfigMap(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[GetConfigMapArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	endpoint := fmt.Sprintf("/api/v1/configmaps/%s", args.UID)
+	if args.Namespace != "" {
+		endpoint += "?namespace=" + url.QueryEscape(args.Namespace)
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return toolError("failed to get config map", err)
+	}
+
+	configMapData, _ := json.MarshalIndent(resp.Data, "", "  ")
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Config Map Details:\n%s", string(configMapData))},
+		},
+	}, nil
+}
+
+// DeleteService removes a service by UID
+func DeleteService(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[DeleteServiceArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	endpoint := fmt.Sprintf("/api/v1/services/%s", args.UID)
+	if args.Namespace != "" {
+		endpoint += "?namespace=" + url.QueryEscape(args.Namespace)
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return toolError("failed to delete service", err)
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Service deleted successfully: %s", resp.Message)},
+		},
+	}, nil
+}
+
+// ListServices retrieves all services managed by the API
+func ListServices(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[interface{}], error) {
+	resp, err := kubeAPI.makeRequest(ctx, "GET", "/api/v1/services", nil)
+	if err != nil {
+		return toolError("failed to list services", err)
+	}
+
+	// Format the services list for display
+	if items, ok := resp.Data["items"].([]interface{}); ok {
+		result := fmt.Sprintf("Found %d services:\n", len(items))
+		for i, item := range items {
+			if svc, ok := item.(map[string]interface{}); ok {
+				uid, _ := svc["uid"].(string)
+				name, _ := svc["name"].(string)
+				result += fmt.Sprintf("%d. UID: %s, Name: %s\n", i+1, uid, name)
+			}
 		}
 
 		return &mcp.CallToolResultFor[interface{}]{
@@ -335,11 +1509,228 @@ func ListServices(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallTo
 	}, nil
 }
 
+// ExportResources dumps all managed pods, services and deployments as a YAML bundle
+func ExportResources(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ExportResourcesArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	query := url.Values{}
+	if args.LabelSelector != "" {
+		query.Set("labelSelector", args.LabelSelector)
+	}
+	if args.AllNamespaces {
+		query.Set("allNamespaces", "true")
+	} else if args.Namespace != "" {
+		query.Set("namespace", args.Namespace)
+	}
+
+	endpoint := "/api/v1/export"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	bundle, err := kubeAPI.makeRawRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return toolError("failed to export resources", err)
+	}
+
+	if bundle == "" {
+		return &mcp.CallToolResultFor[interface{}]{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: "No resources found to export"},
+			},
+		}, nil
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: bundle},
+		},
+	}, nil
+}
+
+// UpdatePodLabels sets and removes labels on a pod in place
+func UpdatePodLabels(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[UpdatePodLabelsArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	endpoint := fmt.Sprintf("/api/v1/pods/%s/labels", args.UID)
+	if args.Namespace != "" {
+		endpoint += "?namespace=" + url.QueryEscape(args.Namespace)
+	}
+
+	req := UpdatePodLabelsRequest{Set: args.Set, Remove: args.Remove}
+	resp, err := kubeAPI.makeRequest(ctx, "PATCH", endpoint, req)
+	if err != nil {
+		return toolError("failed to update pod labels", err)
+	}
+
+	labels, _ := json.MarshalIndent(resp.Data["labels"], "", "  ")
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("%s\nPod: %v\nLabels:\n%s", resp.Message, resp.Data["name"], labels)},
+		},
+	}, nil
+}
+
+// ExecInPod runs a command in a pod container and returns its output and exit code
+func ExecInPod(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ExecInPodArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	if len(args.Command) == 0 {
+		return invalidArgument("failed to exec in pod", "command is required")
+	}
+
+	query := url.Values{}
+	if args.Timeout != "" {
+		query.Set("timeout", args.Timeout)
+	}
+	if args.Namespace != "" {
+		query.Set("namespace", args.Namespace)
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/pods/%s/exec", args.UID)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req := ExecRequest{Container: args.Container, Command: args.Command}
+	resp, err := kubeAPI.makeRequest(ctx, "POST", endpoint, req)
+	if err != nil {
+		return toolError("failed to exec in pod", err)
+	}
+
+	result := fmt.Sprintf("%s (container: %v)", resp.Message, resp.Data["container"])
+	if stdout, _ := resp.Data["stdout"].(string); stdout != "" {
+		result += "\n\nstdout:\n" + stdout
+	}
+	if stderr, _ := resp.Data["stderr"].(string); stderr != "" {
+		result += "\n\nstderr:\n" + stderr
+	}
+	if truncated, _ := resp.Data["truncated"].(bool); truncated {
+		result += "\n\n(output truncated)"
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
+// PortForward opens a short-lived forward to a pod port and returns the address to connect to
+func PortForward(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[PortForwardArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	query := url.Values{}
+	query.Set("podPort", strconv.Itoa(args.PodPort))
+	if args.LocalPort != 0 {
+		query.Set("localPort", strconv.Itoa(args.LocalPort))
+	}
+	if args.Duration != "" {
+		query.Set("duration", args.Duration)
+	}
+	if args.Namespace != "" {
+		query.Set("namespace", args.Namespace)
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/pods/%s/portforward?%s", args.UID, query.Encode())
+	resp, err := kubeAPI.makeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return toolError("failed to port-forward", err)
+	}
+
+	result := fmt.Sprintf("%s\nConnect to: %v (on the Kubernetes API host)\nPod: %v, port %v\nExpires at: %v",
+		resp.Message, resp.Data["local_address"], resp.Data["pod_name"], resp.Data["pod_port"], resp.Data["expires_at"])
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
+// ExportPod returns a pod's manifest, including Kubernetes defaults, ready to be created elsewhere
+func ExportPod(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ExportPodArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	query := url.Values{}
+	if args.Format != "" {
+		query.Set("format", args.Format)
+	}
+	if args.Namespace != "" {
+		query.Set("namespace", args.Namespace)
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/pods/%s/export", args.UID)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	manifest, err := kubeAPI.makeRawRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return toolError("failed to export pod", err)
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: manifest},
+		},
+	}, nil
+}
+
+// ProbeService issues an HTTP GET to a service from inside the cluster and reports the outcome
+func ProbeService(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ProbeServiceArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	query := url.Values{}
+	if args.Path != "" {
+		query.Set("path", args.Path)
+	}
+	if args.Port != "" {
+		query.Set("port", args.Port)
+	}
+	if args.TimeoutSeconds > 0 {
+		query.Set("timeout", fmt.Sprintf("%ds", args.TimeoutSeconds))
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/services/%s/probe", args.UID)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return toolError("failed to probe service", err)
+	}
+
+	outcome, _ := resp.Data["outcome"].(string)
+	name, _ := resp.Data["name"].(string)
+	path, _ := resp.Data["path"].(string)
+	port, _ := resp.Data["port"].(string)
+	latency, _ := resp.Data["latency_ms"].(float64)
+	statusCode, _ := resp.Data["status_code"].(float64)
+
+	result := fmt.Sprintf("Probe of %s:%s%s: %s", name, port, path, outcome)
+	if statusCode > 0 {
+		result += fmt.Sprintf(" (HTTP %d)", int(statusCode))
+	}
+	result += fmt.Sprintf(" in %dms", int(latency))
+	if probeErr, _ := resp.Data["error"].(string); probeErr != "" {
+		result += fmt.Sprintf("\nError: %s", probeErr)
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
 // GetClusterInfo retrieves cluster status and node information
 func GetClusterInfo(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[interface{}], error) {
-	resp, err := kubeAPI.makeRequest("GET", "/api/v1/cluster/info", nil)
+	resp, err := kubeAPI.makeRequest(ctx, "GET", "/api/v1/cluster/info", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get cluster info: %w", err)
+		return toolError("failed to get cluster info", err)
 	}
 
 	// Format cluster info for display
@@ -352,11 +1743,141 @@ func GetClusterInfo(ctx context.Context, ss *mcp.ServerSession, params *mcp.Call
 	}, nil
 }
 
+// ClusterCapabilities reports the API versions and optional features the cluster supports
+func ClusterCapabilities(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ClusterCapabilitiesArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	endpoint := "/api/v1/cluster/capabilities"
+	if params.Arguments.Refresh {
+		endpoint += "?refresh=true"
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return toolError("failed to get cluster capabilities", err)
+	}
+
+	version, _ := resp.Data["server_version"].(string)
+	result := fmt.Sprintf("Kubernetes %s\n\nOptional features:\n", version)
+
+	features, _ := resp.Data["features"].(map[string]interface{})
+	names := slices.Sorted(maps.Keys(features))
+	for _, name := range names {
+		available := "unavailable"
+		if ok, _ := features[name].(bool); ok {
+			available = "available"
+		}
+		result += fmt.Sprintf("- %s: %s\n", name, available)
+	}
+
+	if apiVersions, _ := resp.Data["api_versions"].([]interface{}); len(apiVersions) > 0 {
+		result += "\nAPI versions:\n"
+		for _, apiVersion := range apiVersions {
+			result += fmt.Sprintf("- %v\n", apiVersion)
+		}
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
+// GetNode retrieves allocatable resources, conditions and kubelet version of a node
+func GetNode(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[GetNodeArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	if params.Arguments.Name == "" {
+		return invalidArgument("failed to get node", "name is required")
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "GET", fmt.Sprintf("/api/v1/nodes/%s", params.Arguments.Name), nil)
+	if err != nil {
+		return toolError("failed to get node", err)
+	}
+
+	result := fmt.Sprintf("Node: %v\nReady: %v\nSchedulable: %v\nKubelet version: %v\nInternal IP: %v\n",
+		resp.Data["name"], resp.Data["ready"], resp.Data["schedulable"],
+		resp.Data["kubelet_version"], resp.Data["internal_ip"])
+	result += fmt.Sprintf("Allocatable: cpu=%v memory=%v pods=%v\n",
+		resp.Data["allocatable_cpu"], resp.Data["allocatable_memory"], resp.Data["allocatable_pods"])
+
+	if conditions, _ := resp.Data["conditions"].([]interface{}); len(conditions) > 0 {
+		result += "\nConditions:\n"
+		for _, item := range conditions {
+			condition, _ := item.(map[string]interface{})
+			result += fmt.Sprintf("- %v: %v", condition["type"], condition["status"])
+			if reason, _ := condition["reason"].(string); reason != "" {
+				result += fmt.Sprintf(" (%s)", reason)
+			}
+			result += "\n"
+		}
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
+// ScaleDeployment changes the desired replica count of a deployment
+func ScaleDeployment(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ScaleDeploymentArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	args := params.Arguments
+
+	req := ScaleDeploymentRequest{Replicas: args.Replicas}
+	resp, err := kubeAPI.makeRequest(ctx, "PUT", fmt.Sprintf("/api/v1/deployments/%s/scale", args.UID), req)
+	if err != nil {
+		return toolError("failed to scale deployment", err)
+	}
+
+	result := fmt.Sprintf("%s\nDesired replicas: %v\nAvailable replicas: %v",
+		resp.Message, resp.Data["replicas"], resp.Data["available_replicas"])
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
+// RecentActivity lists the resources recently created or deleted through the API
+func RecentActivity(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[RecentActivityArgs]) (*mcp.CallToolResultFor[interface{}], error) {
+	endpoint := "/api/v1/activity"
+	if params.Arguments.Limit > 0 {
+		endpoint += fmt.Sprintf("?limit=%d", params.Arguments.Limit)
+	}
+
+	resp, err := kubeAPI.makeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return toolError("failed to get recent activity", err)
+	}
+
+	items, _ := resp.Data["items"].([]interface{})
+	if len(items) == 0 {
+		return &mcp.CallToolResultFor[interface{}]{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: "No recent activity"},
+			},
+		}, nil
+	}
+
+	result := fmt.Sprintf("Recent activity (%d operations, newest first):\n", len(items))
+	for _, item := range items {
+		entry, _ := item.(map[string]interface{})
+		result += fmt.Sprintf("- [%v] %v %v %v (UID: %v, namespace: %v)\n",
+			entry["timestamp"], entry["action"], entry["kind"], entry["name"], entry["uid"], entry["namespace"])
+	}
+
+	return &mcp.CallToolResultFor[interface{}]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result},
+		},
+	}, nil
+}
+
 // HealthCheck verifies API availability
 func HealthCheck(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[interface{}], error) {
-	resp, err := kubeAPI.makeRequest("GET", "/health", nil)
+	resp, err := kubeAPI.makeRequest(ctx, "GET", "/health", nil)
 	if err != nil {
-		return nil, fmt.Errorf("health check failed: %w", err)
+		return toolError("health check failed", err)
 	}
 
 	return &mcp.CallToolResultFor[interface{}]{
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// withAPI points kubeAPI at a test server running handler for the duration of the test.
+func withAPI(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	saved := kubeAPI
+	kubeAPI = NewAPIClient(server.URL)
+	t.Cleanup(func() {
+		kubeAPI = saved
+		server.Close()
+	})
+}
+
+// resultContent joins the text content of a kubernetes tool result.
+func resultContent(res *mcp.CallToolResultFor[interface{}]) string {
+	var parts []string
+	for _, content := range res.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func TestGetPodLogsWithOptions(t *testing.T) {
+	var gotPath, gotQuery string
+	withAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("line one\nline two\n"))
+	})
+
+	lines := 20
+	res, err := GetPodLogs(context.Background(), nil, &mcp.CallToolParamsFor[GetPodLogsArgs]{
+		Arguments: GetPodLogsArgs{UID: "abc123", Lines: &lines, Previous: true, Container: "app"},
+	})
+	if err != nil {
+		t.Fatalf("GetPodLogs() failed: %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("GetPodLogs() returned an error result: %s", resultContent(res))
+	}
+
+	if gotPath != "/api/v1/pods/abc123/logs" {
+		t.Errorf("request path = %q, want /api/v1/pods/abc123/logs", gotPath)
+	}
+	for _, param := range []string{"previous=true", "container=app", "lines=20"} {
+		if !strings.Contains(gotQuery, param) {
+			t.Errorf("request query %q is missing %s", gotQuery, param)
+		}
+	}
+	if text := resultContent(res); !strings.Contains(text, "line one\nline two") {
+		t.Errorf("GetPodLogs() = %q, want the plain-text logs", text)
+	}
+}
+
+func TestGetPodLogsAPIError(t *testing.T) {
+	withAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"success":false,"error":"Pod with UID abc123 not found"}`))
+	})
+
+	res, err := GetPodLogs(context.Background(), nil, &mcp.CallToolParamsFor[GetPodLogsArgs]{
+		Arguments: GetPodLogsArgs{UID: "abc123", Previous: true},
+	})
+	if err != nil {
+		t.Fatalf("GetPodLogs() failed: %v", err)
+	}
+	if !res.IsError {
+		t.Fatal("GetPodLogs() of a missing pod: IsError = false, want true")
+	}
+	if text := resultContent(res); !strings.Contains(text, "Pod with UID abc123 not found") {
+		t.Errorf("GetPodLogs() = %q, want the API error message", text)
+	}
+}
+
+func TestRequestCancelledMidFlight(t *testing.T) {
+	received := make(chan struct{})
+	withAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		// Never answer; only the client giving up ends the request
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	start := time.Now()
+	res, err := GetPod(ctx, nil, &mcp.CallToolParamsFor[GetPodArgs]{Arguments: GetPodArgs{UID: "abc123"}})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("GetPod() returned %v after the context was cancelled", elapsed)
+	}
+	if err != nil {
+		t.Fatalf("GetPod() failed: %v", err)
+	}
+	if !res.IsError || !strings.Contains(resultContent(res), "(cancelled)") {
+		t.Errorf("GetPod() with a cancelled context = %q, want a cancelled error result", resultContent(res))
+	}
+
+	_, err = kubeAPI.makeRequest(ctx, "GET", "/api/v1/pods", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("makeRequest() with a cancelled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestListPodsLabelSelector(t *testing.T) {
+	var gotSelector string
+	withAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		gotSelector = r.URL.Query().Get("labelSelector")
+		w.Write([]byte(`{"success":true,"data":{"items":[{"uid":"a","name":"web-1","status":"Running"}],"count":1}}`))
+	})
+
+	res, err := ListPods(context.Background(), nil, &mcp.CallToolParamsFor[ListPodsArgs]{
+		Arguments: ListPodsArgs{LabelSelector: "app=nginx,tier in (web)"},
+	})
+	if err != nil {
+		t.Fatalf("ListPods() failed: %v", err)
+	}
+	if gotSelector != "app=nginx,tier in (web)" {
+		t.Errorf("labelSelector query = %q, want the selector unchanged", gotSelector)
+	}
+	if text := resultContent(res); !strings.Contains(text, "Found 1 pods") {
+		t.Errorf("ListPods() = %q, want the listed pod", text)
+	}
+}
+
+func TestMakeRequestForwardsToken(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL)
+	if _, err := client.makeRequest(context.Background(), http.MethodGet, "/api/v1/pods", nil); err != nil {
+		t.Fatalf("makeRequest() without a token failed: %v", err)
+	}
+	client.Token = "secret"
+	if _, err := client.makeRequest(context.Background(), http.MethodGet, "/api/v1/pods", nil); err != nil {
+		t.Fatalf("makeRequest() with a token failed: %v", err)
+	}
+
+	if got[0] != "" {
+		t.Errorf("Authorization without a token = %q, want none", got[0])
+	}
+	if got[1] != "Bearer secret" {
+		t.Errorf("Authorization with a token = %q, want %q", got[1], "Bearer secret")
+	}
+}
+
+func TestMakeRequestUnauthorized(t *testing.T) {
+	withAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"success":false,"error":"Missing or invalid bearer token"}`))
+	})
+
+	_, err := kubeAPI.makeRequest(context.Background(), http.MethodGet, "/api/v1/pods", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("makeRequest() = %v, want a 401 APIError", err)
+	}
+}
+
+func TestCreatePodFailureIsErrorResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantCode string
+		wantText string
+	}{
+		{"rejected", http.StatusBadRequest, `{"success":false,"error":"invalid restart_policy \"Sometimes\""}`, "invalid_argument", `invalid restart_policy "Sometimes"`},
+		{"missing reference", http.StatusBadRequest, `{"success":false,"error":"config map not found: abc in namespace default"}`, "invalid_argument", "config map not found"},
+		{"cluster failure", http.StatusInternalServerError, `{"success":false,"error":"pods \"web\" is forbidden: exceeded quota"}`, "upstream_error", "exceeded quota"},
+		{"rate limited", http.StatusTooManyRequests, `{"success":false,"error":"Rate limit exceeded, retry later"}`, "rate_limited", "Rate limit exceeded"},
+		{"non-JSON body", http.StatusBadGateway, `<html>bad gateway</html>`, "upstream_error", "502 Bad Gateway"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withAPI(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			res, err := CreatePod(context.Background(), nil, &mcp.CallToolParamsFor[CreatePodArgs]{
+				Arguments: CreatePodArgs{Name: "web", Image: "nginx", ContainerName: "nginx"},
+			})
+			if err != nil {
+				t.Fatalf("CreatePod() returned a protocol error %v, want an error result", err)
+			}
+			if !res.IsError {
+				t.Fatal("CreatePod() of a rejected pod: IsError = false, want true")
+			}
+			text := resultContent(res)
+			if !strings.Contains(text, "failed to create pod") || !strings.Contains(text, tt.wantText) {
+				t.Errorf("CreatePod() = %q, want it to embed %q", text, tt.wantText)
+			}
+			if toolErr, ok := res.StructuredContent.(ToolError); !ok || toolErr.Code != tt.wantCode || !strings.Contains(toolErr.Message, tt.wantText) {
+				t.Errorf("CreatePod() structured content = %+v, want code %s with the API message", res.StructuredContent, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCreatePodAPIUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	saved := kubeAPI
+	kubeAPI = NewAPIClient(server.URL)
+	t.Cleanup(func() { kubeAPI = saved })
+
+	res, err := CreatePod(context.Background(), nil, &mcp.CallToolParamsFor[CreatePodArgs]{
+		Arguments: CreatePodArgs{Name: "web", Image: "nginx", ContainerName: "nginx"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePod() returned a protocol error %v, want an error result", err)
+	}
+	if toolErr, ok := res.StructuredContent.(ToolError); !res.IsError || !ok || toolErr.Code != "unavailable" {
+		t.Errorf("CreatePod() with the API down = %q, want an unavailable error result", resultContent(res))
+	}
+}
+
+func TestCreatePodInvalidTimeout(t *testing.T) {
+	withAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("CreatePod() called the API with an invalid timeout")
+	})
+
+	res, err := CreatePod(context.Background(), nil, &mcp.CallToolParamsFor[CreatePodArgs]{
+		Arguments: CreatePodArgs{Name: "web", Image: "nginx", ContainerName: "nginx", WaitForReady: true, Timeout: "forever"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePod() returned a protocol error %v, want an error result", err)
+	}
+	if !res.IsError || !strings.Contains(resultContent(res), "invalid timeout") {
+		t.Errorf("CreatePod() with an invalid timeout = %q, want an invalid_argument result", resultContent(res))
+	}
+}
+
+func TestExportResourcesAllNamespaces(t *testing.T) {
+	var gotQuery string
+	withAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write([]byte("---\nkind: Pod\n"))
+	})
+
+	res, err := ExportResources(context.Background(), nil, &mcp.CallToolParamsFor[ExportResourcesArgs]{
+		Arguments: ExportResourcesArgs{Namespace: "team-a", AllNamespaces: true},
+	})
+	if err != nil {
+		t.Fatalf("ExportResources() failed: %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("ExportResources() returned an error result: %s", resultContent(res))
+	}
+	if gotQuery != "allNamespaces=true" {
+		t.Errorf("request query = %q, want allNamespaces=true", gotQuery)
+	}
+}
+
+func TestApplyManifestBundle(t *testing.T) {
+	withAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"success":true,"message":"2 objects created successfully","data":{"items":[` +
+			`{"kind":"Pod","uid":"a1","name":"web","namespace":"default"},` +
+			`{"kind":"Service","uid":"c3","name":"web","namespace":"default"}]}}`))
+	})
+
+	res, err := ApplyManifest(context.Background(), nil, &mcp.CallToolParamsFor[ApplyManifestArgs]{
+		Arguments: ApplyManifestArgs{Manifest: "---\nkind: Pod\n---\nkind: Service\n"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyManifest() failed: %v", err)
+	}
+	text := resultContent(res)
+	for _, want := range []string{"Kind: Pod\nUID: a1", "Kind: Service\nUID: c3"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("ApplyManifest() = %q, want it to contain %q", text, want)
+		}
+	}
+}
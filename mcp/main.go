@@ -1,18 +1,14 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"log"
 	"os"
+	"sync"
 
-	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -51,6 +47,43 @@ type AddObservationsResult struct {
 	Observations []Observation `json:"observations"`
 }
 
+// UpdateObservationsArgs defines the update observations tool parameters.
+type UpdateObservationsArgs struct {
+	Updates []ObservationUpdate `json:"updates" mcp:"observations to replace, each naming the entity and the old and new contents"`
+}
+
+// UpdateObservationsResult reports which updates were applied.
+type UpdateObservationsResult struct {
+	Updated  []ObservationUpdate `json:"updated"`
+	NotFound []ObservationUpdate `json:"notFound,omitempty"`
+}
+
+// RenameEntityArgs defines the rename entity tool parameters.
+type RenameEntityArgs struct {
+	Name    string `json:"name" mcp:"current name of the entity"`
+	NewName string `json:"newName" mcp:"new name, which must not belong to another entity"`
+}
+
+// RenameEntityResult reports the renamed entity and how many relations changed.
+type RenameEntityResult struct {
+	Name             string `json:"name"`
+	RelationsUpdated int    `json:"relationsUpdated"`
+}
+
+// MergeEntitiesArgs defines the merge entities tool parameters.
+type MergeEntitiesArgs struct {
+	Source string `json:"source" mcp:"entity to fold in and delete"`
+	Target string `json:"target" mcp:"entity that receives the source's observations and relations"`
+}
+
+// MergeEntitiesResult summarizes what a merge moved.
+type MergeEntitiesResult struct {
+	Target            string `json:"target"`
+	ObservationsAdded int    `json:"observationsAdded"`
+	RelationsRewired  int    `json:"relationsRewired"`
+	RelationsDropped  int    `json:"relationsDropped"`
+}
+
 // DeleteEntitiesArgs defines the delete entities tool parameters.
 type DeleteEntitiesArgs struct {
 	EntityNames []string `json:"entityNames" mcp:"entities to delete"`
@@ -66,6 +99,21 @@ type DeleteRelationsArgs struct {
 	Relations []Relation `json:"relations" mcp:"relations to delete"`
 }
 
+// ReadGraphArgs defines the read graph tool parameters.
+type ReadGraphArgs struct {
+	EntityType string `json:"entityType,omitempty" mcp:"only return entities of this type (optional)"`
+	Offset     int    `json:"offset,omitempty" mcp:"number of matching entities to skip (optional)"`
+	Limit      int    `json:"limit,omitempty" mcp:"maximum number of entities to return (optional, default all)"`
+}
+
+// ReadGraphResult returns a page of entities, the relations touching them,
+// and how many entities matched in total.
+type ReadGraphResult struct {
+	Entities  []Entity   `json:"entities"`
+	Relations []Relation `json:"relations"`
+	Total     int        `json:"total"`
+}
+
 // SearchNodesArgs defines the search nodes tool parameters.
 type SearchNodesArgs struct {
 	Query string `json:"query" mcp:"query string"`
@@ -76,77 +124,93 @@ type OpenNodesArgs struct {
 	Names []string `json:"names" mcp:"names of nodes to open"`
 }
 
-type IOTransport struct {
-	r *bufio.Reader
-	w io.Writer
+// GetNeighborsArgs defines the get neighbors tool parameters.
+type GetNeighborsArgs struct {
+	Name      string `json:"name" mcp:"entity to start from"`
+	Depth     int    `json:"depth,omitempty" mcp:"how many relations away to look (default 1, max 5)"`
+	Direction string `json:"direction,omitempty" mcp:"relations to follow: outgoing, incoming or both (default both)"`
 }
 
-func NewIOTransport(r io.Reader, w io.Writer) *IOTransport {
-	return &IOTransport{
-		r: bufio.NewReader(r),
-		w: w,
-	}
+// ExportGraphArgs defines the export graph tool parameters.
+type ExportGraphArgs struct {
+	Format string `json:"format,omitempty" mcp:"output format: dot or graphml (default dot)"`
 }
 
-type ioConn struct {
-	r *bufio.Reader
-	w io.Writer
+// FindPathArgs defines the find path tool parameters.
+type FindPathArgs struct {
+	From            string `json:"from" mcp:"entity to start from"`
+	To              string `json:"to" mcp:"entity to reach"`
+	MaxDepth        int    `json:"maxDepth,omitempty" mcp:"longest path to look for, in relations (default 6, max 10)"`
+	IgnoreDirection bool   `json:"ignoreDirection,omitempty" mcp:"also follow relations from their target to their source"`
 }
 
-func (t *IOTransport) Connect(ctx context.Context) (mcp.Connection, error) {
-	return &ioConn{
-		r: t.r,
-		w: t.w,
-	}, nil
+// FindPathResult returns the shortest path found, if any.
+type FindPathResult struct {
+	Found     bool       `json:"found"`
+	Entities  []string   `json:"entities,omitempty"`
+	Relations []Relation `json:"relations,omitempty"`
 }
 
-// problem with an import for decodemsg fnc so used json unmarshal
-func (t *ioConn) Read(context.Context) (jsonrpc.Message, error) {
-	data, err := t.r.ReadBytes('\n')
-	if err != nil {
-		return nil, err
-	}
+// generateUID creates a random hex string for UIDs
+func generateUID() string {
+	b := make([]byte, 4) // 8 character hex string
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
 
-	var msg jsonrpc.Message
-	err = json.Unmarshal(data[:len(data)-1], &msg)
+func main() {
+	client, err := NewAPIClientFromEnv()
 	if err != nil {
-		return nil, err
+		log.Fatalln("[ERROR]: Failed to configure Kubernetes API client:", err)
 	}
-	return msg, nil
-}
+	kubeAPI = client
+
+	sessions := NewSessionStore()
+	if dir := os.Getenv(ThinkingSessionsDirEnv); dir != "" {
+		sessions, err = NewPersistentSessionStore(dir)
+		if err != nil {
+			log.Fatalln("[ERROR]: Failed to load thinking sessions:", err)
+		}
+	}
+	defer func() {
+		if err := sessions.Close(); err != nil {
+			log.Println("[ERROR]: Failed to save thinking sessions:", err)
+		}
+	}()
 
-// problem with an import for encodemsg fnc so used json marshal
-func (t *ioConn) Write(_ context.Context, msg jsonrpc.Message) error {
-	data, err := json.Marshal(msg)
+	janitor, enabled, err := JanitorConfigFromEnv()
 	if err != nil {
-		return err
+		log.Fatalln("[ERROR]: Failed to configure thinking session expiry:", err)
+	}
+	if enabled {
+		stop := sessions.StartJanitor(janitor)
+		defer stop()
 	}
 
-	_, err1 := t.w.Write(data)
-	_, err2 := t.w.Write([]byte{'\n'})
-	return errors.Join(err1, err2)
-}
-
-func (t *ioConn) Close() error {
-	// We need to clean resources here before close
-	return nil
-}
-
-// constant session id for our local setup for now
-func (t *ioConn) SessionID() string {
-	return "kubernetes-1"
-}
+	kb := knowledgeBase{s: &memoryStore{}, mu: new(sync.RWMutex), history: newGraphHistory(defaultGraphHistorySize)}
+	if path := os.Getenv(MemoryFilePathEnv); path != "" {
+		kb.s = &fileStore{path: path}
+		if _, err := kb.loadGraph(); err != nil {
+			log.Fatalln("[ERROR]: Failed to load knowledge graph:", err)
+		}
+	}
 
-// generateUID creates a random hex string for UIDs
-func generateUID() string {
-	b := make([]byte, 4) // 8 character hex string
-	rand.Read(b)
-	return hex.EncodeToString(b)
+	server := newServer(sessions, kb)
+	if err := runServer(context.Background(), server); err != nil {
+		log.Println("[ERROR]: Failed to run server:", err)
+	}
 }
 
-func main() {
+// newServer creates the MCP server with every tool and resource registered,
+// backed by the given thinking sessions and knowledge base. Expiring idle
+// sessions is left to the caller, as only some stores support it.
+func newServer(sessions Store, kb knowledgeBase) *mcp.Server {
 	server := mcp.NewServer(&mcp.Implementation{Name: "kubernetes-uuid"}, nil)
 
+	// record every tool invocation for the audit_log tool and
+	// track in-flight calls for cancel_operation
+	server.AddReceivingMiddleware(auditLog.Middleware(), operations.Middleware())
+
 	// kubernetes API tools
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "create_pod",
@@ -168,26 +232,141 @@ func main() {
 		Description: "Delete a pod by UID",
 	}, DeletePod)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "apply_manifest",
+		Description: "Create Pods, Services, Deployments or ConfigMaps from a raw YAML or JSON manifest. Multi-document bundles such as export_resources output are applied in order, and each object is labelled with a generated UID",
+	}, ApplyManifest)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_pods_batch",
+		Description: "Create several pods in one call. Each pod succeeds or fails independently and gets its own UID",
+	}, CreatePodsBatch)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "reconcile_pods",
+		Description: "Make the managed pods match a desired set by name: create missing pods and delete all others. Use dry_run to preview",
+	}, ReconcilePods)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "pod_operation",
+		Description: "Restart or delete a pod by UID, or stop/start the deployment that owns it",
+	}, PodOperation)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "get_pod_logs",
 		Description: "Get logs from a specific pod",
 	}, GetPodLogs)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "container_restart_info",
+		Description: "Get restart counts and last termination reasons for each container in a pod",
+	}, ContainerRestartInfo)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "why_pending",
+		Description: "Explain why a pod has not been scheduled from its scheduling condition and events",
+	}, WhyPending)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_pod_events",
+		Description: "List the events of a pod, such as FailedScheduling or image pull errors, oldest first",
+	}, GetPodEvents)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_pod_metrics",
+		Description: "Get the current CPU and memory usage of each container in a pod (requires metrics-server)",
+	}, GetPodMetrics)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_pod_env",
+		Description: "Set environment variables on a pod by recreating it with the same UID. Pods in system namespaces or owned by a controller are rejected",
+	}, UpdatePodEnv)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "create_service",
 		Description: "Create a service linked to a pod",
 	}, CreateService)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_configmap",
+		Description: "Create a config map whose UID can be passed to create_pod to mount it or inject it as environment variables",
+	}, CreateConfigMap)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_configmap",
+		Description: "Get a config map and its data by UID",
+	}, GetConfigMap)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_secret",
+		Description: "Store sensitive values in a secret whose UID can be passed to create_pod as secret_env; the values are never returned",
+	}, CreateSecret)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "list_services",
 		Description: "List all services managed by the API",
 	}, ListServices)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_service",
+		Description: "Delete a service by UID",
+	}, DeleteService)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "probe_service",
+		Description: "Send an HTTP GET to a service from inside the cluster and report status code and latency",
+	}, ProbeService)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "export_resources",
+		Description: "Export the managed pods, services and deployments of a namespace, or of all namespaces, as a multi-document YAML bundle that apply_manifest accepts",
+	}, ExportResources)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_pod_labels",
+		Description: "Add, change or remove labels on a pod in place, e.g. to re-target services. The uid label is protected",
+	}, UpdatePodLabels)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "exec_in_pod",
+		Description: "Run a command in a pod container, like kubectl exec, and return stdout, stderr and the exit code",
+	}, ExecInPod)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "port_forward",
+		Description: "Forward a local port on the Kubernetes API host to a pod port for a limited time, returning the address to connect to",
+	}, PortForward)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "export_pod",
+		Description: "Get a pod's manifest as YAML or JSON, including defaults filled in by Kubernetes, without status or server-managed fields",
+	}, ExportPod)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "scale_deployment",
+		Description: "Change the number of replicas of a deployment by UID",
+	}, ScaleDeployment)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "get_cluster_info",
 		Description: "Get cluster status and node information",
 	}, GetClusterInfo)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_node",
+		Description: "Get a node's allocatable CPU/memory, conditions, kubelet version and schedulability",
+	}, GetNode)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "cluster_capabilities",
+		Description: "Discover the cluster's API versions and optional features such as metrics and ephemeral containers",
+	}, ClusterCapabilities)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "recent_activity",
+		Description: "List the pods, services and deployments recently created or deleted through the API, newest first",
+	}, RecentActivity)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "health_check",
 		Description: "Check the health status of the Kubernetes API",
@@ -206,36 +385,100 @@ func main() {
 		}, nil
 	})
 
+	// audit log
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "audit_log",
+		Description: "List the tools invoked in this MCP session with their arguments and outcome",
+	}, AuditLogQuery)
+
+	// in-flight operations
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_operations",
+		Description: "List this session's tool calls that are currently in flight",
+	}, ListOperations)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "cancel_operation",
+		Description: "Cancel one of this session's in-flight tool calls by its operation ID",
+	}, CancelOperation)
+
 	// sequential thinking
+	thinking := sequentialThinking{store: sessions}
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "start_thinking",
 		Description: "Begin a new sequential thinking session for a complex problem",
 	}, func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[StartThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
-		return StartThinking(ctx, ss, params)
+		return thinking.StartThinking(ctx, ss, params)
 	})
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "continue_thinking",
-		Description: "Add the next thought step, revise a previous step, or create a branch",
+		Description: "Add the next thought step, revise a previous step, or create a branch (optionally from an earlier step with fromStep)",
 	}, func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ContinueThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
-		return ContinueThinking(ctx, ss, params)
+		return thinking.ContinueThinking(ctx, ss, params)
 	})
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "review_thinking",
 		Description: "Review the complete thinking process for a session",
 	}, func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ReviewThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
-		return ReviewThinking(ctx, ss, params)
+		return thinking.ReviewThinking(ctx, ss, params)
 	})
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_thought",
+		Description: "Delete a mistaken thought from a session; later thoughts are renumbered",
+	}, thinking.DeleteThought)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "complete_thinking",
+		Description: "Mark a thinking session completed and record its conclusion",
+	}, thinking.CompleteThinking)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "undo_revision",
+		Description: "Restore the wording a thought had before its most recent revision",
+	}, thinking.UndoRevision)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "pause_thinking",
+		Description: "Pause an active thinking session so it accepts no thoughts until resumed",
+	}, thinking.PauseThinking)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "resume_thinking",
+		Description: "Resume a paused thinking session",
+	}, thinking.ResumeThinking)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "thinking_stats",
+		Description: "Show a thinking session's duration, average time between thoughts and the gap before each thought",
+	}, thinking.ThinkingStats)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search_thinking",
+		Description: "Search the thoughts of every thinking session for a word or phrase, ranked by number of hits",
+	}, thinking.SearchThinking)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "merge_branch",
+		Description: "Append the thoughts a branch added after its fork point to its parent session",
+	}, thinking.MergeBranch)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "thinking_graph",
+		Description: "Render a thinking session and its branches as a Graphviz DOT graph",
+	}, thinking.ThinkingGraph)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "snapshot_thinking",
+		Description: "Save every thinking session as a JSON bundle that can be restored later",
+	}, thinking.SnapshotThinking)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "restore_thinking",
+		Description: "Restore thinking sessions from a snapshot bundle, merging into or replacing the store",
+	}, thinking.RestoreThinking)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_thinking_session",
+		Description: "Delete a thinking session and every branch created from it",
+	}, thinking.DeleteThinking)
 	server.AddResource(&mcp.Resource{
 		Name:        "thinking_sessions",
 		Description: "Access thinking session data and history",
 		URI:         "thinking://sessions",
 		MIMEType:    "application/json",
 	}, func(ctx context.Context, ss *mcp.ServerSession, params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
-		return ThinkingHistory(ctx, ss, params)
+		return thinking.ThinkingHistory(ctx, ss, params)
 	})
 
 	// Memory Store
-	kb := knowledgeBase{s: &memoryStore{}}
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "create_entities",
 		Description: "Create multiple new entities in the knowledge graph",
@@ -248,6 +491,18 @@ func main() {
 		Name:        "add_observations",
 		Description: "Add new observations to existing entities",
 	}, kb.AddObservations)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_observations",
+		Description: "Replace existing observations of entities with new contents, keeping their order",
+	}, kb.UpdateObservations)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "rename_entity",
+		Description: "Rename an entity and update every relation that refers to it",
+	}, kb.RenameEntity)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "merge_entities",
+		Description: "Fold a duplicate entity into another, combining observations and relations, then delete it",
+	}, kb.MergeEntities)
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "delete_entities",
 		Description: "Remove entities and their relations",
@@ -262,7 +517,7 @@ func main() {
 	}, kb.DeleteRelations)
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "read_graph",
-		Description: "Read the entire knowledge graph",
+		Description: "Read the knowledge graph, optionally paginated with offset/limit and filtered by entityType",
 	}, kb.ReadGraph)
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "search_nodes",
@@ -272,13 +527,22 @@ func main() {
 		Name:        "open_nodes",
 		Description: "Retrieve specific nodes by name",
 	}, kb.OpenNodes)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "graph_history",
+		Description: "List recent changes to the knowledge graph: entities, observations and relations created, updated or deleted",
+	}, kb.GraphHistory)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_neighbors",
+		Description: "Get the entities connected to an entity through relations, up to a depth, with the relations between them",
+	}, kb.GetNeighbors)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "find_path",
+		Description: "Find the shortest chain of relations connecting two entities",
+	}, kb.FindPath)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "export_graph",
+		Description: "Export the knowledge graph as Graphviz DOT or GraphML for visualization in external tools",
+	}, kb.ExportGraph)
 
-	transport := &IOTransport{
-		r: bufio.NewReader(os.Stdin),
-		w: os.Stdout,
-	}
-	err := server.Run(context.Background(), transport)
-	if err != nil {
-		log.Println("[ERROR]: Failed to run server:", err)
-	}
+	return server
 }
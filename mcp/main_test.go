@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestKubernetesToolsRegistered(t *testing.T) {
+	httpServer := httptest.NewServer(newHTTPHandler(newTestServer()))
+	defer httpServer.Close()
+
+	cs := connectHTTP(t, httpServer.URL)
+	res, err := cs.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools() failed: %v", err)
+	}
+	registered := make(map[string]bool)
+	for _, tool := range res.Tools {
+		registered[tool.Name] = true
+	}
+
+	for _, name := range []string{
+		"create_pod", "get_pod", "list_pods", "delete_pod", "get_pod_logs",
+		"create_service", "list_services", "get_cluster_info", "health_check",
+	} {
+		if !registered[name] {
+			t.Errorf("tool %s is not registered", name)
+		}
+	}
+}
+
+// mockStore is a Store that records which of its methods the tools call,
+// keeping sessions in an in-memory SessionStore.
+type mockStore struct {
+	sessions *SessionStore
+	setErr   error // returned by SetSession when set
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{sessions: NewSessionStore()}
+}
+
+func (m *mockStore) record(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, method)
+}
+
+// takeCalls returns the calls recorded since it was last called.
+func (m *mockStore) takeCalls() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls := m.calls
+	m.calls = nil
+	return calls
+}
+
+func (m *mockStore) Session(id string) (*ThinkingSession, bool) {
+	m.record("Session")
+	return m.sessions.Session(id)
+}
+
+func (m *mockStore) SetSession(session *ThinkingSession) error {
+	m.record("SetSession")
+	if m.setErr != nil {
+		return m.setErr
+	}
+	return m.sessions.SetSession(session)
+}
+
+func (m *mockStore) CompareAndSwap(sessionID string, updateFunc func(*ThinkingSession) (*ThinkingSession, error)) error {
+	m.record("CompareAndSwap")
+	return m.sessions.CompareAndSwap(sessionID, updateFunc)
+}
+
+func (m *mockStore) SessionSnapshot(id string) (*ThinkingSession, bool) {
+	m.record("SessionSnapshot")
+	return m.sessions.SessionSnapshot(id)
+}
+
+func (m *mockStore) SessionsSnapshot() []*ThinkingSession {
+	m.record("SessionsSnapshot")
+	return m.sessions.SessionsSnapshot()
+}
+
+func (m *mockStore) DeleteSession(id string) bool {
+	m.record("DeleteSession")
+	return m.sessions.DeleteSession(id)
+}
+
+func (m *mockStore) ReplaceSessions(sessions []*ThinkingSession) error {
+	m.record("ReplaceSessions")
+	return m.sessions.ReplaceSessions(sessions)
+}
+
+func TestThinkingToolsUseStore(t *testing.T) {
+	store := newMockStore()
+	httpServer := httptest.NewServer(newHTTPHandler(newServer(store, newTestKnowledgeBase())))
+	defer httpServer.Close()
+	cs := connectHTTP(t, httpServer.URL)
+
+	tests := []struct {
+		tool string
+		args map[string]any
+		want []string
+	}{
+		{"start_thinking", map[string]any{"sessionId": "s1", "problem": "plan a rollout"}, []string{"SetSession"}},
+		{"continue_thinking", map[string]any{"sessionId": "s1", "thought": "list the services"}, []string{"CompareAndSwap"}},
+		{"review_thinking", map[string]any{"sessionId": "s1"}, []string{"SessionSnapshot"}},
+		{"search_thinking", map[string]any{"query": "services"}, []string{"SessionsSnapshot"}},
+		{"snapshot_thinking", map[string]any{}, []string{"SessionsSnapshot"}},
+		{"delete_thinking_session", map[string]any{"sessionId": "s1"}, []string{"DeleteSession", "SessionsSnapshot"}},
+	}
+	for _, tt := range tests {
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: tt.tool, Arguments: tt.args})
+		if err != nil {
+			t.Fatalf("CallTool(%s) failed: %v", tt.tool, err)
+		}
+		if res.IsError {
+			t.Fatalf("%s returned an error result: %s", tt.tool, toolText(t, res))
+		}
+		if calls := store.takeCalls(); !slices.Equal(calls, tt.want) {
+			t.Errorf("%s called %v, want %v", tt.tool, calls, tt.want)
+		}
+	}
+
+	if _, ok := store.sessions.Session("s1"); ok {
+		t.Error("session s1 is still in the store after delete_thinking_session")
+	}
+}
+
+func TestThinkingToolsReportStoreErrors(t *testing.T) {
+	store := newMockStore()
+	store.setErr = errors.New("disk full")
+	httpServer := httptest.NewServer(newHTTPHandler(newServer(store, newTestKnowledgeBase())))
+	defer httpServer.Close()
+	cs := connectHTTP(t, httpServer.URL)
+
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "start_thinking",
+		Arguments: map[string]any{"sessionId": "s1", "problem": "plan a rollout"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool(start_thinking) failed: %v", err)
+	}
+	if !res.IsError || !strings.Contains(toolText(t, res), "disk full") {
+		t.Errorf("start_thinking = %q, want an error result carrying the store's error", toolText(t, res))
+	}
+	if _, ok := store.sessions.Session("s1"); ok {
+		t.Error("session s1 was stored although SetSession failed")
+	}
+}
@@ -7,10 +7,15 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -20,6 +25,24 @@ type Entity struct {
 	Name         string   `json:"name"`
 	EntityType   string   `json:"entityType"`
 	Observations []string `json:"observations"`
+
+	// Set by the knowledge base; ignored on input
+	Created          *time.Time           `json:"created,omitempty"`
+	Updated          *time.Time           `json:"updated,omitempty"`
+	ObservationTimes map[string]time.Time `json:"observationTimes,omitempty"` // when each observation was added or last updated
+}
+
+// touch marks the entity as updated at now.
+func (e *Entity) touch(now time.Time) {
+	e.Updated = &now
+}
+
+// stampObservation records that observation was added or updated at now.
+func (e *Entity) stampObservation(observation string, now time.Time) {
+	if e.ObservationTimes == nil {
+		e.ObservationTimes = make(map[string]time.Time)
+	}
+	e.ObservationTimes[observation] = now
 }
 
 // Relation represents a directed edge between two entities.
@@ -37,12 +60,23 @@ type Observation struct {
 	Observations []string `json:"observations,omitempty"` // Used for deletion operations
 }
 
+// ObservationUpdate replaces one observation of an entity with new content.
+type ObservationUpdate struct {
+	EntityName string `json:"entityName"`
+	Old        string `json:"old"`
+	New        string `json:"new"`
+}
+
 // KnowledgeGraph represents the complete graph structure.
 type KnowledgeGraph struct {
 	Entities  []Entity   `json:"entities"`
 	Relations []Relation `json:"relations"`
 }
 
+// MemoryFilePathEnv names the environment variable holding the file the
+// knowledge graph is persisted to. When unset, the graph lives in memory only.
+const MemoryFilePathEnv = "MEMORY_FILE_PATH"
+
 // store provides persistence interface for knowledge base data.
 type store interface {
 	Read() ([]byte, error)
@@ -82,17 +116,46 @@ func (fs *fileStore) Read() ([]byte, error) {
 	return data, nil
 }
 
-// Write saves data to file with 0600 permissions.
+// Write saves data to file with 0600 permissions. The data goes to a
+// temporary file that is renamed over the old one, so a crash mid-write
+// leaves the previous graph intact.
 func (fs *fileStore) Write(data []byte) error {
-	if err := os.WriteFile(fs.path, data, 0600); err != nil {
+	dir := filepath.Dir(fs.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", fs.path, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(fs.path)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write file %s: %w", fs.path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file %s: %w", fs.path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file %s: %w", fs.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", fs.path, err)
+	}
+	if err := os.Rename(tmp.Name(), fs.path); err != nil {
 		return fmt.Errorf("failed to write file %s: %w", fs.path, err)
 	}
 	return nil
 }
 
 // knowledgeBase manages entities and relations with persistent storage.
+// Tool calls may run concurrently, so every load-modify-save cycle holds mu
+// for writing and every read holds it for reading; the store itself is only
+// accessed under mu.
 type knowledgeBase struct {
-	s store
+	s       store
+	mu      *sync.RWMutex
+	history *graphHistory // nil keeps no history
 }
 
 // kbItem represents a single item in persistent storage (entity or relation).
@@ -100,9 +163,12 @@ type kbItem struct {
 	Type string `json:"type"`
 
 	// Entity fields (when Type == "entity")
-	Name         string   `json:"name,omitempty"`
-	EntityType   string   `json:"entityType,omitempty"`
-	Observations []string `json:"observations,omitempty"`
+	Name             string               `json:"name,omitempty"`
+	EntityType       string               `json:"entityType,omitempty"`
+	Observations     []string             `json:"observations,omitempty"`
+	Created          *time.Time           `json:"created,omitempty"`
+	Updated          *time.Time           `json:"updated,omitempty"`
+	ObservationTimes map[string]time.Time `json:"observationTimes,omitempty"`
 
 	// Relation fields (when Type == "relation")
 	From         string `json:"from,omitempty"`
@@ -132,9 +198,12 @@ func (k knowledgeBase) loadGraph() (KnowledgeGraph, error) {
 		switch item.Type {
 		case "entity":
 			graph.Entities = append(graph.Entities, Entity{
-				Name:         item.Name,
-				EntityType:   item.EntityType,
-				Observations: item.Observations,
+				Name:             item.Name,
+				EntityType:       item.EntityType,
+				Observations:     item.Observations,
+				Created:          item.Created,
+				Updated:          item.Updated,
+				ObservationTimes: item.ObservationTimes,
 			})
 		case "relation":
 			graph.Relations = append(graph.Relations, Relation{
@@ -154,10 +223,13 @@ func (k knowledgeBase) saveGraph(graph KnowledgeGraph) error {
 
 	for _, entity := range graph.Entities {
 		items = append(items, kbItem{
-			Type:         "entity",
-			Name:         entity.Name,
-			EntityType:   entity.EntityType,
-			Observations: entity.Observations,
+			Type:             "entity",
+			Name:             entity.Name,
+			EntityType:       entity.EntityType,
+			Observations:     entity.Observations,
+			Created:          entity.Created,
+			Updated:          entity.Updated,
+			ObservationTimes: entity.ObservationTimes,
 		})
 	}
 
@@ -184,22 +256,35 @@ func (k knowledgeBase) saveGraph(graph KnowledgeGraph) error {
 // createEntities adds new entities to the graph, skipping duplicates by name.
 // It returns the new entities that were actually added.
 func (k knowledgeBase) createEntities(entities []Entity) ([]Entity, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	graph, err := k.loadGraph()
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
 	var newEntities []Entity
+	var changes []GraphChange
 	for _, entity := range entities {
 		if !slices.ContainsFunc(graph.Entities, func(e Entity) bool { return e.Name == entity.Name }) {
+			entity.Created = &now
+			entity.Updated = &now
+			entity.ObservationTimes = nil
+			for _, observation := range entity.Observations {
+				entity.stampObservation(observation, now)
+			}
 			newEntities = append(newEntities, entity)
 			graph.Entities = append(graph.Entities, entity)
+			changes = append(changes, GraphChange{Action: graphActionCreateEntity, Entity: entity.Name, Detail: entity.EntityType})
 		}
 	}
 
 	if err := k.saveGraph(graph); err != nil {
 		return nil, err
 	}
+	k.record(changes...)
 
 	return newEntities, nil
 }
@@ -207,6 +292,9 @@ func (k knowledgeBase) createEntities(entities []Entity) ([]Entity, error) {
 // createRelations adds new relations to the graph, skipping exact duplicates.
 // It returns the new relations that were actually added.
 func (k knowledgeBase) createRelations(relations []Relation) ([]Relation, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	graph, err := k.loadGraph()
 	if err != nil {
 		return nil, err
@@ -228,33 +316,50 @@ func (k knowledgeBase) createRelations(relations []Relation) ([]Relation, error)
 	if err := k.saveGraph(graph); err != nil {
 		return nil, err
 	}
+	for _, relation := range newRelations {
+		k.record(GraphChange{Action: graphActionCreateRelation, Entity: relation.From, Detail: relationDetail(relation)})
+	}
 
 	return newRelations, nil
 }
 
 // addObservations appends new observations to existing entities.
-// It returns the new observations that were actually added.
-func (k knowledgeBase) addObservations(observations []Observation) ([]Observation, error) {
+// It returns the new observations that were actually added, and the names of
+// entities that could not be found (whose observations were skipped).
+func (k knowledgeBase) addObservations(observations []Observation) ([]Observation, []string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	graph, err := k.loadGraph()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	now := time.Now()
 	var results []Observation
+	var missing []string
+	var changes []GraphChange
 
 	for _, obs := range observations {
 		entityIndex := slices.IndexFunc(graph.Entities, func(e Entity) bool { return e.Name == obs.EntityName })
 		if entityIndex == -1 {
-			return nil, fmt.Errorf("entity with name %s not found", obs.EntityName)
+			missing = append(missing, obs.EntityName)
+			continue
 		}
 
+		entity := &graph.Entities[entityIndex]
 		var newObservations []string
 		for _, content := range obs.Contents {
-			if !slices.Contains(graph.Entities[entityIndex].Observations, content) {
+			if !slices.Contains(entity.Observations, content) {
 				newObservations = append(newObservations, content)
-				graph.Entities[entityIndex].Observations = append(graph.Entities[entityIndex].Observations, content)
+				entity.Observations = append(entity.Observations, content)
+				entity.stampObservation(content, now)
+				changes = append(changes, GraphChange{Action: graphActionAddObservation, Entity: entity.Name, Detail: content})
 			}
 		}
+		if len(newObservations) > 0 {
+			entity.touch(now)
+		}
 
 		results = append(results, Observation{
 			EntityName: obs.EntityName,
@@ -263,14 +368,212 @@ func (k knowledgeBase) addObservations(observations []Observation) ([]Observatio
 	}
 
 	if err := k.saveGraph(graph); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	k.record(changes...)
+
+	return results, missing, nil
+}
+
+// updateObservations replaces observation contents in place, keeping the order
+// of the others. It returns the updates that were applied and those whose
+// entity or old observation could not be found. All updates are saved at once.
+func (k knowledgeBase) updateObservations(updates []ObservationUpdate) ([]ObservationUpdate, []ObservationUpdate, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	graph, err := k.loadGraph()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	var updated, notFound []ObservationUpdate
+	for _, update := range updates {
+		entityIndex := slices.IndexFunc(graph.Entities, func(e Entity) bool { return e.Name == update.EntityName })
+		if entityIndex == -1 {
+			notFound = append(notFound, update)
+			continue
+		}
+		observations := graph.Entities[entityIndex].Observations
+		i := slices.Index(observations, update.Old)
+		if i == -1 {
+			notFound = append(notFound, update)
+			continue
+		}
+
+		// Replacing with an observation the entity already has would duplicate it
+		entity := &graph.Entities[entityIndex]
+		if update.New != update.Old && slices.Contains(observations, update.New) {
+			observations = slices.Delete(observations, i, i+1)
+		} else {
+			observations[i] = update.New
+		}
+		entity.Observations = observations
+		delete(entity.ObservationTimes, update.Old)
+		entity.stampObservation(update.New, now)
+		entity.touch(now)
+		updated = append(updated, update)
+	}
+
+	if len(updated) > 0 {
+		if err := k.saveGraph(graph); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, update := range updated {
+		k.record(GraphChange{Action: graphActionUpdateObservation, Entity: update.EntityName, Detail: fmt.Sprintf("%q -> %q", update.Old, update.New)})
+	}
+
+	return updated, notFound, nil
+}
+
+// errEntityNotFound and errEntityExists report a missing or clashing entity name.
+var (
+	errEntityNotFound = errors.New("entity not found")
+	errEntityExists   = errors.New("entity already exists")
+)
+
+// renameEntity renames an entity and every relation endpoint naming it,
+// returning how many relations changed. Nothing is saved on error.
+func (k knowledgeBase) renameEntity(oldName, newName string) (int, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	graph, err := k.loadGraph()
+	if err != nil {
+		return 0, err
+	}
+
+	entityIndex := slices.IndexFunc(graph.Entities, func(e Entity) bool { return e.Name == oldName })
+	if entityIndex == -1 {
+		return 0, fmt.Errorf("%w: %s", errEntityNotFound, oldName)
+	}
+	if slices.ContainsFunc(graph.Entities, func(e Entity) bool { return e.Name == newName }) {
+		return 0, fmt.Errorf("%w: %s", errEntityExists, newName)
+	}
+	graph.Entities[entityIndex].Name = newName
+	graph.Entities[entityIndex].touch(time.Now())
+
+	updated := 0
+	for i, relation := range graph.Relations {
+		if relation.From != oldName && relation.To != oldName {
+			continue
+		}
+		if relation.From == oldName {
+			graph.Relations[i].From = newName
+		}
+		if relation.To == oldName {
+			graph.Relations[i].To = newName
+		}
+		updated++
+	}
+	graph.Relations = dedupeRelations(graph.Relations)
+
+	if err := k.saveGraph(graph); err != nil {
+		return 0, err
+	}
+	k.record(GraphChange{Action: graphActionRenameEntity, Entity: newName, Detail: "renamed from " + oldName})
+	return updated, nil
+}
+
+// mergeEntities folds source into target: target gains source's observations
+// it lacks, source's relations are moved onto target, and source is deleted.
+// Relations that would become self-loops on target or duplicate an existing
+// relation are dropped. Nothing is saved on error.
+func (k knowledgeBase) mergeEntities(source, target string) (MergeEntitiesResult, error) {
+	result := MergeEntitiesResult{Target: target}
+	if source == target {
+		return result, fmt.Errorf("cannot merge %s into itself", source)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	graph, err := k.loadGraph()
+	if err != nil {
+		return result, err
+	}
+
+	sourceIndex := slices.IndexFunc(graph.Entities, func(e Entity) bool { return e.Name == source })
+	if sourceIndex == -1 {
+		return result, fmt.Errorf("%w: %s", errEntityNotFound, source)
+	}
+	targetIndex := slices.IndexFunc(graph.Entities, func(e Entity) bool { return e.Name == target })
+	if targetIndex == -1 {
+		return result, fmt.Errorf("%w: %s", errEntityNotFound, target)
+	}
+
+	now := time.Now()
+	targetEntity := &graph.Entities[targetIndex]
+	for _, observation := range graph.Entities[sourceIndex].Observations {
+		if !slices.Contains(targetEntity.Observations, observation) {
+			targetEntity.Observations = append(targetEntity.Observations, observation)
+			// Keep when the observation was originally recorded, if known
+			added, known := graph.Entities[sourceIndex].ObservationTimes[observation]
+			if !known {
+				added = now
+			}
+			targetEntity.stampObservation(observation, added)
+			result.ObservationsAdded++
+		}
 	}
+	targetEntity.touch(now)
+	graph.Entities = slices.Delete(graph.Entities, sourceIndex, sourceIndex+1)
 
-	return results, nil
+	// Keep the relations that don't involve source, so rewired ones are
+	// recognised as duplicates of them
+	kept := slices.DeleteFunc(slices.Clone(graph.Relations), func(relation Relation) bool {
+		return relation.From == source || relation.To == source
+	})
+	for _, relation := range graph.Relations {
+		if relation.From != source && relation.To != source {
+			continue
+		}
+		if relation.From == source {
+			relation.From = target
+		}
+		if relation.To == source {
+			relation.To = target
+		}
+		if relation.From == target && relation.To == target || slices.Contains(kept, relation) {
+			result.RelationsDropped++
+			continue
+		}
+		kept = append(kept, relation)
+		result.RelationsRewired++
+	}
+	graph.Relations = kept
+
+	if err := k.saveGraph(graph); err != nil {
+		return result, err
+	}
+	k.record(GraphChange{Action: graphActionMergeEntities, Entity: target, Detail: "merged in " + source})
+	return result, nil
+}
+
+// relationDetail describes a relation for the graph history.
+func relationDetail(relation Relation) string {
+	return fmt.Sprintf("%s -%s-> %s", relation.From, relation.RelationType, relation.To)
+}
+
+// dedupeRelations drops repeated relations, keeping the first of each.
+func dedupeRelations(relations []Relation) []Relation {
+	seen := make(map[Relation]bool)
+	return slices.DeleteFunc(relations, func(relation Relation) bool {
+		if seen[relation] {
+			return true
+		}
+		seen[relation] = true
+		return false
+	})
 }
 
 // deleteEntities removes entities and their associated relations.
 func (k knowledgeBase) deleteEntities(entityNames []string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	graph, err := k.loadGraph()
 	if err != nil {
 		return err
@@ -283,8 +586,13 @@ func (k knowledgeBase) deleteEntities(entityNames []string) error {
 	}
 
 	// Filter entities using slices.DeleteFunc
+	var changes []GraphChange
 	graph.Entities = slices.DeleteFunc(graph.Entities, func(entity Entity) bool {
-		return entitiesToDelete[entity.Name]
+		if entitiesToDelete[entity.Name] {
+			changes = append(changes, GraphChange{Action: graphActionDeleteEntity, Entity: entity.Name})
+			return true
+		}
+		return false
 	})
 
 	// Filter relations using slices.DeleteFunc
@@ -292,16 +600,25 @@ func (k knowledgeBase) deleteEntities(entityNames []string) error {
 		return entitiesToDelete[relation.From] || entitiesToDelete[relation.To]
 	})
 
-	return k.saveGraph(graph)
+	if err := k.saveGraph(graph); err != nil {
+		return err
+	}
+	k.record(changes...)
+	return nil
 }
 
 // deleteObservations removes specific observations from entities.
 func (k knowledgeBase) deleteObservations(deletions []Observation) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	graph, err := k.loadGraph()
 	if err != nil {
 		return err
 	}
 
+	now := time.Now()
+	var changes []GraphChange
 	for _, deletion := range deletions {
 		entityIndex := slices.IndexFunc(graph.Entities, func(e Entity) bool {
 			return e.Name == deletion.EntityName
@@ -309,6 +626,7 @@ func (k knowledgeBase) deleteObservations(deletions []Observation) error {
 		if entityIndex == -1 {
 			continue
 		}
+		before := len(changes)
 
 		// Create a map for quick lookup
 		observationsToDelete := make(map[string]bool)
@@ -317,34 +635,63 @@ func (k knowledgeBase) deleteObservations(deletions []Observation) error {
 		}
 
 		// Filter observations using slices.DeleteFunc
-		graph.Entities[entityIndex].Observations = slices.DeleteFunc(graph.Entities[entityIndex].Observations, func(observation string) bool {
-			return observationsToDelete[observation]
+		entity := &graph.Entities[entityIndex]
+		entity.Observations = slices.DeleteFunc(entity.Observations, func(observation string) bool {
+			if observationsToDelete[observation] {
+				delete(entity.ObservationTimes, observation)
+				changes = append(changes, GraphChange{Action: graphActionDeleteObservation, Entity: entity.Name, Detail: observation})
+				return true
+			}
+			return false
 		})
+		if len(changes) > before {
+			entity.touch(now)
+		}
 	}
 
-	return k.saveGraph(graph)
+	if err := k.saveGraph(graph); err != nil {
+		return err
+	}
+	k.record(changes...)
+	return nil
 }
 
 // deleteRelations removes specific relations from the graph.
 func (k knowledgeBase) deleteRelations(relations []Relation) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	graph, err := k.loadGraph()
 	if err != nil {
 		return err
 	}
 
 	// Filter relations using slices.DeleteFunc and slices.ContainsFunc
+	var changes []GraphChange
 	graph.Relations = slices.DeleteFunc(graph.Relations, func(existingRelation Relation) bool {
-		return slices.ContainsFunc(relations, func(relationToDelete Relation) bool {
+		deleted := slices.ContainsFunc(relations, func(relationToDelete Relation) bool {
 			return existingRelation.From == relationToDelete.From &&
 				existingRelation.To == relationToDelete.To &&
 				existingRelation.RelationType == relationToDelete.RelationType
 		})
+		if deleted {
+			changes = append(changes, GraphChange{Action: graphActionDeleteRelation, Entity: existingRelation.From, Detail: relationDetail(existingRelation)})
+		}
+		return deleted
 	})
-	return k.saveGraph(graph)
+
+	if err := k.saveGraph(graph); err != nil {
+		return err
+	}
+	k.record(changes...)
+	return nil
 }
 
 // searchNodes filters entities and relations matching the query string.
 func (k knowledgeBase) searchNodes(query string) (KnowledgeGraph, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
 	graph, err := k.loadGraph()
 	if err != nil {
 		return KnowledgeGraph{}, err
@@ -392,6 +739,9 @@ func (k knowledgeBase) searchNodes(query string) (KnowledgeGraph, error) {
 
 // openNodes returns entities with specified names and their interconnecting relations.
 func (k knowledgeBase) openNodes(names []string) (KnowledgeGraph, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
 	graph, err := k.loadGraph()
 	if err != nil {
 		return KnowledgeGraph{}, err
@@ -431,16 +781,325 @@ func (k knowledgeBase) openNodes(names []string) (KnowledgeGraph, error) {
 	}, nil
 }
 
+// Directions a neighbor traversal can follow relations in.
+const (
+	directionOutgoing = "outgoing"
+	directionIncoming = "incoming"
+	directionBoth     = "both"
+)
+
+// maxNeighborDepth caps how many relations away get_neighbors looks.
+const maxNeighborDepth = 5
+
+// neighbors returns the entities reachable from name within depth relations,
+// following relations in direction, together with the relations followed.
+// The second return value is false if there is no entity called name.
+func (k knowledgeBase) neighbors(name string, depth int, direction string) (KnowledgeGraph, bool, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	graph, err := k.loadGraph()
+	if err != nil {
+		return KnowledgeGraph{}, false, err
+	}
+	if !slices.ContainsFunc(graph.Entities, func(e Entity) bool { return e.Name == name }) {
+		return KnowledgeGraph{}, false, nil
+	}
+
+	reached := map[string]bool{name: true}
+	followed := make([]bool, len(graph.Relations))
+	frontier := []string{name}
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		var next []string
+		for _, current := range frontier {
+			for i, relation := range graph.Relations {
+				var other string
+				switch {
+				case direction != directionIncoming && relation.From == current:
+					other = relation.To
+				case direction != directionOutgoing && relation.To == current:
+					other = relation.From
+				default:
+					continue
+				}
+				followed[i] = true
+				if !reached[other] {
+					reached[other] = true
+					next = append(next, other)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	var result KnowledgeGraph
+	for _, entity := range graph.Entities {
+		if reached[entity.Name] {
+			result.Entities = append(result.Entities, entity)
+		}
+	}
+	for i, relation := range graph.Relations {
+		if followed[i] {
+			result.Relations = append(result.Relations, relation)
+		}
+	}
+	return result, true, nil
+}
+
+// readGraph returns a page of the graph: up to limit entities (all if limit
+// is 0) of entityType (any if empty), starting at offset, with the relations
+// touching them. Total counts every entity matching entityType. When the page
+// holds the whole unfiltered graph, every relation is returned.
+func (k knowledgeBase) readGraph(entityType string, offset, limit int) (ReadGraphResult, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	graph, err := k.loadGraph()
+	if err != nil {
+		return ReadGraphResult{}, err
+	}
+
+	entities := graph.Entities
+	if entityType != "" {
+		entities = slices.DeleteFunc(slices.Clone(entities), func(e Entity) bool { return e.EntityType != entityType })
+	}
+	result := ReadGraphResult{Total: len(entities)}
+
+	entities = entities[min(offset, len(entities)):]
+	if limit > 0 && limit < len(entities) {
+		entities = entities[:limit]
+	}
+	result.Entities = entities
+
+	if entityType == "" && len(entities) == len(graph.Entities) {
+		result.Relations = graph.Relations
+		return result, nil
+	}
+	names := make(map[string]bool)
+	for _, entity := range entities {
+		names[entity.Name] = true
+	}
+	for _, relation := range graph.Relations {
+		if names[relation.From] || names[relation.To] {
+			result.Relations = append(result.Relations, relation)
+		}
+	}
+	return result, nil
+}
+
+const (
+	defaultPathDepth = 6
+	// maxPathDepth caps how many relations find_path searches through.
+	maxPathDepth = 10
+)
+
+// findPath searches breadth-first for the shortest chain of at most maxDepth
+// relations leading from one entity to another, following relations against
+// their direction too if undirected is set. It returns the entities along the
+// path and the relations linking them, or nil if there is no such path.
+func (k knowledgeBase) findPath(from, to string, maxDepth int, undirected bool) ([]string, []Relation, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	graph, err := k.loadGraph()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, name := range []string{from, to} {
+		if !slices.ContainsFunc(graph.Entities, func(e Entity) bool { return e.Name == name }) {
+			return nil, nil, fmt.Errorf("%w: %s", errEntityNotFound, name)
+		}
+	}
+	if from == to {
+		return []string{from}, nil, nil
+	}
+
+	// via records the relation that first reached each entity
+	via := map[string]int{from: -1}
+	frontier := []string{from}
+	for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+		var next []string
+		for _, current := range frontier {
+			for i, relation := range graph.Relations {
+				var other string
+				switch {
+				case relation.From == current:
+					other = relation.To
+				case undirected && relation.To == current:
+					other = relation.From
+				default:
+					continue
+				}
+				if _, seen := via[other]; seen {
+					continue
+				}
+				via[other] = i
+				if other == to {
+					entities, path := tracePath(graph.Relations, via, to)
+					return entities, path, nil
+				}
+				next = append(next, other)
+			}
+		}
+		frontier = next
+	}
+	return nil, nil, nil
+}
+
+// tracePath follows via back from end, returning the entities on the path
+// and the relations linking them, in order.
+func tracePath(relations []Relation, via map[string]int, end string) ([]string, []Relation) {
+	entities := []string{end}
+	var path []Relation
+	for current := end; via[current] >= 0; {
+		relation := relations[via[current]]
+		path = append(path, relation)
+		if relation.To == current {
+			current = relation.From
+		} else {
+			current = relation.To
+		}
+		entities = append(entities, current)
+	}
+	slices.Reverse(entities)
+	slices.Reverse(path)
+	return entities, path
+}
+
+// Formats the knowledge graph can be exported in.
+const (
+	exportFormatDOT     = "dot"
+	exportFormatGraphML = "graphml"
+)
+
+// exportGraph renders the graph in format. Entities become nodes labelled
+// with their type and observation count, and relations become edges labelled
+// with their type. Relations to entities that don't exist are left out.
+func (k knowledgeBase) exportGraph(format string) (string, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	graph, err := k.loadGraph()
+	if err != nil {
+		return "", err
+	}
+
+	ids := make(map[string]string, len(graph.Entities))
+	for i, entity := range graph.Entities {
+		ids[entity.Name] = fmt.Sprintf("n%d", i)
+	}
+	var relations []Relation
+	for _, relation := range graph.Relations {
+		if ids[relation.From] != "" && ids[relation.To] != "" {
+			relations = append(relations, relation)
+		}
+	}
+
+	var out strings.Builder
+	switch format {
+	case exportFormatDOT:
+		out.WriteString("digraph knowledge {\n  node [shape=box];\n")
+		for _, entity := range graph.Entities {
+			label := fmt.Sprintf("%s\n%d observations", entity.Name, len(entity.Observations))
+			if entity.EntityType != "" {
+				label = fmt.Sprintf("%s\n%s, %d observations", entity.Name, entity.EntityType, len(entity.Observations))
+			}
+			fmt.Fprintf(&out, "  %s [label=%s];\n", ids[entity.Name], dotQuote(label))
+		}
+		for _, relation := range relations {
+			fmt.Fprintf(&out, "  %s -> %s [label=%s];\n", ids[relation.From], ids[relation.To], dotQuote(relation.RelationType))
+		}
+		out.WriteString("}\n")
+
+	case exportFormatGraphML:
+		out.WriteString(xml.Header)
+		out.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+		out.WriteString(`  <key id="name" for="node" attr.name="name" attr.type="string"/>` + "\n")
+		out.WriteString(`  <key id="entityType" for="node" attr.name="entityType" attr.type="string"/>` + "\n")
+		out.WriteString(`  <key id="observations" for="node" attr.name="observations" attr.type="int"/>` + "\n")
+		out.WriteString(`  <key id="relationType" for="edge" attr.name="relationType" attr.type="string"/>` + "\n")
+		out.WriteString(`  <graph id="knowledge" edgedefault="directed">` + "\n")
+		for _, entity := range graph.Entities {
+			fmt.Fprintf(&out, "    <node id=%q>\n", ids[entity.Name])
+			fmt.Fprintf(&out, "      <data key=\"name\">%s</data>\n", xmlEscape(entity.Name))
+			fmt.Fprintf(&out, "      <data key=\"entityType\">%s</data>\n", xmlEscape(entity.EntityType))
+			fmt.Fprintf(&out, "      <data key=\"observations\">%d</data>\n", len(entity.Observations))
+			out.WriteString("    </node>\n")
+		}
+		for i, relation := range relations {
+			fmt.Fprintf(&out, "    <edge id=\"e%d\" source=%q target=%q>\n", i, ids[relation.From], ids[relation.To])
+			fmt.Fprintf(&out, "      <data key=\"relationType\">%s</data>\n", xmlEscape(relation.RelationType))
+			out.WriteString("    </edge>\n")
+		}
+		out.WriteString("  </graph>\n</graphml>\n")
+
+	default:
+		return "", fmt.Errorf("unsupported format %q: must be dot or graphml", format)
+	}
+	return out.String(), nil
+}
+
+// xmlEscape escapes s for use as XML character data.
+func xmlEscape(s string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(s))
+	return escaped.String()
+}
+
+// invalidInput returns an error result telling the caller what was wrong with its input.
+func invalidInput[T any](message string) *mcp.CallToolResultFor[T] {
+	return &mcp.CallToolResultFor[T]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: message},
+		},
+		IsError: true,
+	}
+}
+
 func (k knowledgeBase) CreateEntities(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CreateEntitiesArgs]) (*mcp.CallToolResultFor[CreateEntitiesResult], error) {
 	var res mcp.CallToolResultFor[CreateEntitiesResult]
 
-	entities, err := k.createEntities(params.Arguments.Entities)
+	if len(params.Arguments.Entities) == 0 {
+		return invalidInput[CreateEntitiesResult]("No entities provided; pass at least one entity with a name and entityType"), nil
+	}
+
+	// Nameless entities can never be referenced, so report and drop them
+	var valid []Entity
+	var problems []string
+	for i, entity := range params.Arguments.Entities {
+		if strings.TrimSpace(entity.Name) == "" {
+			problems = append(problems, fmt.Sprintf("entity %d has no name", i+1))
+			continue
+		}
+		valid = append(valid, entity)
+	}
+
+	entities, err := k.createEntities(valid)
 	if err != nil {
 		return nil, err
 	}
 
+	var skipped []string
+	created := slices.Clone(entities)
+	for _, entity := range valid {
+		if i := slices.IndexFunc(created, func(e Entity) bool { return e.Name == entity.Name }); i >= 0 {
+			// Each created entity accounts for one input; repeats were skipped
+			created = slices.Delete(created, i, i+1)
+		} else {
+			skipped = append(skipped, entity.Name)
+		}
+	}
+
+	text := fmt.Sprintf("Created %d entities", len(entities))
+	if len(skipped) > 0 {
+		text += fmt.Sprintf("\nSkipped existing entities: %s", strings.Join(skipped, ", "))
+	}
+	if len(problems) > 0 {
+		text += fmt.Sprintf("\nIgnored invalid input: %s", strings.Join(problems, "; "))
+	}
+
 	res.Content = []mcp.Content{
-		&mcp.TextContent{Text: "Entities created successfully"},
+		&mcp.TextContent{Text: text},
 	}
 
 	res.StructuredContent = CreateEntitiesResult{
@@ -453,13 +1112,34 @@ func (k knowledgeBase) CreateEntities(ctx context.Context, ss *mcp.ServerSession
 func (k knowledgeBase) CreateRelations(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CreateRelationsArgs]) (*mcp.CallToolResultFor[CreateRelationsResult], error) {
 	var res mcp.CallToolResultFor[CreateRelationsResult]
 
+	if len(params.Arguments.Relations) == 0 {
+		return invalidInput[CreateRelationsResult]("No relations provided; pass at least one relation with from, to and relationType"), nil
+	}
+
 	relations, err := k.createRelations(params.Arguments.Relations)
 	if err != nil {
 		return nil, err
 	}
 
+	// Relations are unique by from, to and type, so anything not created already existed
+	var skipped []string
+	created := slices.Clone(relations)
+	for _, relation := range params.Arguments.Relations {
+		if i := slices.Index(created, relation); i >= 0 {
+			// Each created relation accounts for one input; repeats were skipped
+			created = slices.Delete(created, i, i+1)
+		} else {
+			skipped = append(skipped, relationDetail(relation))
+		}
+	}
+
+	text := fmt.Sprintf("Created %d relations", len(relations))
+	if len(skipped) > 0 {
+		text += fmt.Sprintf("\nSkipped existing relations: %s", strings.Join(skipped, ", "))
+	}
+
 	res.Content = []mcp.Content{
-		&mcp.TextContent{Text: "Relations created successfully"},
+		&mcp.TextContent{Text: text},
 	}
 
 	res.StructuredContent = CreateRelationsResult{
@@ -472,14 +1152,30 @@ func (k knowledgeBase) CreateRelations(ctx context.Context, ss *mcp.ServerSessio
 func (k knowledgeBase) AddObservations(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[AddObservationsArgs]) (*mcp.CallToolResultFor[AddObservationsResult], error) {
 	var res mcp.CallToolResultFor[AddObservationsResult]
 
-	observations, err := k.addObservations(params.Arguments.Observations)
+	if len(params.Arguments.Observations) == 0 {
+		return invalidInput[AddObservationsResult]("No observations provided; pass at least one entityName with contents"), nil
+	}
+
+	observations, missing, err := k.addObservations(params.Arguments.Observations)
 	if err != nil {
 		return nil, err
 	}
 
+	added := 0
+	for _, obs := range observations {
+		added += len(obs.Contents)
+	}
+
+	text := fmt.Sprintf("Added %d observations to %d entities", added, len(observations))
+	if len(missing) > 0 {
+		text += fmt.Sprintf("\nEntities not found (observations skipped): %s", strings.Join(missing, ", "))
+	}
+
 	res.Content = []mcp.Content{
-		&mcp.TextContent{Text: "Observations added successfully"},
+		&mcp.TextContent{Text: text},
 	}
+	// Nothing applied at all means the caller has to fix its input
+	res.IsError = len(observations) == 0
 
 	res.StructuredContent = AddObservationsResult{
 		Observations: observations,
@@ -488,6 +1184,94 @@ func (k knowledgeBase) AddObservations(ctx context.Context, ss *mcp.ServerSessio
 	return &res, nil
 }
 
+func (k knowledgeBase) UpdateObservations(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[UpdateObservationsArgs]) (*mcp.CallToolResultFor[UpdateObservationsResult], error) {
+	var res mcp.CallToolResultFor[UpdateObservationsResult]
+
+	if len(params.Arguments.Updates) == 0 {
+		return invalidInput[UpdateObservationsResult]("No updates provided; pass at least one entityName with old and new observation contents"), nil
+	}
+
+	updated, notFound, err := k.updateObservations(params.Arguments.Updates)
+	if err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf("Updated %d observations", len(updated))
+	if len(notFound) > 0 {
+		var missing []string
+		for _, update := range notFound {
+			missing = append(missing, fmt.Sprintf("%s: %q", update.EntityName, update.Old))
+		}
+		text += fmt.Sprintf("\nNot found (left unchanged): %s", strings.Join(missing, "; "))
+	}
+
+	res.Content = []mcp.Content{
+		&mcp.TextContent{Text: text},
+	}
+	// Nothing applied at all means the caller has to fix its input
+	res.IsError = len(updated) == 0
+
+	res.StructuredContent = UpdateObservationsResult{
+		Updated:  updated,
+		NotFound: notFound,
+	}
+
+	return &res, nil
+}
+
+func (k knowledgeBase) RenameEntity(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[RenameEntityArgs]) (*mcp.CallToolResultFor[RenameEntityResult], error) {
+	var res mcp.CallToolResultFor[RenameEntityResult]
+	args := params.Arguments
+
+	if strings.TrimSpace(args.NewName) == "" {
+		return invalidInput[RenameEntityResult]("No new name provided; pass the entity's current name and a non-empty newName"), nil
+	}
+
+	updated, err := k.renameEntity(args.Name, args.NewName)
+	if errors.Is(err, errEntityNotFound) || errors.Is(err, errEntityExists) {
+		return invalidInput[RenameEntityResult](fmt.Sprintf("Cannot rename: %v", err)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res.Content = []mcp.Content{
+		&mcp.TextContent{Text: fmt.Sprintf("Renamed %s to %s and updated %d relations", args.Name, args.NewName, updated)},
+	}
+
+	res.StructuredContent = RenameEntityResult{
+		Name:             args.NewName,
+		RelationsUpdated: updated,
+	}
+
+	return &res, nil
+}
+
+func (k knowledgeBase) MergeEntities(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[MergeEntitiesArgs]) (*mcp.CallToolResultFor[MergeEntitiesResult], error) {
+	var res mcp.CallToolResultFor[MergeEntitiesResult]
+	args := params.Arguments
+
+	if args.Source == args.Target {
+		return invalidInput[MergeEntitiesResult]("Source and target are the same entity; pass two different entity names"), nil
+	}
+
+	result, err := k.mergeEntities(args.Source, args.Target)
+	if errors.Is(err, errEntityNotFound) {
+		return invalidInput[MergeEntitiesResult](fmt.Sprintf("Cannot merge: %v", err)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res.Content = []mcp.Content{
+		&mcp.TextContent{Text: fmt.Sprintf("Merged %s into %s: %d observations added, %d relations rewired, %d relations dropped as self-loops or duplicates",
+			args.Source, args.Target, result.ObservationsAdded, result.RelationsRewired, result.RelationsDropped)},
+	}
+
+	res.StructuredContent = result
+	return &res, nil
+}
+
 func (k knowledgeBase) DeleteEntities(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[DeleteEntitiesArgs]) (*mcp.CallToolResultFor[struct{}], error) {
 	var res mcp.CallToolResultFor[struct{}]
 
@@ -533,19 +1317,28 @@ func (k knowledgeBase) DeleteRelations(ctx context.Context, ss *mcp.ServerSessio
 	return &res, nil
 }
 
-func (k knowledgeBase) ReadGraph(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[KnowledgeGraph], error) {
-	var res mcp.CallToolResultFor[KnowledgeGraph]
+func (k knowledgeBase) ReadGraph(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ReadGraphArgs]) (*mcp.CallToolResultFor[ReadGraphResult], error) {
+	var res mcp.CallToolResultFor[ReadGraphResult]
+	args := params.Arguments
 
-	graph, err := k.loadGraph()
+	if args.Offset < 0 || args.Limit < 0 {
+		return invalidInput[ReadGraphResult]("offset and limit must not be negative"), nil
+	}
+
+	result, err := k.readGraph(args.EntityType, args.Offset, args.Limit)
 	if err != nil {
 		return nil, err
 	}
 
+	text := "Graph read successfully"
+	if args.EntityType != "" || args.Offset > 0 || args.Limit > 0 {
+		text = fmt.Sprintf("Read %d of %d entities", len(result.Entities), result.Total)
+	}
 	res.Content = []mcp.Content{
-		&mcp.TextContent{Text: "Graph read successfully"},
+		&mcp.TextContent{Text: text},
 	}
 
-	res.StructuredContent = graph
+	res.StructuredContent = result
 	return &res, nil
 }
 
@@ -565,6 +1358,115 @@ func (k knowledgeBase) SearchNodes(ctx context.Context, ss *mcp.ServerSession, p
 	return &res, nil
 }
 
+func (k knowledgeBase) GetNeighbors(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[GetNeighborsArgs]) (*mcp.CallToolResultFor[KnowledgeGraph], error) {
+	var res mcp.CallToolResultFor[KnowledgeGraph]
+	args := params.Arguments
+
+	depth := args.Depth
+	if depth == 0 {
+		depth = 1
+	}
+	if depth < 0 || depth > maxNeighborDepth {
+		return invalidInput[KnowledgeGraph](fmt.Sprintf("Invalid depth %d: must be between 1 and %d", args.Depth, maxNeighborDepth)), nil
+	}
+
+	direction := args.Direction
+	if direction == "" {
+		direction = directionBoth
+	}
+	if direction != directionOutgoing && direction != directionIncoming && direction != directionBoth {
+		return invalidInput[KnowledgeGraph](fmt.Sprintf("Invalid direction %q: must be outgoing, incoming or both", args.Direction)), nil
+	}
+
+	graph, found, err := k.neighbors(args.Name, depth, direction)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return invalidInput[KnowledgeGraph](fmt.Sprintf("Entity %q not found", args.Name)), nil
+	}
+
+	res.Content = []mcp.Content{
+		&mcp.TextContent{Text: fmt.Sprintf("Found %d entities within %d relations of %s (%s)", len(graph.Entities)-1, depth, args.Name, direction)},
+	}
+
+	res.StructuredContent = graph
+	return &res, nil
+}
+
+func (k knowledgeBase) ExportGraph(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ExportGraphArgs]) (*mcp.CallToolResultFor[struct{}], error) {
+	var res mcp.CallToolResultFor[struct{}]
+
+	format := params.Arguments.Format
+	if format == "" {
+		format = exportFormatDOT
+	}
+	if format != exportFormatDOT && format != exportFormatGraphML {
+		return invalidInput[struct{}](fmt.Sprintf("Unsupported format %q: must be dot or graphml", params.Arguments.Format)), nil
+	}
+
+	out, err := k.exportGraph(format)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Content = []mcp.Content{
+		&mcp.TextContent{Text: out},
+	}
+
+	return &res, nil
+}
+
+func (k knowledgeBase) FindPath(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[FindPathArgs]) (*mcp.CallToolResultFor[FindPathResult], error) {
+	var res mcp.CallToolResultFor[FindPathResult]
+	args := params.Arguments
+
+	maxDepth := args.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = defaultPathDepth
+	}
+	if maxDepth < 0 || maxDepth > maxPathDepth {
+		return invalidInput[FindPathResult](fmt.Sprintf("Invalid maxDepth %d: must be between 1 and %d", args.MaxDepth, maxPathDepth)), nil
+	}
+
+	entities, relations, err := k.findPath(args.From, args.To, maxDepth, args.IgnoreDirection)
+	if errors.Is(err, errEntityNotFound) {
+		return invalidInput[FindPathResult](fmt.Sprintf("Cannot find a path: %v", err)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if entities == nil {
+		res.Content = []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("No path from %s to %s within %d relations", args.From, args.To, maxDepth)},
+		}
+		res.StructuredContent = FindPathResult{}
+		return &res, nil
+	}
+
+	var path strings.Builder
+	path.WriteString(entities[0])
+	for i, relation := range relations {
+		if relation.From == entities[i] {
+			fmt.Fprintf(&path, " -%s-> %s", relation.RelationType, entities[i+1])
+		} else {
+			fmt.Fprintf(&path, " <-%s- %s", relation.RelationType, entities[i+1])
+		}
+	}
+
+	res.Content = []mcp.Content{
+		&mcp.TextContent{Text: fmt.Sprintf("Path of %d relations: %s", len(relations), path.String())},
+	}
+
+	res.StructuredContent = FindPathResult{
+		Found:     true,
+		Entities:  entities,
+		Relations: relations,
+	}
+	return &res, nil
+}
+
 func (k knowledgeBase) OpenNodes(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[OpenNodesArgs]) (*mcp.CallToolResultFor[KnowledgeGraph], error) {
 	var res mcp.CallToolResultFor[KnowledgeGraph]
 
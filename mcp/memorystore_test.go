@@ -0,0 +1,753 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newTestKnowledgeBase returns a knowledge base backed by an in-memory store.
+func newTestKnowledgeBase() knowledgeBase {
+	return knowledgeBase{s: &memoryStore{}, mu: new(sync.RWMutex), history: newGraphHistory(defaultGraphHistorySize)}
+}
+
+// TestKnowledgeBaseConcurrentMutations is meant to be run with -race.
+func TestKnowledgeBaseConcurrentMutations(t *testing.T) {
+	k := newTestKnowledgeBase()
+	ctx := context.Background()
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("entity-%d", i)
+			_, err := k.CreateEntities(ctx, nil, &mcp.CallToolParamsFor[CreateEntitiesArgs]{
+				Arguments: CreateEntitiesArgs{Entities: []Entity{{Name: name, EntityType: "test"}}},
+			})
+			if err != nil {
+				t.Errorf("CreateEntities(%s) failed: %v", name, err)
+				return
+			}
+			for j := range 5 {
+				_, err := k.AddObservations(ctx, nil, &mcp.CallToolParamsFor[AddObservationsArgs]{
+					Arguments: AddObservationsArgs{Observations: []Observation{{EntityName: name, Contents: []string{fmt.Sprintf("fact %d", j)}}}},
+				})
+				if err != nil {
+					t.Errorf("AddObservations(%s) failed: %v", name, err)
+					return
+				}
+			}
+			// Odd workers delete their entity again
+			if i%2 == 1 {
+				_, err := k.DeleteEntities(ctx, nil, &mcp.CallToolParamsFor[DeleteEntitiesArgs]{
+					Arguments: DeleteEntitiesArgs{EntityNames: []string{name}},
+				})
+				if err != nil {
+					t.Errorf("DeleteEntities(%s) failed: %v", name, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	graph, err := k.loadGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Entities) != workers/2 {
+		t.Fatalf("%d entities left, want %d", len(graph.Entities), workers/2)
+	}
+	for _, entity := range graph.Entities {
+		if len(entity.Observations) != 5 {
+			t.Errorf("%s has %d observations, want 5; concurrent updates were lost", entity.Name, len(entity.Observations))
+		}
+	}
+}
+
+// newFileKnowledgeBase returns a knowledge base persisted to path, as main sets
+// it up when MemoryFilePathEnv is set.
+func newFileKnowledgeBase(path string) knowledgeBase {
+	return knowledgeBase{s: &fileStore{path: path}, mu: new(sync.RWMutex)}
+}
+
+func TestFileStoreReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory", "graph.json")
+
+	k := newFileKnowledgeBase(path)
+	if graph, err := k.loadGraph(); err != nil || len(graph.Entities) != 0 {
+		t.Fatalf("loadGraph() before the file exists = %+v, %v; want an empty graph", graph, err)
+	}
+
+	if _, err := k.createEntities([]Entity{
+		{Name: "web", EntityType: "service", Observations: []string{"serves HTTP"}},
+		{Name: "db", EntityType: "database"},
+		{Name: "cache", EntityType: "database"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.createRelations([]Relation{
+		{From: "web", To: "db", RelationType: "reads"},
+		{From: "web", To: "cache", RelationType: "reads"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := k.addObservations([]Observation{{EntityName: "db", Contents: []string{"postgres 16"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.deleteEntities([]string{"cache"}); err != nil {
+		t.Fatal(err)
+	}
+	want, err := k.loadGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reconstruct the knowledge base from the file, as after a restart
+	got, err := newFileKnowledgeBase(path).loadGraph()
+	if err != nil {
+		t.Fatalf("loadGraph() after reloading failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reloaded graph = %+v, want %+v", got, want)
+	}
+	if len(got.Entities) != 2 || len(got.Relations) != 1 || got.Relations[0] != (Relation{From: "web", To: "db", RelationType: "reads"}) {
+		t.Errorf("reloaded graph = %+v, want web and db joined by one relation", got)
+	}
+	for _, entity := range got.Entities {
+		if entity.Created == nil {
+			t.Errorf("reloaded entity %s lost its creation time", entity.Name)
+		}
+		if entity.Name == "db" && !slices.Contains(entity.Observations, "postgres 16") {
+			t.Errorf("reloaded db observations = %v, want postgres 16", entity.Observations)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("memory directory holds %d files, want only the graph; temporary files were left behind", len(entries))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("graph file mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestFileStoreInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "graph.json")
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newFileKnowledgeBase(path).loadGraph(); err == nil {
+		t.Error("loadGraph() of a corrupt file succeeded")
+	}
+}
+
+// newSeededKnowledgeBase returns an in-memory knowledge base in which alice
+// and bob work at acme, alice knows bob, and acme uses go.
+func newSeededKnowledgeBase(t *testing.T) knowledgeBase {
+	t.Helper()
+	k := newTestKnowledgeBase()
+	if _, err := k.createEntities([]Entity{
+		{Name: "alice", EntityType: "person", Observations: []string{"likes go", "lives in paris"}},
+		{Name: "bob", EntityType: "person", Observations: []string{"likes tea"}},
+		{Name: "acme", EntityType: "company"},
+		{Name: "go", EntityType: "language"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := k.createRelations([]Relation{
+		{From: "alice", To: "acme", RelationType: "works_at"},
+		{From: "bob", To: "acme", RelationType: "works_at"},
+		{From: "alice", To: "bob", RelationType: "knows"},
+		{From: "acme", To: "go", RelationType: "uses"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// callKB runs a knowledge base tool with args, failing the test if the tool
+// returns an error rather than an error result.
+func callKB[Args, Result any](t *testing.T, tool func(context.Context, *mcp.ServerSession, *mcp.CallToolParamsFor[Args]) (*mcp.CallToolResultFor[Result], error), args Args) *mcp.CallToolResultFor[Result] {
+	t.Helper()
+	res, err := tool(context.Background(), nil, &mcp.CallToolParamsFor[Args]{Arguments: args})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// kbText returns the text of a knowledge base tool result.
+func kbText[Result any](res *mcp.CallToolResultFor[Result]) string {
+	var parts []string
+	for _, content := range res.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// entityNamed returns the entity called name in k's graph.
+func entityNamed(t *testing.T, k knowledgeBase, name string) (Entity, bool) {
+	t.Helper()
+	graph, err := k.loadGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(graph.Entities, func(e Entity) bool { return e.Name == name })
+	if i < 0 {
+		return Entity{}, false
+	}
+	return graph.Entities[i], true
+}
+
+func TestUpdateObservations(t *testing.T) {
+	tests := []struct {
+		name     string
+		updates  []ObservationUpdate
+		isError  bool
+		notFound int
+		want     []string // alice's observations afterwards
+	}{
+		{
+			name:    "replace in place",
+			updates: []ObservationUpdate{{EntityName: "alice", Old: "likes go", New: "loves go"}},
+			want:    []string{"loves go", "lives in paris"},
+		},
+		{
+			name:    "replace with an existing observation",
+			updates: []ObservationUpdate{{EntityName: "alice", Old: "likes go", New: "lives in paris"}},
+			want:    []string{"lives in paris"},
+		},
+		{
+			name: "partly applied",
+			updates: []ObservationUpdate{
+				{EntityName: "alice", Old: "lives in paris", New: "lives in lyon"},
+				{EntityName: "alice", Old: "likes rust", New: "loves rust"},
+			},
+			notFound: 1,
+			want:     []string{"likes go", "lives in lyon"},
+		},
+		{
+			name:     "missing entity",
+			updates:  []ObservationUpdate{{EntityName: "carol", Old: "likes go", New: "loves go"}},
+			isError:  true,
+			notFound: 1,
+			want:     []string{"likes go", "lives in paris"},
+		},
+		{
+			name:     "missing observation",
+			updates:  []ObservationUpdate{{EntityName: "alice", Old: "likes rust", New: "loves rust"}},
+			isError:  true,
+			notFound: 1,
+			want:     []string{"likes go", "lives in paris"},
+		},
+		{
+			name:    "no updates",
+			isError: true,
+			want:    []string{"likes go", "lives in paris"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newSeededKnowledgeBase(t)
+			res := callKB(t, k.UpdateObservations, UpdateObservationsArgs{Updates: tt.updates})
+			if res.IsError != tt.isError {
+				t.Errorf("IsError = %v, want %v: %s", res.IsError, tt.isError, kbText(res))
+			}
+			if got := len(res.StructuredContent.NotFound); got != tt.notFound {
+				t.Errorf("%d updates not found, want %d", got, tt.notFound)
+			}
+			alice, _ := entityNamed(t, k, "alice")
+			if !slices.Equal(alice.Observations, tt.want) {
+				t.Errorf("observations = %q, want %q", alice.Observations, tt.want)
+			}
+			for _, observation := range alice.Observations {
+				if _, ok := alice.ObservationTimes[observation]; !ok {
+					t.Errorf("observation %q has no timestamp", observation)
+				}
+			}
+			if len(alice.ObservationTimes) != len(alice.Observations) {
+				t.Errorf("observation times = %v, want one per observation", alice.ObservationTimes)
+			}
+		})
+	}
+}
+
+// graphSummary returns the entity names and relation details of graph.
+func graphSummary(entities []Entity, relations []Relation) ([]string, []string) {
+	var names, details []string
+	for _, entity := range entities {
+		names = append(names, entity.Name)
+	}
+	for _, relation := range relations {
+		details = append(details, relationDetail(relation))
+	}
+	return names, details
+}
+
+func TestGetNeighbors(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      GetNeighborsArgs
+		isError   bool
+		entities  []string
+		relations []string
+	}{
+		{
+			name:      "both directions by default",
+			args:      GetNeighborsArgs{Name: "bob"},
+			entities:  []string{"alice", "bob", "acme"},
+			relations: []string{"bob -works_at-> acme", "alice -knows-> bob"},
+		},
+		{
+			name:      "outgoing",
+			args:      GetNeighborsArgs{Name: "bob", Direction: "outgoing"},
+			entities:  []string{"bob", "acme"},
+			relations: []string{"bob -works_at-> acme"},
+		},
+		{
+			name:      "incoming",
+			args:      GetNeighborsArgs{Name: "bob", Direction: "incoming"},
+			entities:  []string{"alice", "bob"},
+			relations: []string{"alice -knows-> bob"},
+		},
+		{
+			name:      "two relations away",
+			args:      GetNeighborsArgs{Name: "bob", Depth: 2, Direction: "outgoing"},
+			entities:  []string{"bob", "acme", "go"},
+			relations: []string{"bob -works_at-> acme", "acme -uses-> go"},
+		},
+		{
+			name:      "no neighbors",
+			args:      GetNeighborsArgs{Name: "go", Direction: "outgoing"},
+			entities:  []string{"go"},
+			relations: nil,
+		},
+		{name: "depth too large", args: GetNeighborsArgs{Name: "bob", Depth: maxNeighborDepth + 1}, isError: true},
+		{name: "negative depth", args: GetNeighborsArgs{Name: "bob", Depth: -1}, isError: true},
+		{name: "unknown direction", args: GetNeighborsArgs{Name: "bob", Direction: "sideways"}, isError: true},
+		{name: "missing entity", args: GetNeighborsArgs{Name: "carol"}, isError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newSeededKnowledgeBase(t)
+			res := callKB(t, k.GetNeighbors, tt.args)
+			if res.IsError != tt.isError {
+				t.Fatalf("IsError = %v, want %v: %s", res.IsError, tt.isError, kbText(res))
+			}
+			if tt.isError {
+				return
+			}
+			entities, relations := graphSummary(res.StructuredContent.Entities, res.StructuredContent.Relations)
+			if !slices.Equal(entities, tt.entities) || !slices.Equal(relations, tt.relations) {
+				t.Errorf("neighbors = %v joined by %v, want %v joined by %v", entities, relations, tt.entities, tt.relations)
+			}
+		})
+	}
+}
+
+func TestCreateSkipsDuplicates(t *testing.T) {
+	entityTests := []struct {
+		name     string
+		entities []Entity
+		isError  bool
+		created  []string
+		text     string
+	}{
+		{"new entity", []Entity{{Name: "carol", EntityType: "person"}}, false, []string{"carol"}, "Created 1 entities"},
+		{"existing entity", []Entity{{Name: "alice", EntityType: "robot"}}, false, nil, "Created 0 entities\nSkipped existing entities: alice"},
+		{"repeated in one call", []Entity{{Name: "carol"}, {Name: "carol"}}, false, []string{"carol"}, "Created 1 entities\nSkipped existing entities: carol"},
+		{"nameless entity", []Entity{{Name: " "}, {Name: "carol"}}, false, []string{"carol"}, "Created 1 entities\nIgnored invalid input: entity 1 has no name"},
+		{"no entities", nil, true, nil, ""},
+	}
+	for _, tt := range entityTests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newSeededKnowledgeBase(t)
+			res := callKB(t, k.CreateEntities, CreateEntitiesArgs{Entities: tt.entities})
+			if res.IsError != tt.isError {
+				t.Fatalf("IsError = %v, want %v: %s", res.IsError, tt.isError, kbText(res))
+			}
+			created, _ := graphSummary(res.StructuredContent.Entities, nil)
+			if !slices.Equal(created, tt.created) {
+				t.Errorf("created %v, want %v", created, tt.created)
+			}
+			if tt.text != "" && kbText(res) != tt.text {
+				t.Errorf("text = %q, want %q", kbText(res), tt.text)
+			}
+			if alice, _ := entityNamed(t, k, "alice"); alice.EntityType != "person" {
+				t.Errorf("alice's type = %q, want the existing entity untouched", alice.EntityType)
+			}
+		})
+	}
+
+	knows := Relation{From: "alice", To: "bob", RelationType: "knows"}
+	relationTests := []struct {
+		name      string
+		relations []Relation
+		isError   bool
+		created   []string
+		text      string
+	}{
+		{"new relation", []Relation{{From: "bob", To: "alice", RelationType: "knows"}}, false, []string{"bob -knows-> alice"}, "Created 1 relations"},
+		{"existing relation", []Relation{knows}, false, nil, "Created 0 relations\nSkipped existing relations: alice -knows-> bob"},
+		{"repeated in one call", []Relation{{From: "bob", To: "go", RelationType: "uses"}, {From: "bob", To: "go", RelationType: "uses"}}, false, []string{"bob -uses-> go"}, "Created 1 relations\nSkipped existing relations: bob -uses-> go"},
+		{"no relations", nil, true, nil, ""},
+	}
+	for _, tt := range relationTests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newSeededKnowledgeBase(t)
+			res := callKB(t, k.CreateRelations, CreateRelationsArgs{Relations: tt.relations})
+			if res.IsError != tt.isError {
+				t.Fatalf("IsError = %v, want %v: %s", res.IsError, tt.isError, kbText(res))
+			}
+			_, created := graphSummary(nil, res.StructuredContent.Relations)
+			if !slices.Equal(created, tt.created) {
+				t.Errorf("created %v, want %v", created, tt.created)
+			}
+			if tt.text != "" && kbText(res) != tt.text {
+				t.Errorf("text = %q, want %q", kbText(res), tt.text)
+			}
+			graph, err := k.loadGraph()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := len(slices.DeleteFunc(slices.Clone(graph.Relations), func(r Relation) bool { return r != knows })); n != 1 {
+				t.Errorf("graph holds %d copies of %s, want 1", n, relationDetail(knows))
+			}
+		})
+	}
+}
+
+func TestRenameEntity(t *testing.T) {
+	seeded := []string{"alice -works_at-> acme", "bob -works_at-> acme", "alice -knows-> bob", "acme -uses-> go"}
+	tests := []struct {
+		name      string
+		args      RenameEntityArgs
+		isError   bool
+		updated   int
+		entities  []string
+		relations []string
+	}{
+		{
+			name:      "rename",
+			args:      RenameEntityArgs{Name: "alice", NewName: "alicia"},
+			updated:   2,
+			entities:  []string{"alicia", "bob", "acme", "go"},
+			relations: []string{"alicia -works_at-> acme", "bob -works_at-> acme", "alicia -knows-> bob", "acme -uses-> go"},
+		},
+		{
+			name:      "no relations",
+			args:      RenameEntityArgs{Name: "go", NewName: "golang"},
+			updated:   1,
+			entities:  []string{"alice", "bob", "acme", "golang"},
+			relations: []string{"alice -works_at-> acme", "bob -works_at-> acme", "alice -knows-> bob", "acme -uses-> golang"},
+		},
+		{name: "name taken", args: RenameEntityArgs{Name: "alice", NewName: "bob"}, isError: true},
+		{name: "missing entity", args: RenameEntityArgs{Name: "carol", NewName: "caroline"}, isError: true},
+		{name: "empty new name", args: RenameEntityArgs{Name: "alice", NewName: " "}, isError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newSeededKnowledgeBase(t)
+			res := callKB(t, k.RenameEntity, tt.args)
+			if res.IsError != tt.isError {
+				t.Fatalf("IsError = %v, want %v: %s", res.IsError, tt.isError, kbText(res))
+			}
+			if tt.isError {
+				// Nothing is saved on error
+				tt.entities, tt.relations = []string{"alice", "bob", "acme", "go"}, seeded
+			} else if res.StructuredContent.RelationsUpdated != tt.updated {
+				t.Errorf("%d relations updated, want %d", res.StructuredContent.RelationsUpdated, tt.updated)
+			}
+
+			graph, err := k.loadGraph()
+			if err != nil {
+				t.Fatal(err)
+			}
+			entities, relations := graphSummary(graph.Entities, graph.Relations)
+			if !slices.Equal(entities, tt.entities) || !slices.Equal(relations, tt.relations) {
+				t.Errorf("graph = %v joined by %v, want %v joined by %v", entities, relations, tt.entities, tt.relations)
+			}
+		})
+	}
+}
+
+func TestMergeEntities(t *testing.T) {
+	seeded := []string{"alice -works_at-> acme", "bob -works_at-> acme", "alice -knows-> bob", "acme -uses-> go"}
+	tests := []struct {
+		name         string
+		args         MergeEntitiesArgs
+		isError      bool
+		result       MergeEntitiesResult
+		observations []string // the target's observations afterwards
+		relations    []string
+	}{
+		{
+			name:         "rewire relations",
+			args:         MergeEntitiesArgs{Source: "bob", Target: "go"},
+			result:       MergeEntitiesResult{Target: "go", ObservationsAdded: 1, RelationsRewired: 2},
+			observations: []string{"likes tea"},
+			relations:    []string{"alice -works_at-> acme", "acme -uses-> go", "go -works_at-> acme", "alice -knows-> go"},
+		},
+		{
+			name:         "drop duplicates and self-loops",
+			args:         MergeEntitiesArgs{Source: "bob", Target: "alice"},
+			result:       MergeEntitiesResult{Target: "alice", ObservationsAdded: 1, RelationsDropped: 2},
+			observations: []string{"likes go", "lives in paris", "likes tea"},
+			relations:    []string{"alice -works_at-> acme", "acme -uses-> go"},
+		},
+		{name: "same entity", args: MergeEntitiesArgs{Source: "bob", Target: "bob"}, isError: true},
+		{name: "missing source", args: MergeEntitiesArgs{Source: "carol", Target: "bob"}, isError: true},
+		{name: "missing target", args: MergeEntitiesArgs{Source: "bob", Target: "carol"}, isError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newSeededKnowledgeBase(t)
+			res := callKB(t, k.MergeEntities, tt.args)
+			if res.IsError != tt.isError {
+				t.Fatalf("IsError = %v, want %v: %s", res.IsError, tt.isError, kbText(res))
+			}
+
+			graph, err := k.loadGraph()
+			if err != nil {
+				t.Fatal(err)
+			}
+			entities, relations := graphSummary(graph.Entities, graph.Relations)
+			if tt.isError {
+				// Nothing is saved on error
+				if len(entities) != 4 || !slices.Equal(relations, seeded) {
+					t.Errorf("rejected merge changed the graph to %v joined by %v", entities, relations)
+				}
+				return
+			}
+
+			if res.StructuredContent != tt.result {
+				t.Errorf("result = %+v, want %+v", res.StructuredContent, tt.result)
+			}
+			if slices.Contains(entities, tt.args.Source) {
+				t.Errorf("source %s still exists", tt.args.Source)
+			}
+			target, _ := entityNamed(t, k, tt.args.Target)
+			if !slices.Equal(target.Observations, tt.observations) {
+				t.Errorf("target observations = %q, want %q", target.Observations, tt.observations)
+			}
+			if _, ok := target.ObservationTimes["likes tea"]; !ok {
+				t.Error("merged observation has no timestamp")
+			}
+			if !slices.Equal(relations, tt.relations) {
+				t.Errorf("relations = %v, want %v", relations, tt.relations)
+			}
+		})
+	}
+}
+
+func TestReadGraphPaging(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      ReadGraphArgs
+		isError   bool
+		text      string
+		total     int
+		entities  []string
+		relations []string
+	}{
+		{
+			name:      "whole graph",
+			text:      "Graph read successfully",
+			total:     4,
+			entities:  []string{"alice", "bob", "acme", "go"},
+			relations: []string{"alice -works_at-> acme", "bob -works_at-> acme", "alice -knows-> bob", "acme -uses-> go"},
+		},
+		{
+			name:      "by type",
+			args:      ReadGraphArgs{EntityType: "person"},
+			text:      "Read 2 of 2 entities",
+			total:     2,
+			entities:  []string{"alice", "bob"},
+			relations: []string{"alice -works_at-> acme", "bob -works_at-> acme", "alice -knows-> bob"},
+		},
+		{
+			name:      "by type from an offset",
+			args:      ReadGraphArgs{EntityType: "person", Offset: 1},
+			text:      "Read 1 of 2 entities",
+			total:     2,
+			entities:  []string{"bob"},
+			relations: []string{"bob -works_at-> acme", "alice -knows-> bob"},
+		},
+		{
+			name:      "second page",
+			args:      ReadGraphArgs{Offset: 2, Limit: 2},
+			text:      "Read 2 of 4 entities",
+			total:     4,
+			entities:  []string{"acme", "go"},
+			relations: []string{"alice -works_at-> acme", "bob -works_at-> acme", "acme -uses-> go"},
+		},
+		{name: "past the end", args: ReadGraphArgs{Offset: 10}, text: "Read 0 of 4 entities", total: 4},
+		{name: "unknown type", args: ReadGraphArgs{EntityType: "robot"}, text: "Read 0 of 0 entities"},
+		{name: "negative offset", args: ReadGraphArgs{Offset: -1}, isError: true},
+		{name: "negative limit", args: ReadGraphArgs{Limit: -1}, isError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newSeededKnowledgeBase(t)
+			res := callKB(t, k.ReadGraph, tt.args)
+			if res.IsError != tt.isError {
+				t.Fatalf("IsError = %v, want %v: %s", res.IsError, tt.isError, kbText(res))
+			}
+			if tt.isError {
+				return
+			}
+			if kbText(res) != tt.text {
+				t.Errorf("text = %q, want %q", kbText(res), tt.text)
+			}
+			page := res.StructuredContent
+			entities, relations := graphSummary(page.Entities, page.Relations)
+			if page.Total != tt.total || !slices.Equal(entities, tt.entities) || !slices.Equal(relations, tt.relations) {
+				t.Errorf("page = %v of %d joined by %v, want %v of %d joined by %v", entities, page.Total, relations, tt.entities, tt.total, tt.relations)
+			}
+		})
+	}
+}
+
+func TestExportGraph(t *testing.T) {
+	k := newTestKnowledgeBase()
+	if _, err := k.createEntities([]Entity{
+		{Name: `web "edge"`, EntityType: "service", Observations: []string{"serves HTTP"}},
+		{Name: "db<1>"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// The relation to ghost, which does not exist, is left out of exports
+	if _, err := k.createRelations([]Relation{
+		{From: `web "edge"`, To: "db<1>", RelationType: "reads & writes"},
+		{From: "db<1>", To: "ghost", RelationType: "replicates"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dot := `digraph knowledge {
+  node [shape=box];
+  n0 [label="web \"edge\"\nservice, 1 observations"];
+  n1 [label="db<1>\n0 observations"];
+  n0 -> n1 [label="reads & writes"];
+}
+`
+	graphML := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="name" for="node" attr.name="name" attr.type="string"/>
+  <key id="entityType" for="node" attr.name="entityType" attr.type="string"/>
+  <key id="observations" for="node" attr.name="observations" attr.type="int"/>
+  <key id="relationType" for="edge" attr.name="relationType" attr.type="string"/>
+  <graph id="knowledge" edgedefault="directed">
+    <node id="n0">
+      <data key="name">web &#34;edge&#34;</data>
+      <data key="entityType">service</data>
+      <data key="observations">1</data>
+    </node>
+    <node id="n1">
+      <data key="name">db&lt;1&gt;</data>
+      <data key="entityType"></data>
+      <data key="observations">0</data>
+    </node>
+    <edge id="e0" source="n0" target="n1">
+      <data key="relationType">reads &amp; writes</data>
+    </edge>
+  </graph>
+</graphml>
+`
+	tests := []struct {
+		format  string
+		isError bool
+		want    string
+	}{
+		{format: "", want: dot},
+		{format: "dot", want: dot},
+		{format: "graphml", want: graphML},
+		{format: "svg", isError: true},
+	}
+	for _, tt := range tests {
+		res := callKB(t, k.ExportGraph, ExportGraphArgs{Format: tt.format})
+		if res.IsError != tt.isError {
+			t.Errorf("format %q: IsError = %v, want %v: %s", tt.format, res.IsError, tt.isError, kbText(res))
+			continue
+		}
+		if !tt.isError && kbText(res) != tt.want {
+			t.Errorf("format %q: export =\n%s\nwant\n%s", tt.format, kbText(res), tt.want)
+		}
+	}
+}
+
+func TestFindPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     FindPathArgs
+		isError  bool
+		text     string
+		entities []string
+	}{
+		{
+			name:     "directed",
+			args:     FindPathArgs{From: "alice", To: "go"},
+			text:     "Path of 2 relations: alice -works_at-> acme -uses-> go",
+			entities: []string{"alice", "acme", "go"},
+		},
+		{
+			name:     "against a relation",
+			args:     FindPathArgs{From: "bob", To: "alice", IgnoreDirection: true},
+			text:     "Path of 1 relations: bob <-knows- alice",
+			entities: []string{"bob", "alice"},
+		},
+		{
+			name:     "to itself",
+			args:     FindPathArgs{From: "alice", To: "alice"},
+			text:     "Path of 0 relations: alice",
+			entities: []string{"alice"},
+		},
+		{name: "only against relations", args: FindPathArgs{From: "bob", To: "alice"}, text: "No path from bob to alice within 6 relations"},
+		{name: "beyond maxDepth", args: FindPathArgs{From: "alice", To: "go", MaxDepth: 1}, text: "No path from alice to go within 1 relations"},
+		{name: "missing entity", args: FindPathArgs{From: "alice", To: "carol"}, isError: true},
+		{name: "maxDepth too large", args: FindPathArgs{From: "alice", To: "go", MaxDepth: maxPathDepth + 1}, isError: true},
+		{name: "negative maxDepth", args: FindPathArgs{From: "alice", To: "go", MaxDepth: -1}, isError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := newSeededKnowledgeBase(t)
+			res := callKB(t, k.FindPath, tt.args)
+			if res.IsError != tt.isError {
+				t.Fatalf("IsError = %v, want %v: %s", res.IsError, tt.isError, kbText(res))
+			}
+			if tt.isError {
+				return
+			}
+			if kbText(res) != tt.text {
+				t.Errorf("text = %q, want %q", kbText(res), tt.text)
+			}
+			result := res.StructuredContent
+			if result.Found != (tt.entities != nil) || !slices.Equal(result.Entities, tt.entities) {
+				t.Errorf("path = %+v, want %v", result, tt.entities)
+			}
+			if result.Found && len(result.Relations) != len(result.Entities)-1 {
+				t.Errorf("path of %d entities has %d relations", len(result.Entities), len(result.Relations))
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// An Operation is a tool call that is currently being handled.
+type Operation struct {
+	// Short random ID used to cancel the operation.
+	ID string `json:"id"`
+	// Name of the tool being run.
+	Tool string `json:"tool"`
+	// MCP session that issued the call.
+	SessionID string `json:"sessionId"`
+	// Time the call was received.
+	Started time.Time `json:"started"`
+
+	cancel context.CancelFunc
+}
+
+// An OperationRegistry tracks in-flight tool calls so they can be cancelled.
+// Operations are kept per session, so a client can only see and cancel its
+// own calls.
+type OperationRegistry struct {
+	mu  sync.Mutex
+	ops map[string]map[string]*Operation // keys are session ID, then operation ID
+}
+
+// NewOperationRegistry creates an empty operation registry.
+func NewOperationRegistry() *OperationRegistry {
+	return &OperationRegistry{
+		ops: make(map[string]map[string]*Operation),
+	}
+}
+
+// Operations returns a session's in-flight operations, oldest first.
+func (r *OperationRegistry) Operations(sessionID string) []Operation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ops := make([]Operation, 0, len(r.ops[sessionID]))
+	for _, op := range r.ops[sessionID] {
+		ops = append(ops, *op)
+	}
+	slices.SortFunc(ops, func(a, b Operation) int { return a.Started.Compare(b.Started) })
+	return ops
+}
+
+// Cancel cancels the context of one of a session's in-flight operations,
+// reporting whether it was found.
+func (r *OperationRegistry) Cancel(sessionID, id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op, exists := r.ops[sessionID][id]
+	if !exists {
+		return false
+	}
+	op.cancel()
+	r.removeLocked(sessionID, id)
+	return true
+}
+
+// removeLocked forgets an operation; r.mu must be held.
+func (r *OperationRegistry) removeLocked(sessionID, id string) {
+	delete(r.ops[sessionID], id)
+	if len(r.ops[sessionID]) == 0 {
+		delete(r.ops, sessionID)
+	}
+}
+
+// Middleware returns receiving middleware that registers each tools/call for
+// the duration of its handler, giving it a cancellable context.
+func (r *OperationRegistry) Middleware() mcp.Middleware[*mcp.ServerSession] {
+	return func(next mcp.MethodHandler[*mcp.ServerSession]) mcp.MethodHandler[*mcp.ServerSession] {
+		return func(ctx context.Context, ss *mcp.ServerSession, method string, params mcp.Params) (mcp.Result, error) {
+			p, ok := params.(*mcp.CallToolParamsFor[json.RawMessage])
+			if method != "tools/call" || !ok || p.Name == "list_operations" || p.Name == "cancel_operation" {
+				return next(ctx, ss, method, params)
+			}
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			op := &Operation{
+				ID:        generateUID(),
+				Tool:      p.Name,
+				SessionID: ss.ID(),
+				Started:   time.Now(),
+				cancel:    cancel,
+			}
+			r.mu.Lock()
+			if r.ops[op.SessionID] == nil {
+				r.ops[op.SessionID] = make(map[string]*Operation)
+			}
+			r.ops[op.SessionID][op.ID] = op
+			r.mu.Unlock()
+
+			defer func() {
+				r.mu.Lock()
+				r.removeLocked(op.SessionID, op.ID)
+				r.mu.Unlock()
+			}()
+
+			return next(ctx, ss, method, params)
+		}
+	}
+}
+
+var operations = NewOperationRegistry()
+
+// CancelOperationArgs are the arguments for cancelling an in-flight operation.
+type CancelOperationArgs struct {
+	OperationID string `json:"operationId" mcp:"ID of the operation to cancel, as shown by list_operations"`
+}
+
+// ListOperations lists the caller's tool calls that are currently in flight.
+func ListOperations(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[any], error) {
+	ops := operations.Operations(ss.ID())
+	if len(ops) == 0 {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: "No operations in flight"},
+			},
+		}, nil
+	}
+
+	var list strings.Builder
+	fmt.Fprintf(&list, "%d operations in flight:\n", len(ops))
+	for _, op := range ops {
+		fmt.Fprintf(&list, "- %s: %s (running for %s)\n", op.ID, op.Tool, time.Since(op.Started).Round(time.Second))
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: list.String()},
+		},
+	}, nil
+}
+
+// CancelOperation cancels the context of one of the caller's in-flight tool calls.
+func CancelOperation(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CancelOperationArgs]) (*mcp.CallToolResultFor[any], error) {
+	id := params.Arguments.OperationID
+	if !operations.Cancel(ss.ID(), id) {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("Operation %s not found or already finished", id)},
+			},
+			IsError: true,
+		}, nil
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Cancelled operation %s", id)},
+		},
+	}, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestOperationRegistryScopedToSession(t *testing.T) {
+	r := NewOperationRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.ops["session-a"] = map[string]*Operation{
+		"op1": {ID: "op1", Tool: "get_pod", SessionID: "session-a", Started: time.Now(), cancel: cancel},
+	}
+
+	if ops := r.Operations("session-b"); len(ops) != 0 {
+		t.Errorf("Operations(session-b) = %v, want none", ops)
+	}
+	if r.Cancel("session-b", "op1") {
+		t.Error("Cancel(session-b, op1) = true, want false for another session's operation")
+	}
+	if ctx.Err() != nil {
+		t.Fatal("another session cancelled the operation")
+	}
+
+	if ops := r.Operations("session-a"); len(ops) != 1 || ops[0].ID != "op1" {
+		t.Errorf("Operations(session-a) = %v, want [op1]", ops)
+	}
+	if !r.Cancel("session-a", "op1") {
+		t.Error("Cancel(session-a, op1) = false, want true")
+	}
+	if ctx.Err() == nil {
+		t.Error("operation context not cancelled")
+	}
+	if _, exists := r.ops["session-a"]; exists {
+		t.Error("session entry kept after its last operation was cancelled")
+	}
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"maps"
 	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -24,6 +28,10 @@ type Thought struct {
 	Created time.Time `json:"created"`
 	// Whether the thought has been revised.
 	Revised bool `json:"revised"`
+	// Earlier wordings of a revised thought, oldest first.
+	PreviousVersions []string `json:"previousVersions,omitempty"`
+	// ID of the branch session the thought was merged from, if any.
+	Origin string `json:"origin,omitempty"`
 	// Index of parent thought, or nil if this is a root for branching.
 	ParentIndex *int `json:"parentIndex,omitempty"`
 }
@@ -41,15 +49,44 @@ type ThinkingSession struct {
 	// Estimated total number of thoughts.
 	EstimatedTotal int `json:"estimatedTotal"`
 	// Status of the session.
-	Status string `json:"status"` // "active", "completed", "paused"
+	Status string `json:"status"` // "active", "completed", "paused", "timed_out"
 	// Time the session was created.
 	Created time.Time `json:"created"`
 	// Time the session was last active.
 	LastActivity time.Time `json:"lastActivity"`
 	// Branches in the session. Alternative thought paths.
 	Branches []string `json:"branches,omitempty"`
+	// For a branch, the step of the parent session it continues from.
+	BranchPoint *int `json:"branchPoint,omitempty"`
 	// Version for optimistic concurrency control.
 	Version int `json:"version"`
+	// Final summary recorded when the session is completed.
+	Conclusion string `json:"conclusion,omitempty"`
+	// Time after which no more thoughts are accepted, if the session is time-boxed.
+	Deadline *time.Time `json:"deadline,omitempty"`
+}
+
+// checkDeadline marks the session as timed out once its deadline has passed.
+// It returns an error if the session no longer accepts thoughts.
+func (s *ThinkingSession) checkDeadline(now time.Time) error {
+	if s.Deadline != nil && now.After(*s.Deadline) {
+		s.Status = "timed_out"
+	}
+	if s.Status != "timed_out" {
+		return nil
+	}
+	if s.Deadline == nil {
+		return fmt.Errorf("session %s timed out; conclude or start a new session", s.ID)
+	}
+	return fmt.Errorf("session %s timed out at %s; conclude or start a new session", s.ID, s.Deadline.Format(time.RFC3339))
+}
+
+// checkPaused returns an error if the session is paused.
+func (s *ThinkingSession) checkPaused() error {
+	if s.Status == "paused" {
+		return fmt.Errorf("session %s is paused; resume it with resume_thinking before continuing", s.ID)
+	}
+	return nil
 }
 
 // clone returns a deep copy of the ThinkingSession.
@@ -60,7 +97,8 @@ func (s *ThinkingSession) clone() *ThinkingSession {
 	return &sessionCopy
 }
 
-// A SessionStore is a global session store (in a real implementation, this might be a database).
+// A SessionStore is a global session store. Sessions are held in memory and,
+// when the store has a backend, handed to it on every change.
 //
 // Locking Strategy:
 // The SessionStore uses a RWMutex to protect the sessions map from concurrent access.
@@ -70,18 +108,38 @@ func (s *ThinkingSession) clone() *ThinkingSession {
 // - Write locks protect map modifications (adding/removing/replacing sessions)
 // - Session field modifications always happen on local copies via CompareAndSwap
 // - No shared ThinkingSession state is ever modified directly
+// - Backend writes happen under the write lock, before the map is updated
 type SessionStore struct {
-	mu       sync.RWMutex
-	sessions map[string]*ThinkingSession // key is session ID
+	mu         sync.RWMutex
+	sessions   map[string]*ThinkingSession // key is session ID
+	backend    sessionBackend              // nil for an in-memory store
+	maxRetries int                         // CompareAndSwap attempts after a version mismatch
 }
 
+// DefaultMaxRetries is how many times CompareAndSwap retries after a version
+// mismatch before giving up. It is far beyond what normal contention needs.
+const DefaultMaxRetries = 1000
+
+// ErrTooManyRetries is returned by CompareAndSwap when the session kept
+// changing underneath it for more than the store's retry limit.
+var ErrTooManyRetries = errors.New("too many concurrent updates")
+
 // NewSessionStore creates a new session store for managing thinking sessions.
 func NewSessionStore() *SessionStore {
 	return &SessionStore{
-		sessions: make(map[string]*ThinkingSession),
+		sessions:   make(map[string]*ThinkingSession),
+		maxRetries: DefaultMaxRetries,
 	}
 }
 
+// SetMaxRetries sets how many times CompareAndSwap retries after a version
+// mismatch. Zero means a single attempt with no retries.
+func (s *SessionStore) SetMaxRetries(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxRetries = max(n, 0)
+}
+
 // Session retrieves a thinking session by ID, returning the session and whether it exists.
 func (s *SessionStore) Session(id string) (*ThinkingSession, bool) {
 	s.mu.RLock()
@@ -91,10 +149,16 @@ func (s *SessionStore) Session(id string) (*ThinkingSession, bool) {
 }
 
 // SetSession stores or updates a thinking session in the store.
-func (s *SessionStore) SetSession(session *ThinkingSession) {
+func (s *SessionStore) SetSession(session *ThinkingSession) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.backend != nil {
+		if err := s.backend.Save(session); err != nil {
+			return err
+		}
+	}
 	s.sessions[session.ID] = session
+	return nil
 }
 
 // CompareAndSwap atomically updates a session if the version matches.
@@ -105,11 +169,13 @@ func (s *SessionStore) SetSession(session *ThinkingSession) {
 // 2. Deep copy the session (all modifications happen on this copy)
 // 3. Release read lock and apply updates to the copy
 // 4. Write lock to check version and atomically update if unchanged
+// 5. On a version mismatch, back off briefly and retry, up to the store's
+//    retry limit, after which ErrTooManyRetries is returned
 //
 // The read lock in step 1 is necessary to prevent map access races,
 // not to protect ThinkingSession fields (which are never modified in-place).
 func (s *SessionStore) CompareAndSwap(sessionID string, updateFunc func(*ThinkingSession) (*ThinkingSession, error)) error {
-	for {
+	for attempt := 0; ; attempt++ {
 		// Get current session
 		s.mu.RLock()
 		current, exists := s.sessions[sessionID]
@@ -137,16 +203,52 @@ func (s *SessionStore) CompareAndSwap(sessionID string, updateFunc func(*Thinkin
 		}
 		if current.Version != oldVersion {
 			// Version mismatch, retry
+			maxRetries := s.maxRetries
 			s.mu.Unlock()
+			if attempt >= maxRetries {
+				return fmt.Errorf("session %s: %w", sessionID, ErrTooManyRetries)
+			}
+			// Back off a little longer each time so contending writers spread out
+			time.Sleep(time.Duration(min(attempt+1, 100)) * time.Microsecond)
 			continue
 		}
 		updated.Version = oldVersion + 1
+		if s.backend != nil {
+			if err := s.backend.Save(updated); err != nil {
+				s.mu.Unlock()
+				return err
+			}
+		}
 		s.sessions[sessionID] = updated
 		s.mu.Unlock()
 		return nil
 	}
 }
 
+// DeleteSession removes a thinking session from the store, reporting whether
+// it existed. A backend failure is logged rather than returned; the session
+// is still removed from memory.
+func (s *SessionStore) DeleteSession(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.sessions[id]; !exists {
+		return false
+	}
+	s.deleteLocked(id)
+	return true
+}
+
+// deleteLocked removes a session; the caller must hold the write lock.
+func (s *SessionStore) deleteLocked(id string) {
+	if s.backend != nil {
+		if err := s.backend.Delete(id); err != nil {
+			log.Printf("[WARN]: %v", err)
+		}
+	}
+	delete(s.sessions, id)
+}
+
 // Sessions returns all thinking sessions in the store.
 func (s *SessionStore) Sessions() []*ThinkingSession {
 	s.mu.RLock()
@@ -180,13 +282,74 @@ func (s *SessionStore) SessionSnapshot(id string) (*ThinkingSession, bool) {
 	return session.clone(), true
 }
 
-var store1 = NewSessionStore()
+// ReplaceSessions atomically replaces the contents of the store with the given sessions.
+func (s *SessionStore) ReplaceSessions(sessions []*ThinkingSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	replaced := make(map[string]*ThinkingSession, len(sessions))
+	for _, session := range sessions {
+		replaced[session.ID] = session
+	}
+
+	if s.backend != nil {
+		for id := range s.sessions {
+			if _, kept := replaced[id]; kept {
+				continue
+			}
+			if err := s.backend.Delete(id); err != nil {
+				return err
+			}
+		}
+		for _, session := range replaced {
+			if err := s.backend.Save(session); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.sessions = replaced
+	return nil
+}
+
+// Close flushes any changes the backend still buffers and releases it. The
+// store must not be changed afterwards.
+func (s *SessionStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.backend == nil {
+		return nil
+	}
+	return s.backend.Close()
+}
+
+// Store is the session storage the thinking tools work against.
+// SessionStore implements it; other implementations can keep sessions
+// elsewhere, such as in a database.
+type Store interface {
+	Session(id string) (*ThinkingSession, bool)
+	SetSession(session *ThinkingSession) error
+	CompareAndSwap(sessionID string, updateFunc func(*ThinkingSession) (*ThinkingSession, error)) error
+	SessionSnapshot(id string) (*ThinkingSession, bool)
+	SessionsSnapshot() []*ThinkingSession
+	DeleteSession(id string) bool
+	ReplaceSessions(sessions []*ThinkingSession) error
+}
+
+var _ Store = (*SessionStore)(nil)
+
+// sequentialThinking provides the thinking tools over a session store.
+type sequentialThinking struct {
+	store Store
+}
 
 // StartThinkingArgs are the arguments for starting a new thinking session.
 type StartThinkingArgs struct {
 	Problem        string `json:"problem"`
 	SessionID      string `json:"sessionId,omitempty"`
 	EstimatedSteps int    `json:"estimatedSteps,omitempty"`
+	Deadline       string `json:"deadline,omitempty"`    // RFC 3339 timestamp
+	MaxDuration    string `json:"maxDuration,omitempty"` // Go duration, e.g. "10m"
 }
 
 // ContinueThinkingArgs are the arguments for continuing a thinking session.
@@ -196,6 +359,7 @@ type ContinueThinkingArgs struct {
 	NextNeeded     *bool  `json:"nextNeeded,omitempty"`
 	ReviseStep     *int   `json:"reviseStep,omitempty"`
 	CreateBranch   bool   `json:"createBranch,omitempty"`
+	FromStep       *int   `json:"fromStep,omitempty"` // branch point; defaults to the last step
 	EstimatedTotal int    `json:"estimatedTotal,omitempty"`
 }
 
@@ -204,23 +368,105 @@ type ReviewThinkingArgs struct {
 	SessionID string `json:"sessionId"`
 }
 
+// UndoRevisionArgs are the arguments for undoing the latest revision of a thought.
+type UndoRevisionArgs struct {
+	SessionID string `json:"sessionId"`
+	Step      int    `json:"step"`
+}
+
+// DeleteThoughtArgs are the arguments for deleting a thought from a session.
+type DeleteThoughtArgs struct {
+	SessionID string `json:"sessionId"`
+	Step      int    `json:"step"`
+}
+
+// SearchThinkingArgs are the arguments for searching thoughts across sessions.
+type SearchThinkingArgs struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit,omitempty"` // defaults to defaultSearchLimit
+}
+
+const (
+	defaultSearchLimit = 20
+	// searchContext is how many bytes of context are shown around a match
+	searchContext = 40
+)
+
+// A thoughtMatch is a thought found by SearchThinking.
+type thoughtMatch struct {
+	sessionID string
+	index     int
+	score     int
+	snippet   string
+}
+
+// ThinkingGraphArgs are the arguments for rendering a session's branch tree.
+type ThinkingGraphArgs struct {
+	SessionID string `json:"sessionId"`
+}
+
+// graphLabelLength is how many characters of a thought label a graph node.
+const graphLabelLength = 40
+
+// MergeBranchArgs are the arguments for merging a branch into its parent session.
+type MergeBranchArgs struct {
+	SessionID      string `json:"sessionId"`
+	BranchID       string `json:"branchId"`
+	CompleteBranch bool   `json:"completeBranch,omitempty"`
+}
+
+// CompleteThinkingArgs are the arguments for completing a thinking session.
+type CompleteThinkingArgs struct {
+	SessionID  string `json:"sessionId"`
+	Conclusion string `json:"conclusion"`
+	Force      bool   `json:"force,omitempty"` // overwrite the conclusion of a completed session
+}
+
+// PauseThinkingArgs are the arguments for pausing a thinking session.
+type PauseThinkingArgs struct {
+	SessionID string `json:"sessionId"`
+}
+
+// ResumeThinkingArgs are the arguments for resuming a paused thinking session.
+type ResumeThinkingArgs struct {
+	SessionID string `json:"sessionId"`
+}
+
+// ThinkingStatsArgs are the arguments for reporting a session's timing.
+type ThinkingStatsArgs struct {
+	SessionID string `json:"sessionId"`
+}
+
 // ThinkingHistoryArgs are the arguments for retrieving thinking history.
 type ThinkingHistoryArgs struct {
 	SessionID string `json:"sessionId"`
 }
 
+// RestoreThinkingArgs are the arguments for restoring the thinking store from a snapshot.
+type RestoreThinkingArgs struct {
+	Snapshot string `json:"snapshot"`
+	Replace  bool   `json:"replace,omitempty"`
+	IDPrefix string `json:"idPrefix,omitempty"`
+}
+
+// DeleteThinkingArgs are the arguments for deleting a thinking session.
+type DeleteThinkingArgs struct {
+	SessionID string `json:"sessionId"`
+}
+
 // deepCopyThoughts creates a deep copy of a slice of thoughts.
 func deepCopyThoughts(thoughts []*Thought) []*Thought {
 	thoughtsCopy := make([]*Thought, len(thoughts))
 	for i, t := range thoughts {
 		t2 := *t
+		t2.PreviousVersions = slices.Clone(t.PreviousVersions)
 		thoughtsCopy[i] = &t2
 	}
 	return thoughtsCopy
 }
 
 // StartThinking begins a new sequential thinking session for a complex problem.
-func StartThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[StartThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
+func (t sequentialThinking) StartThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[StartThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
 	args := params.Arguments
 
 	sessionID := args.SessionID
@@ -233,47 +479,91 @@ func StartThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallT
 		estimatedSteps = 5 // Default estimate
 	}
 
+	now := time.Now()
+
+	// Use the earlier of an absolute deadline and a maximum duration
+	var deadline *time.Time
+	if args.Deadline != "" {
+		at, err := time.Parse(time.RFC3339, args.Deadline)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deadline %q: must be an RFC 3339 timestamp", args.Deadline)
+		}
+		deadline = &at
+	}
+	if args.MaxDuration != "" {
+		d, err := time.ParseDuration(args.MaxDuration)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid maxDuration %q: must be a positive duration such as 10m", args.MaxDuration)
+		}
+		if at := now.Add(d); deadline == nil || at.Before(*deadline) {
+			deadline = &at
+		}
+	}
+
 	session := &ThinkingSession{
 		ID:             sessionID,
 		Problem:        args.Problem,
 		EstimatedTotal: estimatedSteps,
 		Status:         "active",
-		Created:        time.Now(),
-		LastActivity:   time.Now(),
+		Created:        now,
+		LastActivity:   now,
+		Deadline:       deadline,
 	}
 
-	store1.SetSession(session)
+	if err := t.store.SetSession(session); err != nil {
+		return nil, err
+	}
+
+	timeBox := ""
+	if deadline != nil {
+		timeBox = fmt.Sprintf("\nDeadline: %s", deadline.Format(time.RFC3339))
+	}
 
 	return &mcp.CallToolResultFor[any]{
 		Content: []mcp.Content{
 			&mcp.TextContent{
-				Text: fmt.Sprintf("Started thinking session '%s' for problem: %s\nEstimated steps: %d\nReady for your first thought.",
-					sessionID, args.Problem, estimatedSteps),
+				Text: fmt.Sprintf("Started thinking session '%s' for problem: %s\nEstimated steps: %d%s\nReady for your first thought.",
+					sessionID, args.Problem, estimatedSteps, timeBox),
 			},
 		},
 	}, nil
 }
 
 // ContinueThinking adds the next thought step, revises a previous step, or creates a branch in the thinking process.
-func ContinueThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ContinueThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
+func (t sequentialThinking) ContinueThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ContinueThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
 	args := params.Arguments
 
+	// A session past its deadline is saved as timed out, then the call is refused
+	var deadlineErr error
+
 	// Handle revision of existing thought
 	if args.ReviseStep != nil {
-		err := store1.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+		err := t.store.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+			if err := session.checkPaused(); err != nil {
+				return nil, err
+			}
+			if deadlineErr = session.checkDeadline(time.Now()); deadlineErr != nil {
+				return session, nil
+			}
+
 			stepIndex := *args.ReviseStep - 1
 			if stepIndex < 0 || stepIndex >= len(session.Thoughts) {
 				return nil, fmt.Errorf("invalid step number: %d", *args.ReviseStep)
 			}
 
-			session.Thoughts[stepIndex].Content = args.Thought
-			session.Thoughts[stepIndex].Revised = true
+			thought := session.Thoughts[stepIndex]
+			thought.PreviousVersions = append(thought.PreviousVersions, thought.Content)
+			thought.Content = args.Thought
+			thought.Revised = true
 			session.LastActivity = time.Now()
 			return session, nil
 		})
 		if err != nil {
 			return nil, err
 		}
+		if deadlineErr != nil {
+			return nil, deadlineErr
+		}
 
 		return &mcp.CallToolResultFor[any]{
 			Content: []mcp.Content{
@@ -290,19 +580,34 @@ func ContinueThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.Ca
 		var branchID string
 		var branchSession *ThinkingSession
 
-		err := store1.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+		err := t.store.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+			if err := session.checkPaused(); err != nil {
+				return nil, err
+			}
+
+			fromStep := len(session.Thoughts)
+			if args.FromStep != nil {
+				fromStep = *args.FromStep
+				if fromStep < 1 || fromStep > len(session.Thoughts) {
+					return nil, fmt.Errorf("invalid fromStep %d: session has %d steps", fromStep, len(session.Thoughts))
+				}
+			}
+
 			branchID = fmt.Sprintf("%s_branch_%d", args.SessionID, len(session.Branches)+1)
 			session.Branches = append(session.Branches, branchID)
 			session.LastActivity = time.Now()
 
-			// Create a new session for the branch (deep copy thoughts)
-			thoughtsCopy := deepCopyThoughts(session.Thoughts)
+			// Create a new session for the branch, seeded with the thoughts up
+			// to the branch point (deep copy thoughts)
+			thoughtsCopy := deepCopyThoughts(session.Thoughts[:fromStep])
 			branchSession = &ThinkingSession{
 				ID:             branchID,
 				Problem:        session.Problem + " (Alternative branch)",
 				Thoughts:       thoughtsCopy,
-				CurrentThought: len(session.Thoughts),
+				CurrentThought: fromStep,
+				BranchPoint:    &fromStep,
 				EstimatedTotal: session.EstimatedTotal,
+				Deadline:       session.Deadline,
 				Status:         "active",
 				Created:        time.Now(),
 				LastActivity:   time.Now(),
@@ -315,13 +620,15 @@ func ContinueThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.Ca
 		}
 
 		// Save the branch session
-		store1.SetSession(branchSession)
+		if err := t.store.SetSession(branchSession); err != nil {
+			return nil, err
+		}
 
 		return &mcp.CallToolResultFor[any]{
 			Content: []mcp.Content{
 				&mcp.TextContent{
-					Text: fmt.Sprintf("Created branch '%s' from session '%s'. You can now continue thinking in either session.",
-						branchID, args.SessionID),
+					Text: fmt.Sprintf("Created branch '%s' from session '%s' after step %d. You can now continue thinking in either session.",
+						branchID, args.SessionID, *branchSession.BranchPoint),
 				},
 			},
 		}, nil
@@ -332,7 +639,14 @@ func ContinueThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.Ca
 	var progress string
 	var statusMsg string
 
-	err := store1.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+	err := t.store.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+		if err := session.checkPaused(); err != nil {
+			return nil, err
+		}
+		if deadlineErr = session.checkDeadline(time.Now()); deadlineErr != nil {
+			return session, nil
+		}
+
 		thoughtID = len(session.Thoughts) + 1
 		thought := &Thought{
 			Index:   thoughtID,
@@ -340,6 +654,10 @@ func ContinueThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.Ca
 			Created: time.Now(),
 			Revised: false,
 		}
+		// The first thought of a branch continues from the branch point
+		if session.BranchPoint != nil && len(session.Thoughts) == *session.BranchPoint {
+			thought.ParentIndex = session.BranchPoint
+		}
 
 		session.Thoughts = append(session.Thoughts, thought)
 		session.CurrentThought = thoughtID
@@ -365,6 +683,15 @@ func ContinueThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.Ca
 			statusMsg = "\n✓ Thinking process completed!"
 		} else {
 			statusMsg = "\nReady for next thought..."
+			// The estimate is advisory; going past it only earns a nudge
+			if over := thoughtID - session.EstimatedTotal; session.EstimatedTotal > 0 && over > 0 {
+				steps := "steps"
+				if over == 1 {
+					steps = "step"
+				}
+				statusMsg += fmt.Sprintf("\n⚠ You are %d %s over your estimate of %d; consider revising the estimate (estimatedTotal) or concluding.",
+					over, steps, session.EstimatedTotal)
+			}
 		}
 
 		return session, nil
@@ -372,6 +699,9 @@ func ContinueThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.Ca
 	if err != nil {
 		return nil, err
 	}
+	if deadlineErr != nil {
+		return nil, deadlineErr
+	}
 
 	return &mcp.CallToolResultFor[any]{
 		Content: []mcp.Content{
@@ -383,12 +713,539 @@ func ContinueThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.Ca
 	}, nil
 }
 
+// UndoRevision restores the wording a thought had before its latest revision.
+func (t sequentialThinking) UndoRevision(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[UndoRevisionArgs]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	var restored string
+	var remaining int
+	var deadlineErr error
+	err := t.store.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+		if err := session.checkPaused(); err != nil {
+			return nil, err
+		}
+		if deadlineErr = session.checkDeadline(time.Now()); deadlineErr != nil {
+			return session, nil
+		}
+
+		stepIndex := args.Step - 1
+		if stepIndex < 0 || stepIndex >= len(session.Thoughts) {
+			return nil, fmt.Errorf("invalid step number: %d", args.Step)
+		}
+		thought := session.Thoughts[stepIndex]
+		if len(thought.PreviousVersions) == 0 {
+			return nil, fmt.Errorf("step %d in session %s has no revisions to undo", args.Step, args.SessionID)
+		}
+
+		last := len(thought.PreviousVersions) - 1
+		thought.Content = thought.PreviousVersions[last]
+		thought.PreviousVersions = thought.PreviousVersions[:last]
+		thought.Revised = last > 0
+		session.LastActivity = time.Now()
+
+		restored = thought.Content
+		remaining = last
+		return session, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if deadlineErr != nil {
+		return nil, deadlineErr
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Restored step %d in session '%s' (%d earlier versions left):\n%s",
+					args.Step, args.SessionID, remaining, restored),
+			},
+		},
+	}, nil
+}
+
+// DeleteThought removes a thought from a session and renumbers the thoughts
+// after it, so indices stay contiguous.
+func (t sequentialThinking) DeleteThought(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[DeleteThoughtArgs]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	var deleted string
+	var remaining int
+	var deadlineErr error
+	err := t.store.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+		if err := session.checkPaused(); err != nil {
+			return nil, err
+		}
+		if deadlineErr = session.checkDeadline(time.Now()); deadlineErr != nil {
+			return session, nil
+		}
+
+		stepIndex := args.Step - 1
+		if stepIndex < 0 || stepIndex >= len(session.Thoughts) {
+			return nil, fmt.Errorf("invalid step number: %d", args.Step)
+		}
+
+		deleted = session.Thoughts[stepIndex].Content
+		session.Thoughts = slices.Delete(session.Thoughts, stepIndex, stepIndex+1)
+		for i, thought := range session.Thoughts[stepIndex:] {
+			thought.Index = stepIndex + i + 1
+		}
+		if session.CurrentThought >= args.Step {
+			session.CurrentThought--
+		}
+		session.LastActivity = time.Now()
+
+		remaining = len(session.Thoughts)
+		return session, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if deadlineErr != nil {
+		return nil, deadlineErr
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Deleted step %d from session '%s' (%d steps left):\n%s",
+					args.Step, args.SessionID, remaining, deleted),
+			},
+		},
+	}, nil
+}
+
+// SearchThinking finds thoughts in every session containing the query or any
+// of its words, case-insensitively. Matches are ranked by how often the words
+// occur, with an extra point per occurrence of the whole query.
+func (t sequentialThinking) SearchThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[SearchThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	query := strings.ToLower(strings.TrimSpace(args.Query))
+	tokens := strings.Fields(query)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("query is required")
+	}
+	limit := args.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	var matches []thoughtMatch
+	for _, session := range t.store.SessionsSnapshot() {
+		for _, thought := range session.Thoughts {
+			content := strings.ToLower(thought.Content)
+			score := 0
+			first := -1
+			for _, token := range tokens {
+				if n := strings.Count(content, token); n > 0 {
+					score += n
+					if at := strings.Index(content, token); first < 0 || at < first {
+						first = at
+					}
+				}
+			}
+			if score == 0 {
+				continue
+			}
+			if len(tokens) > 1 {
+				score += strings.Count(content, query)
+			}
+
+			// Lowercasing can change byte offsets outside ASCII
+			text := thought.Content
+			if len(text) != len(content) {
+				text = content
+			}
+			matches = append(matches, thoughtMatch{
+				sessionID: session.ID,
+				index:     thought.Index,
+				score:     score,
+				snippet:   snippet(text, first),
+			})
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b thoughtMatch) int {
+		if a.score != b.score {
+			return b.score - a.score
+		}
+		if a.sessionID != b.sessionID {
+			return strings.Compare(a.sessionID, b.sessionID)
+		}
+		return a.index - b.index
+	})
+
+	if len(matches) == 0 {
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("No thoughts match %q", args.Query)},
+			},
+		}, nil
+	}
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "Found %d matching thoughts for %q", len(matches), args.Query)
+	if len(matches) > limit {
+		fmt.Fprintf(&result, " (showing %d)", limit)
+		matches = matches[:limit]
+	}
+	result.WriteString(":\n")
+	for _, match := range matches {
+		fmt.Fprintf(&result, "- %s step %d (score %d): %s\n", match.sessionID, match.index, match.score, match.snippet)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: result.String()},
+		},
+	}, nil
+}
+
+// snippet returns the part of text within searchContext bytes of offset at,
+// marking cut ends with an ellipsis.
+func snippet(text string, at int) string {
+	start := max(at-searchContext, 0)
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	end := min(at+searchContext, len(text))
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	result := strings.Join(strings.Fields(text[start:end]), " ")
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(text) {
+		result += "…"
+	}
+	return result
+}
+
+// ThinkingGraph renders a session and every branch descending from it as a
+// Graphviz DOT digraph. Each session is a cluster of its own thoughts; a
+// branch's first thought is linked to the thought it branched from.
+func (t sequentialThinking) ThinkingGraph(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ThinkingGraphArgs]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	sessions := make(map[string]*ThinkingSession)
+	for _, session := range t.store.SessionsSnapshot() {
+		sessions[session.ID] = session
+	}
+	root, exists := sessions[args.SessionID]
+	if !exists {
+		return nil, fmt.Errorf("session %s not found", args.SessionID)
+	}
+
+	var dot strings.Builder
+	dot.WriteString("digraph thinking {\n")
+	dot.WriteString("  rankdir=LR;\n  node [shape=box];\n")
+
+	cluster := 0
+	rendered := make(map[string]bool)
+	var render func(session *ThinkingSession, parent string)
+	render = func(session *ThinkingSession, parent string) {
+		// Branches lists come from clients via restore_thinking, so guard against cycles
+		if rendered[session.ID] {
+			return
+		}
+		rendered[session.ID] = true
+
+		// A branch repeats its parent's thoughts up to the branch point
+		branchPoint := 0
+		if session.BranchPoint != nil && parent != "" {
+			branchPoint = min(*session.BranchPoint, len(session.Thoughts))
+		}
+		own := session.Thoughts[branchPoint:]
+
+		fmt.Fprintf(&dot, "  subgraph cluster_%d {\n    label=%s;\n", cluster, dotQuote(session.ID))
+		cluster++
+		var first string
+		if len(own) == 0 {
+			first = dotQuote(session.ID + ":start")
+			fmt.Fprintf(&dot, "    %s [label=\"(no thoughts yet)\", shape=plaintext];\n", first)
+		}
+		for i, thought := range own {
+			node := dotQuote(fmt.Sprintf("%s:%d", session.ID, thought.Index))
+			fmt.Fprintf(&dot, "    %s [label=%s];\n", node, dotQuote(fmt.Sprintf("%d. %s", thought.Index, truncateLabel(thought.Content))))
+			if i == 0 {
+				first = node
+			} else {
+				fmt.Fprintf(&dot, "    %s -> %s;\n", dotQuote(fmt.Sprintf("%s:%d", session.ID, own[i-1].Index)), node)
+			}
+		}
+		dot.WriteString("  }\n")
+
+		if parent != "" {
+			from := branchPoint
+			if len(own) > 0 && own[0].ParentIndex != nil {
+				from = *own[0].ParentIndex
+			}
+			if from > 0 {
+				fmt.Fprintf(&dot, "  %s -> %s [style=dashed];\n", dotQuote(fmt.Sprintf("%s:%d", parent, from)), first)
+			} else {
+				fmt.Fprintf(&dot, "  %s -> %s [style=dashed];\n", dotQuote(parent+":root"), first)
+			}
+		}
+
+		for _, branchID := range childBranches(session, sessions) {
+			render(sessions[branchID], session.ID)
+		}
+	}
+
+	fmt.Fprintf(&dot, "  %s [label=%s, shape=ellipse];\n", dotQuote(root.ID+":root"), dotQuote(truncateLabel(root.Problem)))
+	if len(root.Thoughts) > 0 {
+		fmt.Fprintf(&dot, "  %s -> %s;\n", dotQuote(root.ID+":root"), dotQuote(fmt.Sprintf("%s:%d", root.ID, root.Thoughts[0].Index)))
+	}
+	render(root, "")
+	dot.WriteString("}\n")
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: dot.String()},
+		},
+	}, nil
+}
+
+// childBranches returns the IDs of the existing sessions branched directly
+// from session, found through its Branches field and the <id>_branch_N naming.
+func childBranches(session *ThinkingSession, sessions map[string]*ThinkingSession) []string {
+	children := make(map[string]bool)
+	for _, id := range session.Branches {
+		if _, exists := sessions[id]; exists {
+			children[id] = true
+		}
+	}
+	prefix := session.ID + "_branch_"
+	for id := range sessions {
+		if n, ok := strings.CutPrefix(id, prefix); ok {
+			if _, err := strconv.Atoi(n); err == nil {
+				children[id] = true
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(children))
+}
+
+// dotQuote quotes s as a DOT identifier.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+// truncateLabel shortens s to graphLabelLength characters for a node label.
+func truncateLabel(s string) string {
+	if utf8.RuneCountInString(s) <= graphLabelLength {
+		return s
+	}
+	return string([]rune(s)[:graphLabelLength]) + "…"
+}
+
+// MergeBranch appends the thoughts a branch added after its branch point to
+// the parent session, tagged with the branch as their origin. The branch is
+// left in place, and is marked completed if requested.
+func (t sequentialThinking) MergeBranch(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[MergeBranchArgs]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	branch, exists := t.store.SessionSnapshot(args.BranchID)
+	if !exists {
+		return nil, fmt.Errorf("branch %s not found", args.BranchID)
+	}
+
+	var merged int
+	var deadlineErr error
+	err := t.store.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+		if !slices.Contains(childBranches(session, map[string]*ThinkingSession{branch.ID: branch}), branch.ID) {
+			return nil, fmt.Errorf("session %s is not a branch of %s", branch.ID, session.ID)
+		}
+		if err := session.checkPaused(); err != nil {
+			return nil, err
+		}
+		if deadlineErr = session.checkDeadline(time.Now()); deadlineErr != nil {
+			return session, nil
+		}
+
+		if slices.ContainsFunc(session.Thoughts, func(thought *Thought) bool { return thought.Origin == branch.ID }) {
+			return nil, fmt.Errorf("branch %s has already been merged into %s", branch.ID, session.ID)
+		}
+
+		divergence := branch.Thoughts[forkPoint(session, branch):]
+		if len(divergence) == 0 {
+			return nil, fmt.Errorf("branch %s has no thoughts to merge", branch.ID)
+		}
+
+		now := time.Now()
+		for _, thought := range deepCopyThoughts(divergence) {
+			thought.Index = len(session.Thoughts) + 1
+			thought.ParentIndex = nil
+			thought.Origin = branch.ID
+			session.Thoughts = append(session.Thoughts, thought)
+		}
+		session.CurrentThought = len(session.Thoughts)
+		session.LastActivity = now
+
+		merged = len(divergence)
+		return session, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if deadlineErr != nil {
+		return nil, deadlineErr
+	}
+
+	text := fmt.Sprintf("Merged %d thoughts from branch '%s' into session '%s'", merged, args.BranchID, args.SessionID)
+	if args.CompleteBranch {
+		err := t.store.CompareAndSwap(args.BranchID, func(session *ThinkingSession) (*ThinkingSession, error) {
+			session.Status = "completed"
+			session.LastActivity = time.Now()
+			return session, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("merged, but failed to complete branch: %w", err)
+		}
+		text += fmt.Sprintf(" and marked '%s' completed", args.BranchID)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text + "."},
+		},
+	}, nil
+}
+
+// forkPoint returns how many of branch's leading thoughts were copied from
+// parent when the branch was created. Branches created before BranchPoint was
+// recorded are matched against the parent thought by thought.
+func forkPoint(parent, branch *ThinkingSession) int {
+	if branch.BranchPoint != nil {
+		return min(*branch.BranchPoint, len(branch.Thoughts))
+	}
+	n := 0
+	for n < len(branch.Thoughts) && n < len(parent.Thoughts) &&
+		branch.Thoughts[n].Created.Equal(parent.Thoughts[n].Created) {
+		n++
+	}
+	return n
+}
+
+// CompleteThinking marks a session completed and records its conclusion. Paused
+// and timed-out sessions can be completed; a completed one only with force.
+func (t sequentialThinking) CompleteThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[CompleteThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	if strings.TrimSpace(args.Conclusion) == "" {
+		return nil, fmt.Errorf("conclusion is required")
+	}
+
+	var overwritten bool
+	err := t.store.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+		if session.Status == "completed" && !args.Force {
+			return nil, fmt.Errorf("session %s is already completed; set force to overwrite its conclusion", session.ID)
+		}
+
+		overwritten = session.Conclusion != ""
+		session.Status = "completed"
+		session.Conclusion = args.Conclusion
+		session.LastActivity = time.Now()
+		return session, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	action := "Completed"
+	if overwritten {
+		action = "Updated the conclusion of"
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("%s thinking session '%s'.\nConclusion: %s", action, args.SessionID, args.Conclusion),
+			},
+		},
+	}, nil
+}
+
+// PauseThinking moves an active session to paused. A paused session accepts
+// no thoughts until it is resumed; its deadline, if any, keeps running.
+func (t sequentialThinking) PauseThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[PauseThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	var deadlineErr error
+	err := t.store.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+		if deadlineErr = session.checkDeadline(time.Now()); deadlineErr != nil {
+			return session, nil
+		}
+		if session.Status != "active" {
+			return nil, fmt.Errorf("session %s is %s; only active sessions can be paused", session.ID, session.Status)
+		}
+
+		session.Status = "paused"
+		session.LastActivity = time.Now()
+		return session, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if deadlineErr != nil {
+		return nil, deadlineErr
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Paused thinking session '%s'. Use resume_thinking to continue.", args.SessionID),
+			},
+		},
+	}, nil
+}
+
+// ResumeThinking moves a paused session back to active.
+func (t sequentialThinking) ResumeThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ResumeThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	var deadlineErr error
+	err := t.store.CompareAndSwap(args.SessionID, func(session *ThinkingSession) (*ThinkingSession, error) {
+		if session.Status != "paused" {
+			return nil, fmt.Errorf("session %s is %s, not paused", session.ID, session.Status)
+		}
+
+		// The deadline may have passed while the session was paused
+		session.Status = "active"
+		if deadlineErr = session.checkDeadline(time.Now()); deadlineErr != nil {
+			return session, nil
+		}
+
+		session.LastActivity = time.Now()
+		return session, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if deadlineErr != nil {
+		return nil, deadlineErr
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Resumed thinking session '%s'. Ready for your next thought.", args.SessionID),
+			},
+		},
+	}, nil
+}
+
 // ReviewThinking provides a complete review of the thinking process for a session.
-func ReviewThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ReviewThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
+func (t sequentialThinking) ReviewThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ReviewThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
 	args := params.Arguments
 
 	// Get a snapshot of the session to avoid race conditions
-	sessionSnapshot, exists := store1.SessionSnapshot(args.SessionID)
+	sessionSnapshot, exists := t.store.SessionSnapshot(args.SessionID)
 	if !exists {
 		return nil, fmt.Errorf("session %s not found", args.SessionID)
 	}
@@ -397,8 +1254,32 @@ func ReviewThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.Call
 	fmt.Fprintf(&review, "=== Thinking Review: %s ===\n", sessionSnapshot.ID)
 	fmt.Fprintf(&review, "Problem: %s\n", sessionSnapshot.Problem)
 	fmt.Fprintf(&review, "Status: %s\n", sessionSnapshot.Status)
+	if sessionSnapshot.Conclusion != "" {
+		fmt.Fprintf(&review, "Conclusion: %s\n", sessionSnapshot.Conclusion)
+	}
 	fmt.Fprintf(&review, "Steps: %d of ~%d\n", len(sessionSnapshot.Thoughts), sessionSnapshot.EstimatedTotal)
 
+	stats := sessionTimings(sessionSnapshot)
+	fmt.Fprintf(&review, "Duration: %s\n", formatDuration(stats.Duration))
+	if stats.HasAverage {
+		fmt.Fprintf(&review, "Average time between thoughts: %s\n", formatDuration(stats.AverageGap))
+	}
+
+	if sessionSnapshot.Deadline != nil {
+		switch {
+		case sessionSnapshot.Status == "timed_out":
+			fmt.Fprintf(&review, "Deadline: %s (timed out)\n", sessionSnapshot.Deadline.Format(time.RFC3339))
+		case time.Now().After(*sessionSnapshot.Deadline) && sessionSnapshot.Status == "active":
+			fmt.Fprintf(&review, "Deadline: %s (passed)\n", sessionSnapshot.Deadline.Format(time.RFC3339))
+		default:
+			fmt.Fprintf(&review, "Deadline: %s\n", sessionSnapshot.Deadline.Format(time.RFC3339))
+		}
+	}
+
+	if sessionSnapshot.BranchPoint != nil {
+		fmt.Fprintf(&review, "Branched after step %d\n", *sessionSnapshot.BranchPoint)
+	}
+
 	if len(sessionSnapshot.Branches) > 0 {
 		fmt.Fprintf(&review, "Branches: %s\n", strings.Join(sessionSnapshot.Branches, ", "))
 	}
@@ -410,7 +1291,16 @@ func ReviewThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.Call
 		if thought.Revised {
 			status = " (revised)"
 		}
+		if thought.Origin != "" {
+			status += fmt.Sprintf(" (from %s)", thought.Origin)
+		}
+		if gap := stats.Gaps[i]; gap >= 0 {
+			status += fmt.Sprintf(" [+%s]", formatDuration(gap))
+		}
 		fmt.Fprintf(&review, "%d. %s%s\n", i+1, thought.Content, status)
+		for j, previous := range thought.PreviousVersions {
+			fmt.Fprintf(&review, "   v%d: %s\n", j+1, previous)
+		}
 	}
 
 	return &mcp.CallToolResultFor[any]{
@@ -422,8 +1312,104 @@ func ReviewThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.Call
 	}, nil
 }
 
+// sessionStats holds timing figures derived from a session's timestamps.
+type sessionStats struct {
+	// Duration from the session's creation to its last activity.
+	Duration time.Duration
+	// Average time between consecutive thoughts; valid only if HasAverage.
+	AverageGap time.Duration
+	HasAverage bool
+	// Time before each thought, measured from the previous thought or, for
+	// the first one, the session's start. Negative when unknown, as for
+	// thoughts copied into a branch or merged from one.
+	Gaps []time.Duration
+	// Step preceded by the longest gap, or 0 if no gap is known.
+	SlowestStep int
+}
+
+// sessionTimings computes the timing figures of session.
+func sessionTimings(session *ThinkingSession) sessionStats {
+	stats := sessionStats{
+		Duration: max(session.LastActivity.Sub(session.Created), 0),
+		Gaps:     make([]time.Duration, len(session.Thoughts)),
+	}
+
+	previous := session.Created
+	for i, thought := range session.Thoughts {
+		stats.Gaps[i] = -1
+		// A branch's own thoughts are timed from when the branch was created
+		if session.BranchPoint != nil && i == *session.BranchPoint && previous.Before(session.Created) {
+			previous = session.Created
+		}
+		if gap := thought.Created.Sub(previous); thought.Origin == "" && gap >= 0 {
+			stats.Gaps[i] = gap
+			if stats.SlowestStep == 0 || gap > stats.Gaps[stats.SlowestStep-1] {
+				stats.SlowestStep = i + 1
+			}
+		}
+		previous = thought.Created
+	}
+
+	if n := len(session.Thoughts); n > 1 {
+		if span := session.Thoughts[n-1].Created.Sub(session.Thoughts[0].Created); span >= 0 {
+			stats.AverageGap = span / time.Duration(n-1)
+			stats.HasAverage = true
+		}
+	}
+	return stats
+}
+
+// formatDuration rounds d for display: to the second, or to the millisecond
+// below one second.
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+// ThinkingStats reports how long a session has run, the average time between
+// its thoughts and the gap before each one, to show where thinking stalled.
+func (t sequentialThinking) ThinkingStats(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[ThinkingStatsArgs]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	session, exists := t.store.SessionSnapshot(args.SessionID)
+	if !exists {
+		return nil, fmt.Errorf("session %s not found", args.SessionID)
+	}
+	stats := sessionTimings(session)
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "=== Thinking Stats: %s ===\n", session.ID)
+	fmt.Fprintf(&report, "Duration: %s\n", formatDuration(stats.Duration))
+	fmt.Fprintf(&report, "Thoughts: %d\n", len(session.Thoughts))
+	if stats.HasAverage {
+		fmt.Fprintf(&report, "Average time between thoughts: %s\n", formatDuration(stats.AverageGap))
+	}
+	if stats.SlowestStep > 0 {
+		fmt.Fprintf(&report, "Longest gap: %s before step %d\n", formatDuration(stats.Gaps[stats.SlowestStep-1]), stats.SlowestStep)
+	}
+
+	if len(session.Thoughts) > 0 {
+		report.WriteString("\n--- Gap Before Each Step ---\n")
+		for i, gap := range stats.Gaps {
+			if gap < 0 {
+				fmt.Fprintf(&report, "%d. unknown (copied or merged)\n", i+1)
+				continue
+			}
+			fmt.Fprintf(&report, "%d. +%s\n", i+1, formatDuration(gap))
+		}
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: report.String()},
+		},
+	}, nil
+}
+
 // ThinkingHistory handles resource requests for thinking session data and history.
-func ThinkingHistory(ctx context.Context, ss *mcp.ServerSession, params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
+func (t sequentialThinking) ThinkingHistory(ctx context.Context, ss *mcp.ServerSession, params *mcp.ReadResourceParams) (*mcp.ReadResourceResult, error) {
 	// Extract session ID from URI (e.g., "thinking://session_123")
 	u, err := url.Parse(params.URI)
 	if err != nil {
@@ -436,7 +1422,7 @@ func ThinkingHistory(ctx context.Context, ss *mcp.ServerSession, params *mcp.Rea
 	sessionID := u.Host
 	if sessionID == "sessions" {
 		// List all sessions - use snapshot for thread safety
-		sessions := store1.SessionsSnapshot()
+		sessions := t.store.SessionsSnapshot()
 		data, err := json.MarshalIndent(sessions, "", "  ")
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal sessions: %w", err)
@@ -454,7 +1440,7 @@ func ThinkingHistory(ctx context.Context, ss *mcp.ServerSession, params *mcp.Rea
 	}
 
 	// Get specific session - use snapshot for thread safety
-	session, exists := store1.SessionSnapshot(sessionID)
+	session, exists := t.store.SessionSnapshot(sessionID)
 	if !exists {
 		return nil, fmt.Errorf("session %s not found", sessionID)
 	}
@@ -475,6 +1461,104 @@ func ThinkingHistory(ctx context.Context, ss *mcp.ServerSession, params *mcp.Rea
 	}, nil
 }
 
+// SnapshotThinking returns a JSON bundle of every session in the store.
+func (t sequentialThinking) SnapshotThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[any], error) {
+	sessions := t.store.SessionsSnapshot()
+	slices.SortFunc(sessions, func(a, b *ThinkingSession) int { return strings.Compare(a.ID, b.ID) })
+
+	data, err := json.Marshal(sessions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sessions: %w", err)
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: string(data)},
+		},
+	}, nil
+}
+
+// RestoreThinking loads sessions from a snapshot bundle, either merging them
+// into the store or replacing it. Versions are reset, and an optional prefix
+// is applied to every session ID (including branch references) to avoid
+// clobbering existing sessions.
+func (t sequentialThinking) RestoreThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[RestoreThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	var sessions []*ThinkingSession
+	if err := json.Unmarshal([]byte(args.Snapshot), &sessions); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	for _, session := range sessions {
+		if session == nil || session.ID == "" {
+			return nil, fmt.Errorf("invalid snapshot: every session needs an id")
+		}
+		session.ID = args.IDPrefix + session.ID
+		for i, branch := range session.Branches {
+			session.Branches[i] = args.IDPrefix + branch
+		}
+		session.Version = 0
+	}
+
+	if args.Replace {
+		if err := t.store.ReplaceSessions(sessions); err != nil {
+			return nil, err
+		}
+		return &mcp.CallToolResultFor[any]{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("Replaced the thinking store with %d sessions", len(sessions))},
+			},
+		}, nil
+	}
+
+	overwritten := 0
+	for _, session := range sessions {
+		if _, exists := t.store.Session(session.ID); exists {
+			overwritten++
+		}
+		if err := t.store.SetSession(session); err != nil {
+			return nil, err
+		}
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: fmt.Sprintf("Restored %d sessions (%d existing sessions overwritten)", len(sessions), overwritten)},
+		},
+	}, nil
+}
+
+// DeleteThinking removes a thinking session together with every branch
+// spawned from it, including branches of branches.
+func (t sequentialThinking) DeleteThinking(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[DeleteThinkingArgs]) (*mcp.CallToolResultFor[any], error) {
+	args := params.Arguments
+
+	if !t.store.DeleteSession(args.SessionID) {
+		return nil, fmt.Errorf("session %s not found", args.SessionID)
+	}
+
+	var branches []string
+	prefix := args.SessionID + "_branch_"
+	for _, session := range t.store.SessionsSnapshot() {
+		if strings.HasPrefix(session.ID, prefix) && t.store.DeleteSession(session.ID) {
+			branches = append(branches, session.ID)
+		}
+	}
+	slices.Sort(branches)
+
+	text := fmt.Sprintf("Deleted thinking session '%s'", args.SessionID)
+	if len(branches) > 0 {
+		text += fmt.Sprintf(" and %d branches: %s", len(branches), strings.Join(branches, ", "))
+	}
+
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil
+}
+
 // Copied from crypto/rand.
 // TODO: once 1.24 is assured, just use crypto/rand.
 const base32alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
@@ -487,4 +1571,4 @@ func randText() string {
 		src[i] = base32alphabet[src[i]%32]
 	}
 	return string(src)
-}
\ No newline at end of file
+}
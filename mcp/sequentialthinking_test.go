@@ -0,0 +1,687 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// runWithTimeout fails the test if f does not return within a few seconds.
+func runWithTimeout(t *testing.T, f func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("CompareAndSwap callers did not finish; a retry loop is likely stuck")
+	}
+}
+
+// TestCompareAndSwapConcurrent is meant to be run with -race.
+func TestCompareAndSwapConcurrent(t *testing.T) {
+	for _, maxRetries := range []int{DefaultMaxRetries, 0} {
+		store := NewSessionStore()
+		store.SetMaxRetries(maxRetries)
+		if err := store.SetSession(&ThinkingSession{ID: "s1", Status: "active"}); err != nil {
+			t.Fatal(err)
+		}
+
+		const workers = 50
+		errs := make(chan error, workers)
+		runWithTimeout(t, func() {
+			var wg sync.WaitGroup
+			for range workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs <- store.CompareAndSwap("s1", func(session *ThinkingSession) (*ThinkingSession, error) {
+						session.CurrentThought++
+						return session, nil
+					})
+				}()
+			}
+			wg.Wait()
+		})
+		close(errs)
+
+		succeeded := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				succeeded++
+			case !errors.Is(err, ErrTooManyRetries):
+				t.Errorf("CompareAndSwap() = %v, want nil or ErrTooManyRetries", err)
+			}
+		}
+
+		// Every successful update is applied exactly once
+		session, _ := store.Session("s1")
+		if session.CurrentThought != succeeded || session.Version != succeeded {
+			t.Errorf("maxRetries %d: CurrentThought = %d, Version = %d, want both %d", maxRetries, session.CurrentThought, session.Version, succeeded)
+		}
+		if maxRetries == DefaultMaxRetries && succeeded != workers {
+			t.Errorf("%d of %d updates succeeded under normal contention, want all", succeeded, workers)
+		}
+	}
+}
+
+func TestCompareAndSwapRetryLimit(t *testing.T) {
+	store := NewSessionStore()
+	store.SetMaxRetries(3)
+	if err := store.SetSession(&ThinkingSession{ID: "s1", Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every attempt conflicts with a write made while it runs
+	attempts := 0
+	err := store.CompareAndSwap("s1", func(session *ThinkingSession) (*ThinkingSession, error) {
+		attempts++
+		if err := store.CompareAndSwap("s1", func(s *ThinkingSession) (*ThinkingSession, error) { return s, nil }); err != nil {
+			t.Fatalf("conflicting CompareAndSwap() failed: %v", err)
+		}
+		return session, nil
+	})
+	if !errors.Is(err, ErrTooManyRetries) {
+		t.Errorf("CompareAndSwap() = %v, want ErrTooManyRetries", err)
+	}
+	if attempts != 4 {
+		t.Errorf("CompareAndSwap() made %d attempts, want 4", attempts)
+	}
+}
+
+// callThinking runs a thinking tool with args, returning only its error.
+func callThinking[Args any](tool func(context.Context, *mcp.ServerSession, *mcp.CallToolParamsFor[Args]) (*mcp.CallToolResultFor[any], error), args Args) error {
+	_, err := tool(context.Background(), nil, &mcp.CallToolParamsFor[Args]{Arguments: args})
+	return err
+}
+
+// thinkingText runs a thinking tool with args and returns its text, failing
+// the test if the tool fails.
+func thinkingText[Args any](t *testing.T, tool func(context.Context, *mcp.ServerSession, *mcp.CallToolParamsFor[Args]) (*mcp.CallToolResultFor[any], error), args Args) string {
+	t.Helper()
+	res, err := tool(context.Background(), nil, &mcp.CallToolParamsFor[Args]{Arguments: args})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resultContent(res)
+}
+
+func TestPauseAndResumeThinking(t *testing.T) {
+	store := NewSessionStore()
+	thinking := sequentialThinking{store: store}
+
+	if err := callThinking(thinking.StartThinking, StartThinkingArgs{SessionID: "s1", Problem: "plan a rollout"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", Thought: "list the services"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := callThinking(thinking.PauseThinking, PauseThinkingArgs{SessionID: "s1"}); err != nil {
+		t.Fatalf("PauseThinking() failed: %v", err)
+	}
+	if session, _ := store.Session("s1"); session.Status != "paused" {
+		t.Errorf("status after pausing = %q, want paused", session.Status)
+	}
+	if err := callThinking(thinking.PauseThinking, PauseThinkingArgs{SessionID: "s1"}); err == nil {
+		t.Error("pausing a paused session succeeded")
+	}
+
+	step := 1
+	for name, args := range map[string]ContinueThinkingArgs{
+		"continue": {SessionID: "s1", Thought: "order them"},
+		"revise":   {SessionID: "s1", Thought: "list every service", ReviseStep: &step},
+		"branch":   {SessionID: "s1", Thought: "alternative", CreateBranch: true},
+	} {
+		err := callThinking(thinking.ContinueThinking, args)
+		if err == nil || !strings.Contains(err.Error(), "resume_thinking") {
+			t.Errorf("%s on a paused session = %v, want an error pointing at resume_thinking", name, err)
+		}
+	}
+	if session, _ := store.Session("s1"); len(session.Thoughts) != 1 {
+		t.Errorf("paused session has %d thoughts, want 1", len(session.Thoughts))
+	}
+
+	if err := callThinking(thinking.ResumeThinking, ResumeThinkingArgs{SessionID: "s1"}); err != nil {
+		t.Fatalf("ResumeThinking() failed: %v", err)
+	}
+	if err := callThinking(thinking.ResumeThinking, ResumeThinkingArgs{SessionID: "s1"}); err == nil {
+		t.Error("resuming an active session succeeded")
+	}
+	if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", Thought: "order them"}); err != nil {
+		t.Fatalf("ContinueThinking() after resuming failed: %v", err)
+	}
+	if session, _ := store.Session("s1"); session.Status != "active" || len(session.Thoughts) != 2 {
+		t.Errorf("resumed session is %s with %d thoughts, want active with 2", session.Status, len(session.Thoughts))
+	}
+}
+
+func TestResumeThinkingAfterDeadline(t *testing.T) {
+	store := NewSessionStore()
+	thinking := sequentialThinking{store: store}
+
+	if err := callThinking(thinking.StartThinking, StartThinkingArgs{SessionID: "s1", Problem: "plan a rollout", MaxDuration: "1h"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := callThinking(thinking.PauseThinking, PauseThinkingArgs{SessionID: "s1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The deadline keeps running while the session is paused
+	if err := store.CompareAndSwap("s1", func(session *ThinkingSession) (*ThinkingSession, error) {
+		past := time.Now().Add(-time.Minute)
+		session.Deadline = &past
+		return session, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := callThinking(thinking.ResumeThinking, ResumeThinkingArgs{SessionID: "s1"}); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("ResumeThinking() past the deadline = %v, want a timed out error", err)
+	}
+	if session, _ := store.Session("s1"); session.Status != "timed_out" {
+		t.Errorf("status after resuming past the deadline = %q, want timed_out", session.Status)
+	}
+}
+
+func TestDeleteThinkingRemovesBranches(t *testing.T) {
+	store := NewSessionStore()
+	thinking := sequentialThinking{store: store}
+
+	for _, id := range []string{"s1", "s10"} {
+		if err := callThinking(thinking.StartThinking, StartThinkingArgs{SessionID: id, Problem: "plan a rollout"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: id, Thought: "list the services"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// s1_branch_1 gets a branch of its own; s10 only shares a prefix with s1
+	for _, id := range []string{"s1", "s1", "s1_branch_1", "s10"} {
+		if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: id, Thought: "alternative", CreateBranch: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	text := thinkingText(t, thinking.DeleteThinking, DeleteThinkingArgs{SessionID: "s1"})
+	want := "Deleted thinking session 's1' and 3 branches: s1_branch_1, s1_branch_1_branch_1, s1_branch_2"
+	if text != want {
+		t.Errorf("DeleteThinking() = %q, want %q", text, want)
+	}
+
+	var left []string
+	for _, session := range store.SessionsSnapshot() {
+		left = append(left, session.ID)
+	}
+	slices.Sort(left)
+	if !slices.Equal(left, []string{"s10", "s10_branch_1"}) {
+		t.Errorf("sessions left = %v, want only s10 and its branch", left)
+	}
+
+	if err := callThinking(thinking.DeleteThinking, DeleteThinkingArgs{SessionID: "s1"}); err == nil {
+		t.Error("deleting a deleted session succeeded")
+	}
+}
+
+func TestCreateBranchFromStep(t *testing.T) {
+	store := NewSessionStore()
+	thinking := sequentialThinking{store: store}
+
+	if err := callThinking(thinking.StartThinking, StartThinkingArgs{SessionID: "s1", Problem: "plan a rollout"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, thought := range []string{"list the services", "order them", "roll out in order"} {
+		if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", Thought: thought}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fromStep := 2
+	text := thinkingText(t, thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", CreateBranch: true, FromStep: &fromStep})
+	if !strings.Contains(text, "'s1_branch_1'") || !strings.Contains(text, "after step 2") {
+		t.Errorf("ContinueThinking() = %q, want branch s1_branch_1 after step 2", text)
+	}
+
+	branch, ok := store.Session("s1_branch_1")
+	if !ok {
+		t.Fatal("branch session not created")
+	}
+	if branch.BranchPoint == nil || *branch.BranchPoint != 2 || branch.CurrentThought != 2 {
+		t.Errorf("branch point = %v, current thought = %d, want both 2", branch.BranchPoint, branch.CurrentThought)
+	}
+	var seeded []string
+	for _, thought := range branch.Thoughts {
+		seeded = append(seeded, thought.Content)
+	}
+	if !slices.Equal(seeded, []string{"list the services", "order them"}) {
+		t.Errorf("branch seeded with %q, want the first two thoughts", seeded)
+	}
+
+	if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1_branch_1", Thought: "roll out in parallel"}); err != nil {
+		t.Fatal(err)
+	}
+	branch, _ = store.Session("s1_branch_1")
+	if first := branch.Thoughts[2]; first.Index != 3 || first.ParentIndex == nil || *first.ParentIndex != 2 {
+		t.Errorf("first branch thought = %+v, want index 3 with parent 2", first)
+	}
+	if session, _ := store.Session("s1"); len(session.Thoughts) != 3 || !slices.Equal(session.Branches, []string{"s1_branch_1"}) {
+		t.Errorf("parent has %d thoughts and branches %v, want 3 and [s1_branch_1]", len(session.Thoughts), session.Branches)
+	}
+
+	for _, step := range []int{0, 4} {
+		if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", CreateBranch: true, FromStep: &step}); err == nil {
+			t.Errorf("branching from step %d of 3 succeeded", step)
+		}
+	}
+}
+
+func TestUndoRevision(t *testing.T) {
+	store := NewSessionStore()
+	thinking := sequentialThinking{store: store}
+
+	if err := callThinking(thinking.StartThinking, StartThinkingArgs{SessionID: "s1", Problem: "plan a rollout"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", Thought: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	step := 1
+	if err := callThinking(thinking.UndoRevision, UndoRevisionArgs{SessionID: "s1", Step: step}); err == nil {
+		t.Error("undoing an unrevised step succeeded")
+	}
+	for _, thought := range []string{"v2", "v3"} {
+		if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", Thought: thought, ReviseStep: &step}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	text := thinkingText(t, thinking.UndoRevision, UndoRevisionArgs{SessionID: "s1", Step: step})
+	if !strings.Contains(text, "1 earlier versions left") || !strings.HasSuffix(text, "\nv2") {
+		t.Errorf("UndoRevision() = %q, want v2 restored with 1 earlier version left", text)
+	}
+	session, _ := store.Session("s1")
+	if thought := session.Thoughts[0]; thought.Content != "v2" || !thought.Revised || !slices.Equal(thought.PreviousVersions, []string{"v1"}) {
+		t.Errorf("after one undo thought = %+v, want v2, revised, with v1 before it", thought)
+	}
+
+	if err := callThinking(thinking.UndoRevision, UndoRevisionArgs{SessionID: "s1", Step: step}); err != nil {
+		t.Fatal(err)
+	}
+	session, _ = store.Session("s1")
+	if thought := session.Thoughts[0]; thought.Content != "v1" || thought.Revised || len(thought.PreviousVersions) != 0 {
+		t.Errorf("after two undos thought = %+v, want the original unrevised v1", thought)
+	}
+
+	for _, args := range []UndoRevisionArgs{{SessionID: "s1", Step: 1}, {SessionID: "s1", Step: 2}, {SessionID: "missing", Step: 1}} {
+		if err := callThinking(thinking.UndoRevision, args); err == nil {
+			t.Errorf("UndoRevision(%+v) succeeded", args)
+		}
+	}
+}
+
+func TestCompleteThinking(t *testing.T) {
+	store := NewSessionStore()
+	thinking := sequentialThinking{store: store}
+
+	for _, id := range []string{"s1", "s2"} {
+		if err := callThinking(thinking.StartThinking, StartThinkingArgs{SessionID: id, Problem: "plan a rollout"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := callThinking(thinking.CompleteThinking, CompleteThinkingArgs{SessionID: "s1", Conclusion: "  "}); err == nil {
+		t.Error("completing without a conclusion succeeded")
+	}
+	if err := callThinking(thinking.CompleteThinking, CompleteThinkingArgs{SessionID: "missing", Conclusion: "done"}); err == nil {
+		t.Error("completing a missing session succeeded")
+	}
+
+	text := thinkingText(t, thinking.CompleteThinking, CompleteThinkingArgs{SessionID: "s1", Conclusion: "canary first"})
+	if !strings.HasPrefix(text, "Completed thinking session 's1'") {
+		t.Errorf("CompleteThinking() = %q", text)
+	}
+	if session, _ := store.Session("s1"); session.Status != "completed" || session.Conclusion != "canary first" {
+		t.Errorf("session is %s with conclusion %q, want completed with canary first", session.Status, session.Conclusion)
+	}
+
+	// A completed session keeps its conclusion unless forced
+	if err := callThinking(thinking.CompleteThinking, CompleteThinkingArgs{SessionID: "s1", Conclusion: "all at once"}); err == nil || !strings.Contains(err.Error(), "force") {
+		t.Errorf("completing a completed session = %v, want an error pointing at force", err)
+	}
+	text = thinkingText(t, thinking.CompleteThinking, CompleteThinkingArgs{SessionID: "s1", Conclusion: "blue-green", Force: true})
+	if !strings.HasPrefix(text, "Updated the conclusion of thinking session 's1'") {
+		t.Errorf("forced CompleteThinking() = %q", text)
+	}
+	if session, _ := store.Session("s1"); session.Conclusion != "blue-green" {
+		t.Errorf("conclusion = %q, want blue-green", session.Conclusion)
+	}
+
+	// A paused session can be completed without resuming it
+	if err := callThinking(thinking.PauseThinking, PauseThinkingArgs{SessionID: "s2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := callThinking(thinking.CompleteThinking, CompleteThinkingArgs{SessionID: "s2", Conclusion: "shelved"}); err != nil {
+		t.Errorf("completing a paused session failed: %v", err)
+	}
+	if session, _ := store.Session("s2"); session.Status != "completed" {
+		t.Errorf("status = %q, want completed", session.Status)
+	}
+}
+
+func TestDeleteThought(t *testing.T) {
+	store := NewSessionStore()
+	thinking := sequentialThinking{store: store}
+
+	if err := callThinking(thinking.StartThinking, StartThinkingArgs{SessionID: "s1", Problem: "plan a rollout"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, thought := range []string{"one", "two", "three", "four"} {
+		if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", Thought: thought}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	text := thinkingText(t, thinking.DeleteThought, DeleteThoughtArgs{SessionID: "s1", Step: 2})
+	if !strings.Contains(text, "(3 steps left)") || !strings.HasSuffix(text, "\ntwo") {
+		t.Errorf("DeleteThought() = %q, want step two deleted with 3 left", text)
+	}
+
+	session, _ := store.Session("s1")
+	var got []string
+	for _, thought := range session.Thoughts {
+		got = append(got, fmt.Sprintf("%d:%s", thought.Index, thought.Content))
+	}
+	if want := []string{"1:one", "2:three", "3:four"}; !slices.Equal(got, want) {
+		t.Errorf("thoughts = %v, want %v", got, want)
+	}
+	if session.CurrentThought != 3 {
+		t.Errorf("current thought = %d, want 3", session.CurrentThought)
+	}
+
+	// The next thought follows on from the renumbered ones
+	if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", Thought: "five"}); err != nil {
+		t.Fatal(err)
+	}
+	if session, _ := store.Session("s1"); session.Thoughts[3].Index != 4 {
+		t.Errorf("next thought index = %d, want 4", session.Thoughts[3].Index)
+	}
+
+	for _, args := range []DeleteThoughtArgs{{SessionID: "s1", Step: 0}, {SessionID: "s1", Step: 5}, {SessionID: "missing", Step: 1}} {
+		if err := callThinking(thinking.DeleteThought, args); err == nil {
+			t.Errorf("DeleteThought(%+v) succeeded", args)
+		}
+	}
+}
+
+func TestSearchThinkingRanking(t *testing.T) {
+	store := NewSessionStore()
+	thinking := sequentialThinking{store: store}
+
+	for id, thoughts := range map[string][]string{
+		"s1": {"canary rollout then full rollout", "check the canary", "unrelated"},
+		"s2": {"rollout plan", "Canary canary"},
+	} {
+		if err := callThinking(thinking.StartThinking, StartThinkingArgs{SessionID: id, Problem: "plan a rollout"}); err != nil {
+			t.Fatal(err)
+		}
+		for _, thought := range thoughts {
+			if err := callThinking(thinking.ContinueThinking, ContinueThinkingArgs{SessionID: id, Thought: thought}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Ties are broken by session ID, then step
+	want := `Found 4 matching thoughts for "canary rollout":
+- s1 step 1 (score 4): canary rollout then full rollout
+- s2 step 2 (score 2): Canary canary
+- s1 step 2 (score 1): check the canary
+- s2 step 1 (score 1): rollout plan
+`
+	if got := thinkingText(t, thinking.SearchThinking, SearchThinkingArgs{Query: "canary rollout"}); got != want {
+		t.Errorf("SearchThinking() =\n%s\nwant\n%s", got, want)
+	}
+
+	want = `Found 4 matching thoughts for "canary rollout" (showing 2):
+- s1 step 1 (score 4): canary rollout then full rollout
+- s2 step 2 (score 2): Canary canary
+`
+	if got := thinkingText(t, thinking.SearchThinking, SearchThinkingArgs{Query: "canary rollout", Limit: 2}); got != want {
+		t.Errorf("SearchThinking() with a limit =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := thinkingText(t, thinking.SearchThinking, SearchThinkingArgs{Query: "database"}); got != `No thoughts match "database"` {
+		t.Errorf("SearchThinking() without matches = %q", got)
+	}
+	if err := callThinking(thinking.SearchThinking, SearchThinkingArgs{Query: "  "}); err == nil {
+		t.Error("searching for nothing succeeded")
+	}
+}
+
+func TestThinkingGraphDOT(t *testing.T) {
+	thinking := sequentialThinking{store: NewSessionStore()}
+	if err := callThinking(thinking.StartThinking, StartThinkingArgs{SessionID: "s1", Problem: `plan a "safe" rollout`}); err != nil {
+		t.Fatal(err)
+	}
+
+	fromStep := 1
+	for _, args := range []ContinueThinkingArgs{
+		{SessionID: "s1", Thought: "list the services"},
+		{SessionID: "s1", Thought: "order them"},
+		{SessionID: "s1", CreateBranch: true, FromStep: &fromStep},
+		{SessionID: "s1_branch_1", Thought: "roll out every service at once, then watch"},
+		{SessionID: "s1", CreateBranch: true},
+	} {
+		if err := callThinking(thinking.ContinueThinking, args); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := `digraph thinking {
+  rankdir=LR;
+  node [shape=box];
+  "s1:root" [label="plan a \"safe\" rollout", shape=ellipse];
+  "s1:root" -> "s1:1";
+  subgraph cluster_0 {
+    label="s1";
+    "s1:1" [label="1. list the services"];
+    "s1:2" [label="2. order them"];
+    "s1:1" -> "s1:2";
+  }
+  subgraph cluster_1 {
+    label="s1_branch_1";
+    "s1_branch_1:2" [label="2. roll out every service at once, then wat…"];
+  }
+  "s1:1" -> "s1_branch_1:2" [style=dashed];
+  subgraph cluster_2 {
+    label="s1_branch_2";
+    "s1_branch_2:start" [label="(no thoughts yet)", shape=plaintext];
+  }
+  "s1:2" -> "s1_branch_2:start" [style=dashed];
+}
+`
+	if got := thinkingText(t, thinking.ThinkingGraph, ThinkingGraphArgs{SessionID: "s1"}); got != want {
+		t.Errorf("ThinkingGraph() =\n%s\nwant\n%s", got, want)
+	}
+
+	if err := callThinking(thinking.ThinkingGraph, ThinkingGraphArgs{SessionID: "missing"}); err == nil {
+		t.Error("graphing a missing session succeeded")
+	}
+}
+
+func TestContinueThinkingOverEstimate(t *testing.T) {
+	thinking := sequentialThinking{store: NewSessionStore()}
+	if err := callThinking(thinking.StartThinking, StartThinkingArgs{SessionID: "s1", Problem: "plan a rollout", EstimatedSteps: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	const warning = "over your estimate"
+	for step, want := range []string{
+		"",
+		"",
+		"⚠ You are 1 step over your estimate of 2;",
+		"⚠ You are 2 steps over your estimate of 2;",
+	} {
+		text := thinkingText(t, thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", Thought: fmt.Sprintf("thought %d", step+1)})
+		if want == "" && strings.Contains(text, warning) {
+			t.Errorf("step %d warned within the estimate: %q", step+1, text)
+		}
+		if want != "" && !strings.Contains(text, want) {
+			t.Errorf("step %d = %q, want it to contain %q", step+1, text, want)
+		}
+	}
+
+	// Raising the estimate or concluding silences the warning
+	if text := thinkingText(t, thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", Thought: "thought 5", EstimatedTotal: 6}); strings.Contains(text, warning) || !strings.Contains(text, "Step 5 of ~6") {
+		t.Errorf("step with a raised estimate = %q, want no warning", text)
+	}
+	done := false
+	if text := thinkingText(t, thinking.ContinueThinking, ContinueThinkingArgs{SessionID: "s1", Thought: "thought 6", NextNeeded: &done, EstimatedTotal: 3}); strings.Contains(text, warning) {
+		t.Errorf("final step = %q, want no warning", text)
+	}
+}
+
+func TestMergeBranch(t *testing.T) {
+	store := NewSessionStore()
+	thinking := sequentialThinking{store: store}
+
+	for _, id := range []string{"s1", "s2"} {
+		if err := callThinking(thinking.StartThinking, StartThinkingArgs{SessionID: id, Problem: "plan a rollout"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fromStep := 1
+	for _, args := range []ContinueThinkingArgs{
+		{SessionID: "s1", Thought: "list the services"},
+		{SessionID: "s1", Thought: "order them"},
+		{SessionID: "s1", CreateBranch: true, FromStep: &fromStep},
+		{SessionID: "s1_branch_1", Thought: "group them"},
+		{SessionID: "s1_branch_1", Thought: "roll out by group"},
+		{SessionID: "s1", CreateBranch: true},
+	} {
+		if err := callThinking(thinking.ContinueThinking, args); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	text := thinkingText(t, thinking.MergeBranch, MergeBranchArgs{SessionID: "s1", BranchID: "s1_branch_1", CompleteBranch: true})
+	if want := "Merged 2 thoughts from branch 's1_branch_1' into session 's1' and marked 's1_branch_1' completed."; text != want {
+		t.Errorf("MergeBranch() = %q, want %q", text, want)
+	}
+	session, _ := store.Session("s1")
+	var got []string
+	for _, thought := range session.Thoughts {
+		got = append(got, fmt.Sprintf("%d:%s:%s", thought.Index, thought.Content, thought.Origin))
+	}
+	want := []string{"1:list the services:", "2:order them:", "3:group them:s1_branch_1", "4:roll out by group:s1_branch_1"}
+	if !slices.Equal(got, want) || session.CurrentThought != 4 {
+		t.Errorf("merged thoughts = %v at %d, want %v at 4", got, session.CurrentThought, want)
+	}
+	if branch, _ := store.Session("s1_branch_1"); branch.Status != "completed" {
+		t.Errorf("branch status = %q, want completed", branch.Status)
+	}
+
+	tests := []struct {
+		name string
+		args MergeBranchArgs
+		want string
+	}{
+		{"merged twice", MergeBranchArgs{SessionID: "s1", BranchID: "s1_branch_1"}, "already been merged"},
+		{"no new thoughts", MergeBranchArgs{SessionID: "s1", BranchID: "s1_branch_2"}, "no thoughts to merge"},
+		{"not a branch", MergeBranchArgs{SessionID: "s2", BranchID: "s1_branch_1"}, "not a branch"},
+		{"missing branch", MergeBranchArgs{SessionID: "s1", BranchID: "s1_branch_9"}, "not found"},
+	}
+	for _, tt := range tests {
+		err := callThinking(thinking.MergeBranch, tt.args)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: MergeBranch() = %v, want an error containing %q", tt.name, err, tt.want)
+		}
+	}
+	if session, _ := store.Session("s1"); len(session.Thoughts) != 4 {
+		t.Errorf("rejected merges changed the session to %d thoughts", len(session.Thoughts))
+	}
+}
+
+func TestThinkingStats(t *testing.T) {
+	store := NewSessionStore()
+	thinking := sequentialThinking{store: store}
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	branchPoint := 1
+	for _, session := range []*ThinkingSession{{
+		ID:      "s1",
+		Status:  "active",
+		Created: start,
+		Thoughts: []*Thought{
+			{Index: 1, Content: "one", Created: at(10 * time.Second)},
+			{Index: 2, Content: "two", Created: at(40 * time.Second)},
+			{Index: 3, Content: "three", Created: at(50 * time.Second)},
+			{Index: 4, Content: "merged", Created: at(55 * time.Second), Origin: "s1_branch_1"},
+		},
+		LastActivity: at(2 * time.Minute),
+	}, {
+		// The branch was created a minute in, from step 1 of s1
+		ID:          "s1_branch_1",
+		Status:      "active",
+		Created:     at(time.Minute),
+		BranchPoint: &branchPoint,
+		Thoughts: []*Thought{
+			{Index: 1, Content: "one", Created: at(10 * time.Second)},
+			{Index: 2, Content: "alternative", Created: at(time.Minute + 500*time.Millisecond)},
+		},
+		LastActivity: at(time.Minute + 500*time.Millisecond),
+	}} {
+		if err := store.SetSession(session); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"s1", `=== Thinking Stats: s1 ===
+Duration: 2m0s
+Thoughts: 4
+Average time between thoughts: 15s
+Longest gap: 30s before step 2
+
+--- Gap Before Each Step ---
+1. +10s
+2. +30s
+3. +10s
+4. unknown (copied or merged)
+`},
+		{"s1_branch_1", `=== Thinking Stats: s1_branch_1 ===
+Duration: 500ms
+Thoughts: 2
+Average time between thoughts: 51s
+Longest gap: 500ms before step 2
+
+--- Gap Before Each Step ---
+1. unknown (copied or merged)
+2. +500ms
+`},
+	}
+	for _, tt := range tests {
+		if got := thinkingText(t, thinking.ThinkingStats, ThinkingStatsArgs{SessionID: tt.id}); got != tt.want {
+			t.Errorf("ThinkingStats(%s) =\n%s\nwant\n%s", tt.id, got, tt.want)
+		}
+	}
+
+	if err := callThinking(thinking.ThinkingStats, ThinkingStatsArgs{SessionID: "missing"}); err == nil {
+		t.Error("stats for a missing session succeeded")
+	}
+}
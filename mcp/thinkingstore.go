@@ -0,0 +1,506 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ThinkingSessionsDirEnv names the environment variable holding the directory
+// thinking sessions are persisted to. When unset, sessions live in memory only.
+const ThinkingSessionsDirEnv = "THINKING_SESSIONS_DIR"
+
+// sessionBackend provides the persistence interface for a SessionStore.
+// Sessions are loaded when the backend is opened.
+type sessionBackend interface {
+	// Save persists a session, replacing any previous copy.
+	Save(session *ThinkingSession) error
+	// Delete removes a persisted session; deleting a missing session is not an error.
+	Delete(id string) error
+	// Close writes out anything still buffered and releases the backend.
+	Close() error
+}
+
+// dirBackend implements a sessionBackend that keeps one JSON file per
+// session in a directory, rewriting a session's file on every change.
+type dirBackend struct {
+	dir string
+}
+
+// NewPersistentSessionStore creates a session store backed by dir, loading any
+// sessions saved there by a previous run. The directory is created if needed.
+// Changes are batched in a journal as described by DefaultJournalConfig; the
+// store must be closed to flush the last of them.
+func NewPersistentSessionStore(dir string) (*SessionStore, error) {
+	return newPersistentSessionStore(dir, DefaultJournalConfig)
+}
+
+func newPersistentSessionStore(dir string, config JournalConfig) (*SessionStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create sessions directory %s: %w", dir, err)
+	}
+	backend, sessions, err := openJournalBackend(&dirBackend{dir: dir}, config)
+	if err != nil {
+		return nil, err
+	}
+
+	store := NewSessionStore()
+	store.backend = backend
+	for _, session := range sessions {
+		store.sessions[session.ID] = session
+	}
+	return store, nil
+}
+
+// path returns the file a session is stored in. IDs are escaped, since they
+// may be chosen by the client.
+func (b *dirBackend) path(id string) string {
+	return filepath.Join(b.dir, url.PathEscape(id)+".json")
+}
+
+// Load reads every session file in the directory.
+func (b *dirBackend) Load() ([]*ThinkingSession, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions directory %s: %w", b.dir, err)
+	}
+
+	var sessions []*ThinkingSession
+	for _, entry := range entries {
+		// Skips leftover temporary files from interrupted writes
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(b.dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read session file %s: %w", path, err)
+		}
+		var session ThinkingSession
+		if err := json.Unmarshal(data, &session); err != nil {
+			return nil, fmt.Errorf("invalid session file %s: %w", path, err)
+		}
+		if session.ID == "" {
+			return nil, fmt.Errorf("invalid session file %s: missing id", path)
+		}
+		sessions = append(sessions, &session)
+	}
+	return sessions, nil
+}
+
+// Save writes the session to a temporary file and renames it into place, so
+// a crash never leaves a partially written session behind.
+func (b *dirBackend) Save(session *ThinkingSession) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session %s: %w", session.ID, err)
+	}
+
+	tmp, err := os.CreateTemp(b.dir, ".session-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save session %s: %w", session.ID, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save session %s: %w", session.ID, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save session %s: %w", session.ID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save session %s: %w", session.ID, err)
+	}
+	if err := os.Rename(tmp.Name(), b.path(session.ID)); err != nil {
+		return fmt.Errorf("failed to save session %s: %w", session.ID, err)
+	}
+	return nil
+}
+
+// Delete removes the session's file.
+func (b *dirBackend) Delete(id string) error {
+	if err := os.Remove(b.path(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete session %s: %w", id, err)
+	}
+	return nil
+}
+
+// Close does nothing, as every change is already on disk.
+func (b *dirBackend) Close() error {
+	return nil
+}
+
+// journalName is the file, next to the session files, holding changes not
+// yet compacted into them. Its suffix keeps dirBackend.Load from reading it.
+const journalName = "journal.jsonl"
+
+// JournalConfig controls how a persistent session store batches its writes.
+type JournalConfig struct {
+	// FlushInterval is how often buffered changes are appended to the journal.
+	FlushInterval time.Duration
+	// FlushEvery appends buffered changes as soon as this many are waiting.
+	FlushEvery int
+	// CompactEvery folds the journal into the session files once it holds
+	// this many changes.
+	CompactEvery int
+}
+
+// DefaultJournalConfig bounds the changes a crash can lose to one second's
+// worth, and rewrites each session file at most once per 1000 changes.
+var DefaultJournalConfig = JournalConfig{
+	FlushInterval: time.Second,
+	FlushEvery:    100,
+	CompactEvery:  1000,
+}
+
+// journalRecord is one line of the journal: a saved session or a deletion.
+type journalRecord struct {
+	Session *ThinkingSession `json:"session,omitempty"`
+	Delete  string           `json:"delete,omitempty"`
+}
+
+// journalBackend implements a sessionBackend that buffers changes and
+// appends them to a journal in batches, instead of rewriting a session file
+// on every change. The journal is periodically compacted: the latest copy of
+// each changed session is written through the dirBackend and the journal is
+// truncated.
+type journalBackend struct {
+	files  *dirBackend
+	config JournalConfig
+
+	mu        sync.Mutex
+	journal   *os.File
+	buffer    bytes.Buffer                // records not yet appended to the journal
+	buffered  int                         // number of records in buffer
+	journaled int                         // number of records in the journal
+	dirty     map[string]*ThinkingSession // changes since the last compaction; nil marks a deletion
+	closed    bool
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// openJournalBackend loads the sessions in files and replays the journal over
+// them, compacts the result, and starts flushing on config.FlushInterval.
+func openJournalBackend(files *dirBackend, config JournalConfig) (*journalBackend, []*ThinkingSession, error) {
+	loaded, err := files.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b := &journalBackend{
+		files:   files,
+		config:  config,
+		dirty:   map[string]*ThinkingSession{},
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	sessions := make(map[string]*ThinkingSession, len(loaded))
+	for _, session := range loaded {
+		sessions[session.ID] = session
+	}
+	if err := b.replay(sessions); err != nil {
+		return nil, nil, err
+	}
+
+	path := filepath.Join(files.dir, journalName)
+	if b.journal, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600); err != nil {
+		return nil, nil, fmt.Errorf("failed to open journal %s: %w", path, err)
+	}
+	// Also drops a record torn by a crash, so new records start on a clean line
+	if err := b.compactLocked(); err != nil {
+		b.journal.Close()
+		return nil, nil, err
+	}
+
+	go b.flushPeriodically()
+	return b, slices.Collect(maps.Values(sessions)), nil
+}
+
+// replay applies the journal's records to sessions, remembering them as
+// dirty. A final record without a newline was torn by a crash and is ignored.
+func (b *journalBackend) replay(sessions map[string]*ThinkingSession) error {
+	path := filepath.Join(b.files.dir, journalName)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read journal %s: %w", path, err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read journal %s: %w", path, err)
+		}
+
+		var record journalRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return fmt.Errorf("invalid journal %s, line %d: %w", path, line, err)
+		}
+		switch {
+		case record.Session != nil && record.Session.ID != "":
+			sessions[record.Session.ID] = record.Session
+			b.dirty[record.Session.ID] = record.Session
+		case record.Delete != "":
+			delete(sessions, record.Delete)
+			b.dirty[record.Delete] = nil
+		default:
+			return fmt.Errorf("invalid journal %s, line %d: empty record", path, line)
+		}
+	}
+}
+
+// Save buffers the session, flushing when enough changes are waiting.
+func (b *journalBackend) Save(session *ThinkingSession) error {
+	return b.record(journalRecord{Session: session}, session.ID, session)
+}
+
+// Delete buffers the deletion, flushing when enough changes are waiting.
+func (b *journalBackend) Delete(id string) error {
+	return b.record(journalRecord{Delete: id}, id, nil)
+}
+
+func (b *journalBackend) record(record journalRecord, id string, session *ThinkingSession) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session %s: %w", id, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return fmt.Errorf("failed to save session %s: store is closed", id)
+	}
+
+	b.buffer.Write(data)
+	b.buffer.WriteByte('\n')
+	b.buffered++
+	b.dirty[id] = session
+	if b.buffered >= b.config.FlushEvery {
+		return b.flushLocked()
+	}
+	return nil
+}
+
+// flushLocked appends the buffered records to the journal and syncs it,
+// compacting when the journal has grown long enough. The caller must hold b.mu.
+func (b *journalBackend) flushLocked() error {
+	if b.buffered == 0 {
+		return nil
+	}
+	if _, err := b.journal.Write(b.buffer.Bytes()); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := b.journal.Sync(); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	b.journaled += b.buffered
+	b.buffer.Reset()
+	b.buffered = 0
+
+	if b.journaled >= b.config.CompactEvery {
+		return b.compactLocked()
+	}
+	return nil
+}
+
+// compactLocked writes the latest copy of every dirty session to its file,
+// removes the files of deleted ones, and empties the journal. The journal is
+// only truncated once the files are written, so a crash part way through is
+// recovered by replaying it again. The caller must hold b.mu.
+func (b *journalBackend) compactLocked() error {
+	for id, session := range b.dirty {
+		var err error
+		if session == nil {
+			err = b.files.Delete(id)
+		} else {
+			err = b.files.Save(session)
+		}
+		if err != nil {
+			return err
+		}
+		delete(b.dirty, id)
+	}
+
+	if err := b.journal.Truncate(0); err != nil {
+		return fmt.Errorf("failed to compact journal: %w", err)
+	}
+	if err := b.journal.Sync(); err != nil {
+		return fmt.Errorf("failed to compact journal: %w", err)
+	}
+	b.journaled = 0
+	return nil
+}
+
+// flushPeriodically flushes the buffer every config.FlushInterval until the
+// backend is closed.
+func (b *journalBackend) flushPeriodically() {
+	defer close(b.stopped)
+	ticker := time.NewTicker(b.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			err := b.flushLocked()
+			b.mu.Unlock()
+			if err != nil {
+				log.Printf("[WARN]: %v", err)
+			}
+		}
+	}
+}
+
+// Close stops the periodic flush, writes every buffered change to the
+// session files and closes the journal. Closing twice is not an error.
+func (b *journalBackend) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.done)
+	<-b.stopped
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	err := b.flushLocked()
+	if err == nil {
+		err = b.compactLocked()
+	}
+	if closeErr := b.journal.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close journal: %w", closeErr)
+	}
+	return err
+}
+
+const (
+	// ThinkingSessionTTLEnv names the environment variable holding how long a
+	// session may sit idle before it is deleted, as a Go duration. When unset,
+	// sessions never expire.
+	ThinkingSessionTTLEnv = "THINKING_SESSION_TTL"
+	// ThinkingSweepIntervalEnv names the environment variable holding how often
+	// idle sessions are looked for. It defaults to a tenth of the TTL.
+	ThinkingSweepIntervalEnv = "THINKING_SWEEP_INTERVAL"
+	// ThinkingKeepCompletedEnv names the environment variable that, when true,
+	// exempts completed sessions from expiry.
+	ThinkingKeepCompletedEnv = "THINKING_KEEP_COMPLETED"
+
+	// minSweepInterval bounds how often the janitor may run.
+	minSweepInterval = time.Second
+)
+
+// JanitorConfig controls the expiry of idle thinking sessions.
+type JanitorConfig struct {
+	// TTL is how long a session may go without activity before it is deleted.
+	TTL time.Duration
+	// Interval is how often the store is swept for idle sessions.
+	Interval time.Duration
+	// KeepCompleted exempts completed sessions from expiry.
+	KeepCompleted bool
+}
+
+// JanitorConfigFromEnv reads the janitor settings from the environment. The
+// second return value is false when no TTL is configured.
+func JanitorConfigFromEnv() (JanitorConfig, bool, error) {
+	var config JanitorConfig
+
+	ttl := os.Getenv(ThinkingSessionTTLEnv)
+	if ttl == "" {
+		return config, false, nil
+	}
+	var err error
+	if config.TTL, err = time.ParseDuration(ttl); err != nil || config.TTL <= 0 {
+		return config, false, fmt.Errorf("invalid %s %q: must be a positive duration", ThinkingSessionTTLEnv, ttl)
+	}
+
+	config.Interval = max(config.TTL/10, minSweepInterval)
+	if interval := os.Getenv(ThinkingSweepIntervalEnv); interval != "" {
+		if config.Interval, err = time.ParseDuration(interval); err != nil || config.Interval < minSweepInterval {
+			return config, false, fmt.Errorf("invalid %s %q: must be a duration of at least %s", ThinkingSweepIntervalEnv, interval, minSweepInterval)
+		}
+	}
+
+	if keep := os.Getenv(ThinkingKeepCompletedEnv); keep != "" {
+		if config.KeepCompleted, err = strconv.ParseBool(keep); err != nil {
+			return config, false, fmt.Errorf("invalid %s %q: must be true or false", ThinkingKeepCompletedEnv, keep)
+		}
+	}
+	return config, true, nil
+}
+
+// ExpireIdle deletes every session whose last activity is more than ttl
+// before now, returning the IDs of the deleted sessions. Completed sessions
+// are kept when keepCompleted is set.
+func (s *SessionStore) ExpireIdle(now time.Time, ttl time.Duration, keepCompleted bool) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []string
+	for id, session := range s.sessions {
+		if keepCompleted && session.Status == "completed" {
+			continue
+		}
+		if now.Sub(session.LastActivity) > ttl {
+			s.deleteLocked(id)
+			expired = append(expired, id)
+		}
+	}
+	return expired
+}
+
+// StartJanitor sweeps the store for idle sessions every config.Interval in a
+// background goroutine. The returned function stops the janitor and waits for
+// any sweep in progress to finish.
+func (s *SessionStore) StartJanitor(config JanitorConfig) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if expired := s.ExpireIdle(now, config.TTL, config.KeepCompleted); len(expired) > 0 {
+					log.Printf("[INFO]: Expired %d idle thinking sessions", len(expired))
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-stopped
+	}
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPersistentSessionStoreReload(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewPersistentSessionStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"s1", "s2", "team/a b", "gone"} {
+		if err := store.SetSession(&ThinkingSession{ID: id, Problem: "problem " + id, Status: "active"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range 3 {
+		if err := store.CompareAndSwap("s1", func(session *ThinkingSession) (*ThinkingSession, error) {
+			session.Thoughts = append(session.Thoughts, &Thought{Content: "step"})
+			session.CurrentThought++
+			return session, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.CompareAndSwap("team/a b", func(session *ThinkingSession) (*ThinkingSession, error) {
+		session.Status = "completed"
+		return session, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	store.DeleteSession("gone")
+
+	// "Restart" by closing the store and opening a new one over the same directory
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := NewPersistentSessionStore(dir)
+	if err != nil {
+		t.Fatalf("reloading the store failed: %v", err)
+	}
+	t.Cleanup(func() { reloaded.Close() })
+
+	tests := []struct {
+		id       string
+		version  int
+		thoughts int
+		status   string
+	}{
+		{"s1", 3, 3, "active"},
+		{"s2", 0, 0, "active"},
+		{"team/a b", 1, 0, "completed"},
+	}
+	for _, tt := range tests {
+		session, ok := reloaded.Session(tt.id)
+		if !ok {
+			t.Errorf("session %q was not reloaded", tt.id)
+			continue
+		}
+		if session.Version != tt.version || len(session.Thoughts) != tt.thoughts || session.Status != tt.status || session.Problem != "problem "+tt.id {
+			t.Errorf("reloaded session %q = version %d, %d thoughts, %s; want version %d, %d thoughts, %s",
+				tt.id, session.Version, len(session.Thoughts), session.Status, tt.version, tt.thoughts, tt.status)
+		}
+	}
+	if _, ok := reloaded.Session("gone"); ok {
+		t.Error("deleted session was reloaded")
+	}
+
+	// The reloaded versions keep optimistic concurrency going from where it left off
+	if err := reloaded.CompareAndSwap("s1", func(session *ThinkingSession) (*ThinkingSession, error) {
+		return session, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if session, _ := reloaded.Session("s1"); session.Version != 4 {
+		t.Errorf("version after a reloaded CompareAndSwap = %d, want 4", session.Version)
+	}
+}
+
+func TestPersistentSessionStoreAtomicWrites(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewPersistentSessionStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetSession(&ThinkingSession{ID: "s1", Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Errorf("temporary file %s left behind after a save", entry.Name())
+		}
+	}
+
+	// A temporary file left by an interrupted write is ignored on reload
+	if err := os.WriteFile(filepath.Join(dir, ".session-123.tmp"), []byte(`{"id":"partial`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := NewPersistentSessionStore(dir)
+	if err != nil {
+		t.Fatalf("reloading with a leftover temporary file failed: %v", err)
+	}
+	t.Cleanup(func() { reloaded.Close() })
+	if sessions := reloaded.Sessions(); len(sessions) != 1 || sessions[0].ID != "s1" {
+		t.Errorf("reloaded sessions = %v, want only s1", sessions)
+	}
+}
+
+func TestPersistentSessionStoreInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewPersistentSessionStore(dir); err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Errorf("NewPersistentSessionStore() = %v, want an error naming the invalid file", err)
+	}
+}
+
+// bumpThought is a CompareAndSwap update that changes a session without
+// growing it.
+func bumpThought(session *ThinkingSession) (*ThinkingSession, error) {
+	session.CurrentThought++
+	return session, nil
+}
+
+// fileSize returns the size of the named file in dir, or -1 if it is missing.
+func fileSize(t *testing.T, dir, name string) int64 {
+	t.Helper()
+	info, err := os.Stat(filepath.Join(dir, name))
+	if os.IsNotExist(err) {
+		return -1
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
+
+func TestPersistentSessionStoreRecoversJournal(t *testing.T) {
+	dir := t.TempDir()
+
+	// Every change reaches the journal at once, but none is compacted
+	store, err := newPersistentSessionStore(dir, JournalConfig{FlushInterval: time.Hour, FlushEvery: 1, CompactEvery: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"s1", "gone"} {
+		if err := store.SetSession(&ThinkingSession{ID: id, Status: "active"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.CompareAndSwap("s1", bumpThought); err != nil {
+		t.Fatal(err)
+	}
+	store.DeleteSession("gone")
+
+	if size := fileSize(t, dir, "s1.json"); size != -1 {
+		t.Errorf("session file written before compaction")
+	}
+	if size := fileSize(t, dir, journalName); size <= 0 {
+		t.Fatalf("journal size = %d, want the changes appended to it", size)
+	}
+
+	// Simulate a crash: the store is never closed and a record is torn
+	f, err := os.OpenFile(filepath.Join(dir, journalName), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"session":{"id":"torn"`)
+	f.Close()
+
+	reloaded, err := NewPersistentSessionStore(dir)
+	if err != nil {
+		t.Fatalf("reloading after a crash failed: %v", err)
+	}
+	t.Cleanup(func() { reloaded.Close() })
+
+	if session, ok := reloaded.Session("s1"); !ok || session.Version != 1 || session.CurrentThought != 1 {
+		t.Errorf("recovered s1 = %+v, want version 1 with one bump", session)
+	}
+	for _, id := range []string{"gone", "torn"} {
+		if _, ok := reloaded.Session(id); ok {
+			t.Errorf("session %q was recovered", id)
+		}
+	}
+
+	// Opening compacts the recovered journal into the session files
+	if size := fileSize(t, dir, journalName); size != 0 {
+		t.Errorf("journal size after reload = %d, want 0", size)
+	}
+	if size := fileSize(t, dir, "s1.json"); size <= 0 {
+		t.Error("recovered session was not compacted into its file")
+	}
+}
+
+func TestPersistentSessionStoreCompacts(t *testing.T) {
+	dir := t.TempDir()
+	store, err := newPersistentSessionStore(dir, JournalConfig{FlushInterval: time.Hour, FlushEvery: 2, CompactEvery: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	if err := store.SetSession(&ThinkingSession{ID: "s1", Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+	if size := fileSize(t, dir, journalName); size != 0 {
+		t.Errorf("journal size with one buffered change = %d, want 0", size)
+	}
+
+	if err := store.CompareAndSwap("s1", bumpThought); err != nil {
+		t.Fatal(err)
+	}
+	if size := fileSize(t, dir, journalName); size <= 0 {
+		t.Errorf("journal size after FlushEvery changes = %d, want the batch appended", size)
+	}
+
+	for range 2 {
+		if err := store.CompareAndSwap("s1", bumpThought); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if size := fileSize(t, dir, journalName); size != 0 {
+		t.Errorf("journal size after CompactEvery changes = %d, want 0", size)
+	}
+	if size := fileSize(t, dir, "s1.json"); size <= 0 {
+		t.Error("session file was not written by compaction")
+	}
+}
+
+func TestPersistentSessionStoreFlushInterval(t *testing.T) {
+	dir := t.TempDir()
+	store, err := newPersistentSessionStore(dir, JournalConfig{FlushInterval: 10 * time.Millisecond, FlushEvery: 100, CompactEvery: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	if err := store.SetSession(&ThinkingSession{ID: "s1", Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for fileSize(t, dir, journalName) <= 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("buffered change was never flushed to the journal")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPersistentSessionStoreClose(t *testing.T) {
+	store, err := NewPersistentSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Errorf("second Close() = %v, want nil", err)
+	}
+	if err := store.SetSession(&ThinkingSession{ID: "s1"}); err == nil {
+		t.Error("SetSession() on a closed store succeeded")
+	}
+}
+
+// BenchmarkPersistentSessionStoreUpdates compares rewriting a session file on
+// every change with batching changes through the journal.
+func BenchmarkPersistentSessionStoreUpdates(b *testing.B) {
+	thoughts := make([]*Thought, 20)
+	for i := range thoughts {
+		thoughts[i] = &Thought{Index: i + 1, Content: strings.Repeat("reasoning ", 50)}
+	}
+
+	backends := []struct {
+		name string
+		open func(dir string) (*SessionStore, error)
+	}{
+		{"write-through", func(dir string) (*SessionStore, error) {
+			store := NewSessionStore()
+			store.backend = &dirBackend{dir: dir}
+			return store, nil
+		}},
+		{"journal", NewPersistentSessionStore},
+	}
+	for _, backend := range backends {
+		b.Run(backend.name, func(b *testing.B) {
+			store, err := backend.open(b.TempDir())
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer store.Close()
+
+			const sessions = 10
+			for i := range sessions {
+				session := &ThinkingSession{ID: fmt.Sprintf("s%d", i), Status: "active", Thoughts: thoughts}
+				if err := store.SetSession(session); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ResetTimer()
+			for i := range b.N {
+				if err := store.CompareAndSwap(fmt.Sprintf("s%d", i%sessions), bumpThought); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestExpireIdle(t *testing.T) {
+	now := time.Now()
+	newStore := func(t *testing.T) *SessionStore {
+		store := NewSessionStore()
+		for _, session := range []*ThinkingSession{
+			{ID: "fresh", Status: "active", LastActivity: now.Add(-time.Minute)},
+			{ID: "idle", Status: "active", LastActivity: now.Add(-time.Hour)},
+			{ID: "done", Status: "completed", LastActivity: now.Add(-time.Hour)},
+		} {
+			if err := store.SetSession(session); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return store
+	}
+
+	tests := []struct {
+		name          string
+		keepCompleted bool
+		expired, kept []string
+	}{
+		{"expire completed", false, []string{"done", "idle"}, []string{"fresh"}},
+		{"keep completed", true, []string{"idle"}, []string{"done", "fresh"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStore(t)
+			expired := store.ExpireIdle(now, 30*time.Minute, tt.keepCompleted)
+			slices.Sort(expired)
+			if !slices.Equal(expired, tt.expired) {
+				t.Errorf("ExpireIdle() = %v, want %v", expired, tt.expired)
+			}
+			var kept []string
+			for _, session := range store.SessionsSnapshot() {
+				kept = append(kept, session.ID)
+			}
+			slices.Sort(kept)
+			if !slices.Equal(kept, tt.kept) {
+				t.Errorf("sessions kept = %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+}
+
+func TestStartJanitor(t *testing.T) {
+	store := NewSessionStore()
+	idle := func(id string) *ThinkingSession {
+		return &ThinkingSession{ID: id, Status: "active", LastActivity: time.Now().Add(-time.Hour)}
+	}
+	if err := store.SetSession(idle("s1")); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := store.StartJanitor(JanitorConfig{TTL: time.Minute, Interval: 10 * time.Millisecond})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := store.Session("s1"); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("janitor did not expire the idle session")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Stopping twice is fine, and a stopped janitor sweeps no more
+	stop()
+	stop()
+	if err := store.SetSession(idle("s2")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := store.Session("s2"); !ok {
+		t.Error("stopped janitor expired a session")
+	}
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// TransportEnv names the environment variable selecting how clients reach
+	// the server: "stdio", the default, or "http".
+	TransportEnv = "MCP_TRANSPORT"
+	// HTTPAddrEnv names the environment variable holding the address the HTTP
+	// transport listens on.
+	HTTPAddrEnv = "MCP_HTTP_ADDR"
+	// FramingEnv names the environment variable selecting how the stdio
+	// transport frames the messages it writes; see Framing.
+	FramingEnv = "MCP_STDIO_FRAMING"
+
+	defaultHTTPAddr = "localhost:8081"
+	// httpEndpoint is the path the streamable HTTP transport is served at.
+	httpEndpoint = "/mcp"
+)
+
+// runServer serves clients over the transport selected by TransportEnv until
+// the transport is closed or ctx is cancelled.
+func runServer(ctx context.Context, server *mcp.Server) error {
+	switch transport := os.Getenv(TransportEnv); transport {
+	case "", "stdio":
+		framing := Framing(cmp.Or(os.Getenv(FramingEnv), string(FramingAuto)))
+		switch framing {
+		case FramingAuto, FramingNewline, FramingContentLength:
+		default:
+			return fmt.Errorf("invalid %s %q: must be auto, newline or content-length", FramingEnv, framing)
+		}
+		return server.Run(ctx, NewIOTransport(os.Stdin, os.Stdout, framing))
+	case "http":
+		return serveHTTP(ctx, server, cmp.Or(os.Getenv(HTTPAddrEnv), defaultHTTPAddr))
+	default:
+		return fmt.Errorf("invalid %s %q: must be stdio or http", TransportEnv, transport)
+	}
+}
+
+// newHTTPHandler serves the server over the streamable HTTP transport. Every
+// client gets its own session, and all sessions share the server's tools and
+// stores.
+func newHTTPHandler(server *mcp.Server) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(httpEndpoint, mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	return mux
+}
+
+// serveHTTP listens on addr until ctx is cancelled.
+func serveHTTP(ctx context.Context, server *mcp.Server, addr string) error {
+	httpServer := &http.Server{Addr: addr, Handler: newHTTPHandler(server)}
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
+
+	log.Printf("[INFO]: Serving MCP over HTTP at http://%s%s", addr, httpEndpoint)
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Framing selects how the stdio transport frames the messages it writes.
+// Incoming messages are accepted in either form regardless.
+type Framing string
+
+const (
+	// FramingAuto writes newline-delimited messages until the client sends a
+	// Content-Length framed message, and framed messages from then on.
+	// Anything the server writes before the client's first message is
+	// therefore newline-delimited.
+	FramingAuto Framing = "auto"
+	// FramingNewline always writes newline-delimited messages.
+	FramingNewline Framing = "newline"
+	// FramingContentLength always writes Content-Length framed messages.
+	FramingContentLength Framing = "content-length"
+)
+
+type IOTransport struct {
+	r       *bufio.Reader
+	w       io.Writer
+	in      io.Reader // the stream r buffers, closed along with the connection
+	framing Framing
+}
+
+func NewIOTransport(r io.Reader, w io.Writer, framing Framing) *IOTransport {
+	return &IOTransport{
+		r:       bufio.NewReader(r),
+		w:       w,
+		in:      r,
+		framing: framing,
+	}
+}
+
+const (
+	// contentLengthHeader introduces the length of an LSP-style framed message.
+	contentLengthHeader = "Content-Length"
+	// maxMessageSize bounds the size of a single incoming message.
+	maxMessageSize = 16 << 20
+)
+
+var (
+	errMessageTooLarge = fmt.Errorf("message exceeds %d bytes", maxMessageSize)
+	errConnClosed      = errors.New("connection closed")
+)
+
+// An ioConn reads and writes JSON-RPC messages over a byte stream. Incoming
+// messages may be newline-delimited, as the MCP stdio transport specifies, or
+// framed with a Content-Length header. How outgoing messages are framed is
+// chosen by framing.
+type ioConn struct {
+	r       *bufio.Reader
+	w       io.Writer
+	in      io.Reader
+	framing Framing
+
+	framed  atomic.Bool // set once the client sends a framed message
+	closed  atomic.Bool // set by Close; later reads and writes fail
+	writeMu sync.Mutex  // keeps concurrent writes from interleaving
+}
+
+func (t *IOTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	return &ioConn{
+		r:       t.r,
+		w:       t.w,
+		in:      t.in,
+		framing: t.framing,
+	}, nil
+}
+
+// problem with an import for decodemsg fnc so used json unmarshal
+func (t *ioConn) Read(context.Context) (jsonrpc.Message, error) {
+	data, err := t.readMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	var msg jsonrpc.Message
+	err = json.Unmarshal(data, &msg)
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// readMessage reads the body of the next message, newline-delimited or
+// Content-Length framed.
+func (t *ioConn) readMessage() ([]byte, error) {
+	if t.closed.Load() {
+		return nil, errConnClosed
+	}
+
+	var data []byte
+	var err error
+	// Blank lines between messages are ignored
+	for len(bytes.TrimSpace(data)) == 0 {
+		if data, err = t.readLine(); err != nil {
+			// A read interrupted by Close fails on the closed stream
+			if t.closed.Load() {
+				return nil, errConnClosed
+			}
+			return nil, err
+		}
+	}
+
+	if length, ok, err := parseContentLength(data); err != nil {
+		return nil, err
+	} else if ok {
+		t.framed.Store(true)
+		return t.readFramed(length)
+	}
+	return data, nil
+}
+
+// problem with an import for encodemsg fnc so used json marshal
+func (t *ioConn) Write(_ context.Context, msg jsonrpc.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return t.writeMessage(data)
+}
+
+// writeMessage writes data as a single message, framed according to t.framing.
+func (t *ioConn) writeMessage(data []byte) error {
+	// Each message goes out in a single write, so a reader never sees a
+	// header without its body
+	var frame []byte
+	if t.framing == FramingContentLength || (t.framing == FramingAuto && t.framed.Load()) {
+		frame = fmt.Appendf(nil, "%s: %d\r\n\r\n", contentLengthHeader, len(data))
+		frame = append(frame, data...)
+	} else {
+		frame = append(data, '\n')
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if t.closed.Load() {
+		return errConnClosed
+	}
+	_, err := t.w.Write(frame)
+	return err
+}
+
+// parseContentLength reports whether line is a Content-Length header, and if
+// so the length it announces.
+func parseContentLength(line []byte) (int, bool, error) {
+	name, value, found := strings.Cut(strings.TrimSuffix(string(line), "\r"), ":")
+	if !found || !strings.EqualFold(strings.TrimSpace(name), contentLengthHeader) {
+		return 0, false, nil
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || length < 0 {
+		return 0, false, fmt.Errorf("invalid %s header %q", contentLengthHeader, strings.TrimSpace(value))
+	}
+	return length, true, nil
+}
+
+// readLine reads the next line without its trailing newline. A last line
+// that ends at EOF without a newline is returned as is, and io.EOF is only
+// returned once the stream is exhausted. Lines longer than maxMessageSize are
+// discarded and reported as errMessageTooLarge, leaving the reader at the
+// start of the next line.
+func (t *ioConn) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := t.r.ReadSlice('\n')
+		if len(line)+len(chunk) > maxMessageSize+1 {
+			for err == bufio.ErrBufferFull {
+				_, err = t.r.ReadSlice('\n')
+			}
+			return nil, errMessageTooLarge
+		}
+		line = append(line, chunk...)
+
+		switch {
+		case err == nil:
+			return line[:len(line)-1], nil
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && len(line) > 0:
+			return line, nil
+		default:
+			return nil, err
+		}
+	}
+}
+
+// readFramed skips the remaining headers of a framed message, up to the blank
+// line ending them, and then reads exactly length bytes of body.
+func (t *ioConn) readFramed(length int) ([]byte, error) {
+	for {
+		line, err := t.readLine()
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading message headers: %w", err)
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			break
+		}
+	}
+
+	if length > maxMessageSize {
+		if _, err := t.r.Discard(length); err != nil {
+			return nil, fmt.Errorf("reading %d byte message: %w", length, err)
+		}
+		return nil, errMessageTooLarge
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(t.r, data); err != nil {
+		return nil, fmt.Errorf("reading %d byte message: %w", length, err)
+	}
+	return data, nil
+}
+
+// Close flushes a buffered writer and closes the underlying streams that can
+// be closed. Closing an already closed connection does nothing.
+func (t *ioConn) Close() error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if !t.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	var errs []error
+	if flusher, ok := t.w.(interface{ Flush() error }); ok {
+		errs = append(errs, flusher.Flush())
+	}
+	if closer, ok := t.w.(io.Closer); ok {
+		errs = append(errs, closer.Close())
+	}
+	// The same stream may be used in both directions, e.g. a network connection
+	if closer, ok := t.in.(io.Closer); ok && !sameStream(t.in, t.w) {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// sameStream reports whether r and w are the same object.
+func sameStream(r io.Reader, w io.Writer) bool {
+	rw, ok := r.(io.Writer)
+	return ok && rw == w
+}
+
+// constant session id for our local setup for now
+func (t *ioConn) SessionID() string {
+	return "kubernetes-1"
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newTestServer creates the MCP server backed by in-memory stores.
+func newTestServer() *mcp.Server {
+	return newServer(NewSessionStore(), newTestKnowledgeBase())
+}
+
+// connectHTTP connects a new client to the server's streamable HTTP endpoint.
+func connectHTTP(t *testing.T, url string) *mcp.ClientSession {
+	t.Helper()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	cs, err := client.Connect(context.Background(), mcp.NewStreamableClientTransport(url+httpEndpoint, nil))
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+// toolText joins the text content of a tool result.
+func toolText(t *testing.T, res *mcp.CallToolResult) string {
+	t.Helper()
+	var parts []string
+	for _, content := range res.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func TestHTTPTransportToolCall(t *testing.T) {
+	httpServer := httptest.NewServer(newHTTPHandler(newTestServer()))
+	defer httpServer.Close()
+
+	// Several clients can use the server at once
+	for range 2 {
+		cs := connectHTTP(t, httpServer.URL)
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "generate_uuid",
+			Arguments: map[string]any{},
+		})
+		if err != nil {
+			t.Fatalf("CallTool(generate_uuid) failed: %v", err)
+		}
+		if res.IsError {
+			t.Fatalf("generate_uuid returned an error result: %s", toolText(t, res))
+		}
+		if text := toolText(t, res); !strings.HasPrefix(text, "Generated UUID: ") {
+			t.Errorf("generate_uuid returned %q, want a generated UUID", text)
+		}
+	}
+}
+
+func TestHTTPTransportCancelUnknownOperation(t *testing.T) {
+	httpServer := httptest.NewServer(newHTTPHandler(newTestServer()))
+	defer httpServer.Close()
+
+	cs := connectHTTP(t, httpServer.URL)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "cancel_operation",
+		Arguments: map[string]any{"operationId": "missing"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool(cancel_operation) failed: %v", err)
+	}
+	if !res.IsError {
+		t.Errorf("cancel_operation of an unknown operation: IsError = false, want true")
+	}
+	if text := toolText(t, res); !strings.Contains(text, "missing") {
+		t.Errorf("cancel_operation returned %q, want it to name the operation", text)
+	}
+}
+
+// newTestConn returns a connection reading input and writing to out.
+func newTestConn(input string, out io.Writer, framing Framing) *ioConn {
+	conn, _ := NewIOTransport(strings.NewReader(input), out, framing).Connect(context.Background())
+	return conn.(*ioConn)
+}
+
+// A pretty-printed message, whose body contains newlines
+const multilineMessage = "{\n  \"jsonrpc\": \"2.0\",\n  \"method\": \"ping\",\n  \"id\": 1\n}"
+
+func TestFramedMessageWithNewlinesRoundTrip(t *testing.T) {
+	var out bytes.Buffer
+	writer := newTestConn("", &out, FramingContentLength)
+	if err := writer.writeMessage([]byte(multilineMessage)); err != nil {
+		t.Fatalf("writeMessage() failed: %v", err)
+	}
+	if err := writer.writeMessage([]byte(`{"jsonrpc":"2.0","method":"second"}`)); err != nil {
+		t.Fatalf("writeMessage() failed: %v", err)
+	}
+
+	reader := newTestConn(out.String(), io.Discard, FramingAuto)
+	for _, want := range []string{multilineMessage, `{"jsonrpc":"2.0","method":"second"}`} {
+		got, err := reader.readMessage()
+		if err != nil {
+			t.Fatalf("readMessage() failed: %v", err)
+		}
+		if string(got) != want {
+			t.Errorf("readMessage() = %q, want %q", got, want)
+		}
+	}
+	if _, err := reader.readMessage(); err != io.EOF {
+		t.Errorf("readMessage() at end of stream = %v, want io.EOF", err)
+	}
+}
+
+func TestWriteFraming(t *testing.T) {
+	framed := "Content-Length: 2\r\n\r\n{}"
+	for _, tt := range []struct {
+		framing  Framing
+		clientIn string // a message read before writing, if any
+		want     string
+	}{
+		{FramingAuto, "", "{}\n"},
+		{FramingAuto, "Content-Length: 2\r\n\r\n{}", framed},
+		{FramingNewline, "Content-Length: 2\r\n\r\n{}", "{}\n"},
+		// A server that writes first still frames its output
+		{FramingContentLength, "", framed},
+	} {
+		var out bytes.Buffer
+		conn := newTestConn(tt.clientIn, &out, tt.framing)
+		if tt.clientIn != "" {
+			if _, err := conn.readMessage(); err != nil {
+				t.Fatalf("readMessage() failed: %v", err)
+			}
+		}
+		if err := conn.writeMessage([]byte("{}")); err != nil {
+			t.Fatalf("writeMessage() failed: %v", err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s framing after reading %q wrote %q, want %q", tt.framing, tt.clientIn, out.String(), tt.want)
+		}
+	}
+}
+
+func TestReadFramedHeaders(t *testing.T) {
+	input := "content-length: 2\r\nContent-Type: application/json\r\n\r\n{}"
+	got, err := newTestConn(input, io.Discard, FramingAuto).readMessage()
+	if err != nil {
+		t.Fatalf("readMessage() failed: %v", err)
+	}
+	if string(got) != "{}" {
+		t.Errorf("readMessage() = %q, want {}", got)
+	}
+
+	for _, input := range []string{
+		"Content-Length: abc\r\n\r\n{}",
+		"Content-Length: -1\r\n\r\n{}",
+	} {
+		if _, err := newTestConn(input, io.Discard, FramingAuto).readMessage(); err == nil {
+			t.Errorf("readMessage(%q) succeeded, want an invalid header error", input)
+		}
+	}
+
+	// The body is shorter than announced
+	_, err = newTestConn("Content-Length: 10\r\n\r\n{}", io.Discard, FramingAuto).readMessage()
+	if err == nil || !strings.Contains(err.Error(), io.ErrUnexpectedEOF.Error()) {
+		t.Errorf("readMessage() of a truncated body = %v, want an unexpected EOF", err)
+	}
+}
+
+func TestReadLargeMessage(t *testing.T) {
+	// Far larger than the bufio buffer
+	message := `{"data":"` + strings.Repeat("x", 1<<20) + `"}`
+	for _, input := range []string{
+		message + "\n",
+		fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(message), message),
+	} {
+		got, err := newTestConn(input, io.Discard, FramingAuto).readMessage()
+		if err != nil {
+			t.Fatalf("readMessage() of a 1 MB message failed: %v", err)
+		}
+		if string(got) != message {
+			t.Errorf("readMessage() returned %d bytes, want %d", len(got), len(message))
+		}
+	}
+}
+
+func TestReadAtEOF(t *testing.T) {
+	conn := newTestConn(`{"jsonrpc":"2.0","method":"ping"}`, io.Discard, FramingAuto)
+	got, err := conn.readMessage()
+	if err != nil {
+		t.Fatalf("readMessage() of a message without a trailing newline failed: %v", err)
+	}
+	if string(got) != `{"jsonrpc":"2.0","method":"ping"}` {
+		t.Errorf("readMessage() = %q, want the whole message", got)
+	}
+	if _, err := conn.readMessage(); err != io.EOF {
+		t.Errorf("readMessage() after the last message = %v, want io.EOF", err)
+	}
+
+	for _, input := range []string{"", "\n\n"} {
+		if _, err := newTestConn(input, io.Discard, FramingAuto).readMessage(); err != io.EOF {
+			t.Errorf("readMessage() of %q = %v, want io.EOF", input, err)
+		}
+	}
+}
+
+func TestReadOversizedMessage(t *testing.T) {
+	huge := strings.Repeat("x", maxMessageSize+1)
+	for _, input := range []string{
+		huge + "\n{}\n",
+		fmt.Sprintf("Content-Length: %d\r\n\r\n%s{}\n", len(huge), huge),
+	} {
+		conn := newTestConn(input, io.Discard, FramingAuto)
+		if _, err := conn.readMessage(); err != errMessageTooLarge {
+			t.Errorf("readMessage() of an oversized message = %v, want errMessageTooLarge", err)
+		}
+		// The oversized message is skipped, not left in the stream
+		got, err := conn.readMessage()
+		if err != nil || string(got) != "{}" {
+			t.Errorf("readMessage() after an oversized message = %q, %v; want the next message", got, err)
+		}
+	}
+}
+
+// flushCloser records whether it was flushed and closed.
+type flushCloser struct {
+	bytes.Buffer
+	flushed, closed bool
+}
+
+func (f *flushCloser) Flush() error { f.flushed = true; return nil }
+func (f *flushCloser) Close() error { f.closed = true; return nil }
+
+func TestCloseConnection(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	out := &flushCloser{}
+	conn, _ := NewIOTransport(r, out, FramingAuto).Connect(context.Background())
+
+	// A read blocked on the stream is interrupted by Close
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(context.Background())
+		readErr <- err
+	}()
+
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if !out.flushed || !out.closed {
+		t.Errorf("Close() flushed = %v, closed = %v; want the writer flushed and closed", out.flushed, out.closed)
+	}
+	if err := <-readErr; err != errConnClosed {
+		t.Errorf("blocked Read() = %v, want errConnClosed", err)
+	}
+	if _, err := r.Read(make([]byte, 1)); err == nil {
+		t.Error("input stream still open after Close()")
+	}
+
+	if _, err := conn.Read(context.Background()); err != errConnClosed {
+		t.Errorf("Read() after Close() = %v, want errConnClosed", err)
+	}
+	if err := conn.Write(context.Background(), &jsonrpc.Request{Method: "ping"}); err != errConnClosed {
+		t.Errorf("Write() after Close() = %v, want errConnClosed", err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Errorf("second Close() = %v, want nil", err)
+	}
+}